@@ -0,0 +1,339 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/TheSlipper/isa/evolalg"
+	"github.com/TheSlipper/isa/evolalg/gp"
+	chart "github.com/wcharczuk/go-chart/v2"
+)
+
+// epochAxis creates the x axis for a chart that spans all of the epochs in the history.
+func epochAxis(hist []evolalg.EpochData) chart.XAxis {
+	epochs := len(hist) - 1
+	ticker := len(hist) / 20
+	epochTicks := []chart.Tick{}
+	i := 0
+	for ; i < len(hist); i++ {
+		if len(hist) < 20 {
+			epochTicks = append(epochTicks, chart.Tick{Value: float64(i), Label: strconv.Itoa(i)})
+		} else if i%ticker == 0 {
+			epochTicks = append(epochTicks, chart.Tick{Value: float64(i), Label: strconv.Itoa(i)})
+		}
+	}
+	if epochTicks[len(epochTicks)-1].Value != float64(i) {
+		epochTicks = append(epochTicks, chart.Tick{Value: float64(epochs),
+			Label: strconv.Itoa(epochs)})
+	}
+
+	return chart.XAxis{
+		Name: "Epoka",
+		Range: &chart.ContinuousRange{
+			Min: 0.0,
+			Max: float64(epochs),
+		},
+		Ticks: epochTicks,
+	}
+}
+
+// epochSeries creates a line of the chart from the values of each epoch.
+func epochSeries(name string, color int, vals []float64) chart.ContinuousSeries {
+	epochsArr := make([]float64, len(vals))
+	for i := range epochsArr {
+		epochsArr[i] = float64(i)
+	}
+
+	return chart.ContinuousSeries{
+		Name: name,
+		Style: chart.Style{
+			StrokeColor: chart.GetDefaultColor(color).WithAlpha(64),
+			StrokeWidth: 3.5,
+		},
+		XValues: epochsArr,
+		YValues: vals,
+	}
+}
+
+// renderChart renders the graph to an svg file at the given path.
+func renderChart(path string, graph chart.Chart) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	os.Remove(path)
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return graph.Render(chart.SVG, f)
+}
+
+// restartMarkers creates vertical lines spanning from lo to hi in the epochs in which the
+// population was restarted.
+func restartMarkers(hist []evolalg.EpochData, lo, hi float64) (markers []chart.Series) {
+	for i := range hist {
+		if hist[i].Restart == "" {
+			continue
+		}
+		markers = append(markers, chart.ContinuousSeries{
+			Name: "restart " + hist[i].Restart,
+			Style: chart.Style{
+				StrokeColor:     chart.ColorAlternateGray,
+				StrokeWidth:     1.5,
+				StrokeDashArray: []float64{5.0, 5.0},
+			},
+			XValues: []float64{float64(i), float64(i)},
+			YValues: []float64{lo, hi},
+		})
+	}
+	return
+}
+
+// fitnessChart creates the fmax, favg, fmin graph with the restarts marked on it.
+func fitnessChart(hist []evolalg.EpochData) chart.Chart {
+	fmax, favg, fmin := make([]float64, len(hist)), make([]float64, len(hist)), make([]float64, len(hist))
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for i := 0; i < len(hist); i++ {
+		fmax[i] = hist[i].FMax
+		favg[i] = hist[i].FAVG
+		fmin[i] = hist[i].FMin
+		lo, hi = math.Min(lo, fmin[i]), math.Max(hi, fmax[i])
+	}
+
+	return chart.Chart{
+		XAxis: epochAxis(hist),
+		YAxis: chart.YAxis{
+			Name: "f(x)",
+		},
+		Series: append([]chart.Series{
+			epochSeries("fmax", 0, fmax),
+			epochSeries("favg", 1, favg),
+			epochSeries("fmin", 2, fmin),
+		}, restartMarkers(hist, lo, hi)...),
+	}
+}
+
+// diversityChart creates the graph of the population's diversity. All of the metrics are
+// normalized to the <0, 1> range so that they can share the y axis.
+func diversityChart(hist []evolalg.EpochData) chart.Chart {
+	hamming, entropy, unique := make([]float64, len(hist)), make([]float64, len(hist)), make([]float64, len(hist))
+	for i := 0; i < len(hist); i++ {
+		div := hist[i].Diversity
+		if l := len(div.LocusEntropy); l > 0 {
+			hamming[i] = div.MeanHamming / float64(l)
+		}
+		entropy[i] = div.MeanEntropy
+		if N := len(hist[i].PopulationBytes); N > 0 {
+			unique[i] = float64(div.UniqueGenotypes) / float64(N)
+		}
+	}
+
+	return chart.Chart{
+		XAxis: epochAxis(hist),
+		YAxis: chart.YAxis{
+			Name: "Różnorodność",
+			Range: &chart.ContinuousRange{
+				Min: 0.0,
+				Max: 1.0,
+			},
+		},
+		Series: append([]chart.Series{
+			epochSeries("hamming/l", 3, hamming),
+			epochSeries("entropia", 4, entropy),
+			epochSeries("unikalne/N", 0, unique),
+		}, restartMarkers(hist, 0, 1)...),
+	}
+}
+
+// ratesChart creates the graph of the crossover (left axis) and mutation (right axis) probabilities
+// used in every epoch, or of the mean crossover rate and scale factor of differential evolution.
+// The initial population has no probabilities so it's shown with the ones of the first epoch.
+func ratesChart(hist []evolalg.EpochData) chart.Chart {
+	de := deRates(hist)
+	cps, mps := make([]float64, len(hist)), make([]float64, len(hist))
+	for i := 1; i < len(hist); i++ {
+		cps[i], mps[i] = hist[i].CP, hist[i].MP
+		if de {
+			cps[i], mps[i] = hist[i].CR, hist[i].F
+		}
+	}
+	if len(hist) > 1 {
+		cps[0], mps[0] = cps[1], mps[1]
+	}
+	cpName, mpName := "Pk", "Pm"
+	if de {
+		cpName, mpName = "CR", "F"
+	}
+	mpSeries := epochSeries(mpName, 2, mps)
+	mpSeries.YAxis = chart.YAxisSecondary
+
+	return chart.Chart{
+		XAxis: epochAxis(hist),
+		YAxis: chart.YAxis{
+			Name: cpName,
+		},
+		YAxisSecondary: chart.YAxis{
+			Name: mpName,
+		},
+		Series: []chart.Series{
+			epochSeries(cpName, 0, cps),
+			mpSeries,
+		},
+	}
+}
+
+// deRates checks if the history comes from differential evolution, which saves its mean crossover
+// rates and scale factors instead of the probabilities of the genetic algorithm.
+func deRates(hist []evolalg.EpochData) bool {
+	for _, ed := range hist {
+		if ed.F != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// functionSeries creates a line of the chart from the values of f sampled on the <a, b> set.
+func functionSeries(f func(x float64) float64, a, b float64) chart.ContinuousSeries {
+	samples := 1000
+	xs, ys := make([]float64, samples+1), make([]float64, samples+1)
+	for i := 0; i <= samples; i++ {
+		xs[i] = a + (b-a)*float64(i)/float64(samples)
+		ys[i] = f(xs[i])
+	}
+
+	return chart.ContinuousSeries{
+		Name: "f(x)",
+		Style: chart.Style{
+			StrokeColor: chart.GetDefaultColor(0).WithAlpha(64),
+			StrokeWidth: 1.5,
+		},
+		XValues: xs,
+		YValues: ys,
+	}
+}
+
+// nicheChart creates the graph of f(x) on the <a, b> set with the final population and the distinct
+// optima marked on it.
+func nicheChart(f func(x float64) float64, a, b float64, last evolalg.EpochData) chart.Chart {
+	optX, optY := make([]float64, len(last.Optima)), make([]float64, len(last.Optima))
+	for i, o := range last.Optima {
+		optX[i], optY[i] = o.X, o.Grade
+	}
+
+	return chart.Chart{
+		XAxis: chart.XAxis{
+			Name: "x",
+		},
+		YAxis: chart.YAxis{
+			Name: "f(x)",
+		},
+		Series: []chart.Series{
+			functionSeries(f, a, b),
+			chart.ContinuousSeries{
+				Name: "populacja",
+				Style: chart.Style{
+					StrokeWidth: chart.Disabled,
+					DotWidth:    3,
+					DotColor:    chart.GetDefaultColor(1),
+				},
+				XValues: last.PopulationF64,
+				YValues: last.Grades,
+			},
+			chart.ContinuousSeries{
+				Name: "nisze",
+				Style: chart.Style{
+					StrokeWidth: chart.Disabled,
+					DotWidth:    7,
+					DotColor:    chart.GetDefaultColor(2),
+				},
+				XValues: optX,
+				YValues: optY,
+			},
+		},
+	}
+}
+
+// swarmChart creates a chart of the positions of the particles of a swarm in a single epoch over
+// the function f on the <a, b> set. The y axis is fixed so that the frames of all epochs can be
+// animated.
+func swarmChart(f func(x float64) float64, a, b float64, ed evolalg.EpochData) chart.Chart {
+	curve := functionSeries(f, a, b)
+	yMin, yMax := math.Inf(1), math.Inf(-1)
+	for _, y := range curve.YValues {
+		yMin, yMax = math.Min(yMin, y), math.Max(yMax, y)
+	}
+
+	return chart.Chart{
+		XAxis: chart.XAxis{
+			Name:  "x",
+			Range: &chart.ContinuousRange{Min: a, Max: b},
+		},
+		YAxis: chart.YAxis{
+			Name:  "f(x)",
+			Range: &chart.ContinuousRange{Min: yMin, Max: yMax},
+		},
+		Series: []chart.Series{
+			curve,
+			chart.ContinuousSeries{
+				Name: "cząstki",
+				Style: chart.Style{
+					StrokeWidth: chart.Disabled,
+					DotWidth:    4,
+					DotColor:    chart.GetDefaultColor(1),
+				},
+				XValues: ed.PopulationF64,
+				YValues: ed.Grades,
+			},
+			chart.ContinuousSeries{
+				Name: "najlepsza",
+				Style: chart.Style{
+					StrokeWidth: chart.Disabled,
+					DotWidth:    7,
+					DotColor:    chart.GetDefaultColor(2),
+				},
+				XValues: []float64{ed.Elite},
+				YValues: []float64{ed.EliteGrade},
+			},
+		},
+	}
+}
+
+// regressionChart creates a chart of the best expression of the genetic programming against the
+// samples it was fitted to.
+func regressionChart(samples []gp.Sample, best *gp.Node) chart.Chart {
+	sx, sy := make([]float64, len(samples)), make([]float64, len(samples))
+	a, b := math.Inf(1), math.Inf(-1)
+	for i, smp := range samples {
+		sx[i], sy[i] = smp.X, smp.Y
+		a, b = math.Min(a, smp.X), math.Max(b, smp.X)
+	}
+	curve := functionSeries(best.Eval, a, b)
+	curve.Name = best.String()
+
+	return chart.Chart{
+		XAxis: chart.XAxis{
+			Name: "x",
+		},
+		YAxis: chart.YAxis{
+			Name: "y",
+		},
+		Series: []chart.Series{
+			curve,
+			chart.ContinuousSeries{
+				Name: "próbki",
+				Style: chart.Style{
+					StrokeWidth: chart.Disabled,
+					DotWidth:    4,
+					DotColor:    chart.GetDefaultColor(1),
+				},
+				XValues: sx,
+				YValues: sy,
+			},
+		},
+	}
+}
@@ -0,0 +1,307 @@
+// Program isa-cli uruchamia solvery z rejestru pakietu evolalg na funkcjach testowych pakietu
+// benchmarks (domyślnie na funkcji z zadania laboratoryjnego) lub na jego problemach binarnych z
+// linii poleceń. Flagi algorytmu są tworzone na podstawie schematu jego parametrów.
+//
+// Flaga -dot zapisuje rodowód ostatecznej elity algorytmu genetycznego w języku DOT, np.
+//
+//	isa-cli ga -epoki 20 -dot elita.dot && dot -Tsvg elita.dot > elita.svg
+//
+// Flaga -polecenie zastępuje funkcję zewnętrznym programem oceniającym punkty przedziału <a, b>, który
+// wymienia z solverem linie JSON na standardowym wejściu i wyjściu (zob. pakiet evolalg/external), np.
+//
+//	isa-cli ga -a 0 -b 10 -polecenie "python3 model.py" -procesy 4 -limit-czasu 5s
+//
+// Użycie:
+//
+//	isa-cli lista
+//	isa-cli <algorytm> [flagi]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/TheSlipper/isa/evolalg"
+	"github.com/TheSlipper/isa/evolalg/benchmarks"
+	"github.com/TheSlipper/isa/evolalg/external"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "użycie: isa-cli <lista|"+strings.Join(names(), "|")+"> [flagi]")
+		os.Exit(2)
+	}
+	if os.Args[1] == "lista" {
+		printAlgorithms()
+		return
+	}
+	hist, jsonFormat, err := run(os.Args[1], os.Args[2:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	if jsonFormat {
+		err = json.NewEncoder(os.Stdout).Encode(hist)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+	printSummary(hist)
+}
+
+// names zwraca nazwy algorytmów z rejestru.
+func names() (res []string) {
+	for _, a := range evolalg.Algorithms() {
+		res = append(res, a.Name)
+	}
+	return
+}
+
+// run konfiguruje wybrany algorytm na podstawie flag i zwraca historię jego wykonania.
+func run(name string, args []string) (hist []evolalg.EpochData, jsonFormat bool, err error) {
+	alg, err := evolalg.AlgorithmByName(name)
+	if err != nil {
+		return nil, false, fmt.Errorf("nieznany algorytm %s", name)
+	}
+
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	a := fs.String("a", "", "dolna granica przedziału (puste - domyślna dla funkcji)")
+	b := fs.String("b", "", "górna granica przedziału (puste - domyślna dla funkcji)")
+	fnName := fs.String("funkcja", "lab", "funkcja testowa")
+	n := fs.Int("wymiar", 0, "liczba wymiarów (0 - domyślna dla funkcji)")
+	genotypeName := fs.String("genotyp", "", "problem binarny zastępujący funkcję ("+strings.Join(benchmarks.GenotypeNames, ", ")+")")
+	l := fs.Int("dlugosc", 16, "długość genotypu problemu binarnego")
+	k := fs.Int("blok", 4, "rozmiar bloku (royalroad, trap) lub liczba powiązań (nk)")
+	nkSeed := fs.Int64("ziarno-nk", 1, "ziarno tablic krajobrazu NK")
+	fs.BoolVar(&jsonFormat, "json", false, "wypisz całą historię w formacie JSON")
+	dotPath := fs.String("dot", "", "plik, do którego zostanie zapisany rodowód elity w formacie DOT (włącza genealogię)")
+	depth := fs.Int("glebokosc", 0, "liczba pokoleń przodków w rodowodzie (0 - wszystkie)")
+	noise := fs.Float64("szum", 0, "odchylenie standardowe szumu gaussowskiego dodawanego do ocen")
+	drift := fs.Float64("dryf", 0, "przesunięcie krajobrazu funkcji co okres jako ułamek szerokości przedziału")
+	period := fs.Int("okres-dryfu", 10, "liczba epok między przesunięciami krajobrazu")
+	command := fs.String("polecenie", "", "zewnętrzny program oceny zastępujący funkcję (argumenty rozdzielone spacjami)")
+	processes := fs.Int("procesy", 1, "liczba równolegle oceniających procesów zewnętrznego programu")
+	timeout := fs.Duration("limit-czasu", 0, "limit czasu jednej oceny zewnętrznego programu (0 - brak)")
+	for _, p := range alg.Schema() {
+		if p.Kind == evolalg.BoolParam {
+			fs.Bool(p.Name, false, p.Label)
+		} else {
+			fs.String(p.Name, p.Default, usage(p))
+		}
+	}
+	fs.Parse(args)
+
+	// Only the flags given explicitly are passed so that the solver uses the defaults of the schema
+	params := evolalg.Params{}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "a", "b", "json", "funkcja", "wymiar", "genotyp", "dlugosc", "blok", "ziarno-nk", "dot", "glebokosc", "szum", "dryf",
+			"okres-dryfu", "polecenie", "procesy", "limit-czasu":
+		default:
+			params[f.Name] = f.Value.String()
+		}
+	})
+	if *dotPath != "" {
+		if !hasParam(alg, "genealogia") {
+			return nil, jsonFormat, errors.New("algorytm " + name + " nie zapisuje genealogii osobników")
+		}
+		params["genealogia"] = "true"
+	}
+
+	var problem evolalg.Problem
+	if *genotypeName != "" {
+		problem.Name = fmt.Sprintf("%s/%d/%d/%d", *genotypeName, *l, *k, *nkSeed)
+		problem.Genotype, err = benchmarks.GenotypeByName(*genotypeName, *l, *k, *nkSeed)
+	} else {
+		problem, err = benchmarkProblem(*fnName, *n, *a, *b)
+	}
+	if err != nil {
+		return nil, jsonFormat, err
+	}
+	var ev *external.Evaluator
+	if *command != "" {
+		if *genotypeName != "" || *noise != 0 || *drift != 0 {
+			return nil, jsonFormat, errors.New("zewnętrznego programu oceny nie można łączyć z problemem binarnym, szumem ani dryfem")
+		} else if problem.Dim() != 1 {
+			return nil, jsonFormat, errors.New("zewnętrzny program ocenia tylko problemy jednowymiarowe")
+		}
+		ev, err = external.NewEvaluator(external.Config{Command: strings.Fields(*command), Processes: *processes,
+			Timeout: *timeout})
+		if err != nil {
+			return nil, jsonFormat, err
+		}
+		defer ev.Close()
+		problem = evolalg.Problem{Lower: problem.Lower, Upper: problem.Upper, Fitness: ev, Name: "zewnetrzny/" + *command}
+	}
+	if *noise != 0 || *drift != 0 {
+		if *genotypeName != "" {
+			return nil, jsonFormat, errors.New("szum i dryf są dostępne tylko dla funkcji testowych")
+		}
+		if *drift != 0 {
+			problem = benchmarks.WithDrift(problem, *drift, *period)
+		}
+		if *noise != 0 {
+			problem = benchmarks.WithNoise(problem, *noise)
+		}
+	}
+	s, err := alg.New(problem)
+	if err != nil {
+		return nil, jsonFormat, err
+	}
+	err = s.Configure(params)
+	if err != nil {
+		return nil, jsonFormat, err
+	}
+	hist, err = s.Run(context.Background())
+	if ev != nil && ev.Restarts() != 0 {
+		fmt.Fprintf(os.Stderr, "Procesy zewnętrznego programu uruchomiono ponownie %d razy.\n", ev.Restarts())
+	}
+	if err != nil || *dotPath == "" {
+		return hist, jsonFormat, err
+	}
+	return hist, jsonFormat, writeDOT(*dotPath, hist[len(hist)-1].Genealogy, *depth)
+}
+
+// hasParam sprawdza, czy algorytm ma parametr o podanej nazwie.
+func hasParam(alg evolalg.Algorithm, name string) bool {
+	for _, p := range alg.Params {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// writeDOT zapisuje rodowód elity z genealogii do pliku w formacie DOT.
+func writeDOT(path string, g *evolalg.Genealogy, depth int) error {
+	if g == nil {
+		return errors.New("brak genealogii w historii algorytmu")
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = g.WriteDOT(f, g.EliteID, depth)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		fmt.Fprintf(os.Stderr, "Zapisano rodowód elity (%d osobników) do %s.\n", len(g.EliteAncestry(depth)), path)
+	}
+	return err
+}
+
+// benchmarkProblem tworzy problem maksymalizacji funkcji testowej o podanej nazwie. Zerowa liczba wymiarów i
+// puste granice oznaczają wartości domyślne funkcji.
+func benchmarkProblem(name string, n int, aStr, bStr string) (p evolalg.Problem, err error) {
+	fn, err := benchmarks.ByName(name)
+	if err != nil {
+		return
+	}
+	if n == 0 {
+		n = fn.DefaultDim
+	}
+	a, b := fn.Lower, fn.Upper
+	if aStr != "" {
+		_, err = fmt.Sscan(aStr, &a)
+		if err != nil {
+			return
+		}
+	}
+	if bStr != "" {
+		_, err = fmt.Sscan(bStr, &b)
+		if err != nil {
+			return
+		}
+	}
+	return fn.ProblemIn(n, a, b)
+}
+
+// usage zwraca opis flagi parametru wraz z dozwolonymi wartościami i znaczeniem pustej wartości.
+func usage(p evolalg.Param) string {
+	desc := p.Label
+	if len(p.Choices) > 0 {
+		vals := make([]string, len(p.Choices))
+		for i, c := range p.Choices {
+			vals[i] = c.Value
+		}
+		desc += " (" + strings.Join(vals, ", ") + ")"
+	}
+	if p.Hint != "" {
+		desc += " (puste - " + p.Hint + ")"
+	}
+	return desc
+}
+
+// printAlgorithms wypisuje algorytmy z rejestru wraz z ich parametrami.
+func printAlgorithms() {
+	for _, a := range evolalg.Algorithms() {
+		fmt.Printf("%s - %s\n", a.Name, a.Label)
+		for _, p := range a.Params {
+			fmt.Printf("\t-%-16s %-7s %s\n", p.Name, p.Kind, usage(p))
+		}
+	}
+	fmt.Println("Funkcje testowe (-funkcja):")
+	for _, f := range benchmarks.All() {
+		fmt.Printf("\t%-16s %dD", f.Name, f.DefaultDim)
+		if f.MaxDim != f.MinDim {
+			fmt.Printf(" (%dD+)", f.MinDim)
+		}
+		fmt.Printf(" <%g, %g> %s\n", f.Lower, f.Upper, f.Label)
+	}
+	fmt.Println("Problemy binarne (-genotyp):")
+	fmt.Printf("\t%s\n", strings.Join(benchmarks.GenotypeNames, ", "))
+	fmt.Println("Parametry wspólne:")
+	for _, p := range evolalg.CommonParams {
+		fmt.Printf("\t-%-16s %-7s %s\n", p.Name, p.Kind, usage(p))
+	}
+}
+
+// printSummary wypisuje tabelę z najważniejszymi danymi każdej epoki i ostateczną elitę.
+func printSummary(hist []evolalg.EpochData) {
+	fmt.Printf("%6s %12s %12s %12s %12s %10s\n", "epoka", "fmax", "favg", "fmin", "elita", "obliczenia")
+	for i, ed := range hist {
+		fmt.Printf("%6d %12.6f %12.6f %12.6f %12.6f %10d\n", i, ed.FMax, ed.FAVG, ed.FMin, ed.Elite, ed.Evaluations)
+	}
+	last := hist[len(hist)-1]
+	var elite interface{} = last.Elite
+	if last.EliteVec != nil {
+		elite = last.EliteVec
+	}
+	fmt.Printf("Elita %v (dopasowanie %f) znaleziona w epoce %d.\n", elite, last.EliteFit, last.EliteEpoch)
+	var changes []string
+	for i, ed := range hist {
+		if ed.Change {
+			changes = append(changes, strconv.Itoa(i))
+		}
+	}
+	if changes != nil {
+		fmt.Printf("Zmiany środowiska wykryto w epokach: %s.\n", strings.Join(changes, ", "))
+	}
+	if last.Saved != 0 {
+		fmt.Printf("Model zastępczy zaoszczędził %d obliczeń funkcji oceny (wykonano %d).\n", last.Saved, last.Evaluations)
+	}
+	if last.Failed != 0 {
+		fmt.Printf("Nieudane obliczenia funkcji oceny: %d.\n", last.Failed)
+	}
+	if last.Variance != 0 {
+		fmt.Printf("Średnia wariancja próbek oceny w ostatniej epoce: %g.\n", last.Variance)
+	}
+	if ref := last.Reference; ref != nil {
+		fmt.Printf("Algorytm znalazł %g, prawdziwe optimum %g (x = %g, %d punktów). Luka optymalności: %g.\n", ref.Best(),
+			ref.Grade, ref.X, ref.Points, ref.Gap)
+		if ref.HitEpoch >= 0 {
+			fmt.Printf("Optimum zostało znalezione po raz pierwszy w epoce %d.\n", ref.HitEpoch)
+		} else {
+			fmt.Println("Optimum nie zostało znalezione.")
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Program isa-stats analizuje wyniki powtarzanych uruchomień zapisane przez isa-sweep. Dla każdej
+// konfiguracji na każdej funkcji wypisuje statystyki opisowe najlepszych ocen (średnią z przedziałem
+// ufności, medianę, odchylenie standardowe, najlepszy i najgorszy wynik oraz odsetek sukcesów) i
+// porównuje ją testem Manna-Whitneya z konfiguracją o najwyższej medianie. Na koniec porównuje
+// wszystkie konfiguracje testem Friedmana (blokami są funkcje z numerami powtórzeń), a dwie najlepsze
+// z nich testem Wilcoxona dla par z tych samych bloków.
+//
+// Użycie:
+//
+//	isa-stats [flagi] wyniki.csv [wyniki2.json ...]
+//
+// Wyniki z kilku plików (np. różnych algorytmów) są rozróżniane nazwami plików.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TheSlipper/isa/evolalg/stats"
+	"github.com/TheSlipper/isa/evolalg/sweep"
+)
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// run wczytuje pliki wyników i wypisuje ich analizę.
+func run(args []string) error {
+	fs := flag.NewFlagSet("isa-stats", flag.ExitOnError)
+	targetStr := fs.String("cel", "", "ocena uznawana za sukces (puste - bez odsetka sukcesów)")
+	confidence := fs.Float64("ufnosc", 0.95, "poziom ufności przedziałów średnich")
+	alpha := fs.Float64("alfa", 0.05, "poziom istotności testów")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return errors.New("użycie: isa-stats [flagi] wyniki.csv [wyniki2.json ...]")
+	}
+	var target float64
+	if *targetStr != "" {
+		var err error
+		target, err = strconv.ParseFloat(*targetStr, 64)
+		if err != nil {
+			return errors.New("cel musi być liczbą")
+		}
+	}
+
+	var groups []stats.Group
+	for _, path := range fs.Args() {
+		g, err := readGroups(path, fs.NArg() > 1)
+		if err != nil {
+			return err
+		}
+		groups = append(groups, g...)
+	}
+
+	// The groups are printed function by function in the order of their first appearance
+	var functions []string
+	byFunction := make(map[string][]stats.Group)
+	for _, g := range groups {
+		fn := fmt.Sprintf("%s (%dD)", g.Function, g.Dim)
+		if byFunction[fn] == nil {
+			functions = append(functions, fn)
+		}
+		byFunction[fn] = append(byFunction[fn], g)
+	}
+	for _, fn := range functions {
+		err := printFunction(fn, byFunction[fn], *targetStr != "", target, *confidence, *alpha)
+		if err != nil {
+			return err
+		}
+	}
+	return printComparison(groups, *alpha)
+}
+
+// readGroups wczytuje wyniki z pliku (w formacie JSON, jeśli ma rozszerzenie .json lub .jsonl) i dzieli
+// je na konfiguracje. Jeśli labelled jest ustawione, konfiguracje są oznaczane nazwą pliku.
+func readGroups(path string, labelled bool) ([]stats.Group, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	format := sweep.CSV
+	ext := filepath.Ext(path)
+	if ext == ".json" || ext == ".jsonl" {
+		format = sweep.JSON
+	}
+	results, err := sweep.ReadResults(f, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err.Error())
+	} else if len(results) == 0 {
+		return nil, fmt.Errorf("%s: brak wyników", path)
+	}
+	label := ""
+	if labelled {
+		label = strings.TrimSuffix(filepath.Base(path), ext)
+	}
+	return stats.GroupResults(label, results), nil
+}
+
+// printFunction wypisuje statystyki konfiguracji na jednej funkcji wraz z wartościami p testu
+// Manna-Whitneya względem konfiguracji o najwyższej medianie (oznaczonej "ref").
+func printFunction(fn string, groups []stats.Group, hasTarget bool, target, confidence, alpha float64) error {
+	summaries := make([]stats.Summary, len(groups))
+	ref := 0
+	for i, g := range groups {
+		s, err := stats.Summarize(g.Best(), confidence)
+		if err != nil {
+			return err
+		}
+		summaries[i] = s
+		if s.Median > summaries[ref].Median || (s.Median == summaries[ref].Median && s.Mean > summaries[ref].Mean) {
+			ref = i
+		}
+	}
+
+	fmt.Printf("Funkcja %s\n", fn)
+	fmt.Printf("%-30s %4s %12s %12s %12s %12s %12s %27s %7s %9s\n", "konfiguracja", "n", "średnia", "mediana",
+		"odch. std.", "najlepszy", "najgorszy", fmt.Sprintf("przedział ufności %g%%", confidence*100), "sukces", "p (M-W)")
+	for i, g := range groups {
+		s := summaries[i]
+		success := "-"
+		if hasTarget {
+			success = fmt.Sprintf("%.0f%%", 100*stats.SuccessRate(g.Best(), target))
+		}
+		p := "ref"
+		if i != ref {
+			res, err := stats.MannWhitney(g.Best(), groups[ref].Best())
+			if err != nil {
+				return err
+			}
+			p = fmt.Sprintf("%.4f", res.P)
+			if res.P < alpha {
+				p += "*"
+			}
+		}
+		fmt.Printf("%-30s %4d %12.6f %12.6f %12.6f %12.6f %12.6f %13.6f - %11.6f %7s %9s\n", g.Name(), s.N,
+			s.Mean, s.Median, s.SD, s.Best, s.Worst, s.CILow, s.CIHigh, success, p)
+	}
+	fmt.Printf("* - istotna różnica względem ref (p < %g)\n\n", alpha)
+	return nil
+}
+
+// printComparison wypisuje średnie rangi testu Friedmana wszystkich konfiguracji i wynik testu
+// Wilcoxona dwóch najlepszych z nich.
+func printComparison(groups []stats.Group, alpha float64) error {
+	names, blocks, err := stats.Blocks(groups)
+	if err != nil || len(names) < 2 || len(blocks) < 2 {
+		fmt.Println("Za mało wspólnych bloków (funkcja, powtórzenie) do porównania konfiguracji.")
+		return nil
+	}
+	res, err := stats.Friedman(blocks)
+	if err != nil {
+		return err
+	}
+	order := make([]int, len(names))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return res.Ranks[order[i]] < res.Ranks[order[j]] })
+
+	fmt.Printf("Test Friedmana (%d bloków): χ² = %.4f, p = %.4g%s\n", len(blocks), res.Statistic, res.P, significance(res.P, alpha))
+	fmt.Printf("%-30s %12s\n", "konfiguracja", "średnia ranga")
+	for _, j := range order {
+		fmt.Printf("%-30s %12.3f\n", names[j], res.Ranks[j])
+	}
+
+	first, second := order[0], order[1]
+	a, b := make([]float64, len(blocks)), make([]float64, len(blocks))
+	for i, block := range blocks {
+		a[i], b[i] = block[first], block[second]
+	}
+	w, err := stats.Wilcoxon(a, b)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Test Wilcoxona %s vs %s: W+ = %g, p = %.4g%s\n", names[first], names[second], w.Statistic, w.P,
+		significance(w.P, alpha))
+	return nil
+}
+
+// significance zwraca opis istotności wartości p.
+func significance(p, alpha float64) string {
+	if p < alpha {
+		return " (różnica istotna)"
+	}
+	return " (brak istotnej różnicy)"
+}
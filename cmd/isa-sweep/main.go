@@ -0,0 +1,203 @@
+// Program isa-sweep przeszukuje siatkę parametrów algorytmu z rejestru pakietu evolalg na funkcjach
+// testowych. Każda kombinacja wartości parametrów jest uruchamiana zadaną liczbę razy na puli wątków,
+// a wyniki kolejnych uruchomień są dopisywane na bieżąco w formacie CSV lub JSON (jeden obiekt w
+// wierszu). Przerwane przeszukiwanie można wznowić flagą -wznow.
+//
+// Użycie:
+//
+//	isa-sweep [-spec plik.json] [-p nazwa=wartości]... [flagi]
+//
+// Wartości parametru to lista oddzielona przecinkami, której elementami są pojedyncze wartości lub
+// przedziały od:do:krok, np. -p Pm=0.0001,0.0005:0.01:0.0005. Plik labs.json zawiera siatkę z
+// laboratorium.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/TheSlipper/isa/evolalg/sweep"
+)
+
+// paramFlags zbiera wartości powtarzanej flagi -p.
+type paramFlags map[string]string
+
+// String zwraca wartości flagi.
+func (p paramFlags) String() string {
+	var parts []string
+	for name, vals := range p {
+		parts = append(parts, name+"="+vals)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Set dodaje wartości parametru w postaci nazwa=wartości.
+func (p paramFlags) Set(s string) error {
+	i := strings.Index(s, "=")
+	if i <= 0 {
+		return errors.New("parametr musi mieć postać nazwa=wartości")
+	}
+	p[s[:i]] = s[i+1:]
+	return nil
+}
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// run odczytuje specyfikację przeszukiwania z flag i pliku, a następnie je wykonuje.
+func run(args []string) error {
+	fs := flag.NewFlagSet("isa-sweep", flag.ExitOnError)
+	specFile := fs.String("spec", "", "plik JSON ze specyfikacją przeszukiwania (flagi nadpisują jego wartości)")
+	alg := fs.String("algorytm", "ga", "algorytm z rejestru")
+	fns := fs.String("funkcje", "lab", "funkcje testowe oddzielone przecinkami")
+	dim := fs.Int("wymiar", 0, "liczba wymiarów (0 - domyślna dla funkcji)")
+	reps := fs.Int("powtorzenia", 1, "liczba uruchomień każdej konfiguracji")
+	seed := fs.Int64("ziarno", 1, "ziarno pierwszego uruchomienia (kolejne używają następnych)")
+	workers := fs.Int("watki", 0, "liczba wątków (0 - liczba procesorów)")
+	formatName := fs.String("format", "csv", "format wyników (csv, json)")
+	output := fs.String("wyjscie", "", "plik wyników (puste - standardowe wyjście)")
+	resume := fs.Bool("wznow", false, "pomiń uruchomienia zapisane już w pliku wyników i dopisz pozostałe")
+	params := paramFlags{}
+	fs.Var(params, "p", "wartości parametru algorytmu w postaci nazwa=wartości (można powtarzać)")
+	fs.Parse(args)
+
+	var spec sweep.Spec
+	if *specFile != "" {
+		data, err := ioutil.ReadFile(*specFile)
+		if err != nil {
+			return err
+		}
+		err = json.Unmarshal(data, &spec)
+		if err != nil {
+			return fmt.Errorf("%s: %s", *specFile, err.Error())
+		}
+	}
+	// The flags given explicitly (or all of them without a spec file) override the spec
+	set := func(f *flag.Flag) {
+		switch f.Name {
+		case "algorytm":
+			spec.Algorithm = *alg
+		case "funkcje":
+			spec.Functions = strings.Split(*fns, ",")
+		case "wymiar":
+			spec.Dim = *dim
+		case "powtorzenia":
+			spec.Repetitions = *reps
+		case "ziarno":
+			spec.Seed = *seed
+		}
+	}
+	if *specFile == "" {
+		fs.VisitAll(set)
+	} else {
+		fs.Visit(set)
+	}
+	if spec.Params == nil {
+		spec.Params = map[string]string{}
+	}
+	for name, vals := range params {
+		spec.Params[name] = vals
+	}
+
+	format, err := sweep.FormatByName(*formatName)
+	if err != nil {
+		return err
+	}
+	configs, err := spec.Configs()
+	if err != nil {
+		return err
+	}
+	out, done, err := openOutput(*output, format, *resume)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	w, err := sweep.NewWriter(out, format, spec, !hasData(*output, *resume))
+	if err != nil {
+		return err
+	}
+
+	if spec.Repetitions <= 0 {
+		spec.Repetitions = 1
+	}
+	total := len(configs) * spec.Repetitions
+	finished := len(done)
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		fmt.Fprintln(os.Stderr, "\nprzerywanie - wznów flagą -wznow")
+		stop()
+	}()
+	return sweep.Run(ctx, spec, *workers, done, func(r sweep.Result) error {
+		finished++
+		fmt.Fprintf(os.Stderr, "\r%d/%d", finished, total)
+		if finished == total {
+			fmt.Fprintln(os.Stderr)
+		}
+		return w.Write(r)
+	})
+}
+
+// openOutput otwiera plik wyników. Przy wznawianiu zwraca też klucze zapisanych w nim uruchomień, a
+// nowe wyniki są dopisywane na jego końcu.
+func openOutput(path string, format sweep.Format, resume bool) (out io.WriteCloser, done map[string]bool, err error) {
+	if path == "" {
+		if resume {
+			return nil, nil, errors.New("wznowienie wymaga pliku wyników")
+		}
+		return os.Stdout, nil, nil
+	}
+	if !resume {
+		f, err := os.Create(path)
+		return f, nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, nil, err
+	}
+	done, err = sweep.ReadDone(f, format)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	// A line cut off by the interruption is ended so that the appended results start in a new one
+	info, err := f.Stat()
+	if err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		_, err = f.ReadAt(last, info.Size()-1)
+		if err == nil && last[0] != '\n' {
+			_, err = f.Write([]byte("\n"))
+		}
+	}
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, done, nil
+}
+
+// hasData sprawdza, czy wznawiany plik wyników nie jest pusty (i zawiera już nagłówek).
+func hasData(path string, resume bool) bool {
+	if !resume || path == "" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.Size() > 0
+}
@@ -0,0 +1,111 @@
+// Program isa-tune dobiera parametry algorytmu z rejestru pakietu evolalg (metaoptymalizacja).
+// Kandydaci są porównywani na wspólnych instancjach (funkcja testowa z ziarnem) w ramach budżetu
+// uruchomień, a wynikiem jest ranking konfiguracji z przedziałami ufności średnich i wartościami p
+// różnic względem najlepszej z nich.
+//
+// Użycie:
+//
+//	isa-tune [-metoda random|halving|race|metaga] -p nazwa=dziedzina... [-s nazwa=wartość...] [flagi]
+//
+// Dziedzina parametru to przedział od:do (liczb całkowitych, jeśli obie granice są całkowite) albo
+// lista wartości, np. -p N=10:100 -p Pk=0.75:1 -p zastepowanie=generational,ss-worst.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/TheSlipper/isa/evolalg/tune"
+)
+
+// pairFlags zbiera wartości powtarzanej flagi w postaci nazwa=wartość.
+type pairFlags map[string]string
+
+// String zwraca wartości flagi.
+func (p pairFlags) String() string {
+	var parts []string
+	for name, val := range p {
+		parts = append(parts, name+"="+val)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+// Set dodaje wartość w postaci nazwa=wartość.
+func (p pairFlags) Set(s string) error {
+	i := strings.Index(s, "=")
+	if i <= 0 {
+		return errors.New("wartość musi mieć postać nazwa=wartość")
+	}
+	p[s[:i]] = s[i+1:]
+	return nil
+}
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// run dobiera parametry zgodnie z flagami i wypisuje ranking kandydatów.
+func run(args []string) error {
+	var spec tune.Spec
+	space, fixed := pairFlags{}, pairFlags{}
+	fs := flag.NewFlagSet("isa-tune", flag.ExitOnError)
+	fs.StringVar(&spec.Algorithm, "algorytm", "ga", "algorytm z rejestru")
+	fns := fs.String("funkcje", "lab", "funkcje testowe oddzielone przecinkami")
+	fs.IntVar(&spec.Dim, "wymiar", 0, "liczba wymiarów (0 - domyślna dla funkcji)")
+	fs.StringVar(&spec.Method, "metoda", "random", "metoda przeszukiwania (random, halving, race, metaga)")
+	fs.IntVar(&spec.Budget, "budzet", 200, "maksymalna liczba uruchomień algorytmu")
+	fs.IntVar(&spec.Repetitions, "powtorzenia", 5, "liczba instancji każdego kandydata (początkowa w halving i race)")
+	fs.IntVar(&spec.Candidates, "kandydaci", 0, "liczba kandydatów lub populacja metaga (0 - dopasowana do budżetu)")
+	fs.Int64Var(&spec.Seed, "ziarno", 1, "ziarno pierwszej instancji i losowania kandydatów")
+	fs.Float64Var(&spec.Alpha, "alfa", 0.05, "poziom istotności testów")
+	fs.Float64Var(&spec.Confidence, "ufnosc", 0.95, "poziom ufności przedziałów średnich")
+	workers := fs.Int("watki", 1, "liczba równoległych uruchomień")
+	top := fs.Int("najlepsze", 10, "liczba wypisanych kandydatów (0 - wszyscy)")
+	jsonFormat := fs.Bool("json", false, "wypisz wynik w formacie JSON")
+	fs.Var(space, "p", "dziedzina strojonego parametru w postaci nazwa=dziedzina (można powtarzać)")
+	fs.Var(fixed, "s", "stała wartość parametru w postaci nazwa=wartość (można powtarzać)")
+	fs.Parse(args)
+	spec.Functions = strings.Split(*fns, ",")
+	spec.Space, spec.Fixed = space, fixed
+
+	res, err := tune.Run(context.Background(), spec, *workers)
+	if err != nil {
+		return err
+	}
+	if *jsonFormat {
+		return json.NewEncoder(os.Stdout).Encode(res)
+	}
+
+	fmt.Printf("Metoda %s, wykorzystano %d z %d uruchomień, %d kandydatów.\n", res.Method, res.Runs, spec.Budget,
+		len(res.Candidates))
+	fmt.Printf("%4s %-40s %4s %12s %27s %12s %12s %9s\n", "nr", "parametry", "n", "średnia",
+		fmt.Sprintf("przedział ufności %g%%", spec.Confidence*100), "mediana", "odch. std.", "p")
+	for i, c := range res.Candidates {
+		if *top > 0 && i == *top {
+			break
+		}
+		p := "-"
+		if i > 0 {
+			p = fmt.Sprintf("%.4f", c.P)
+			if c.P < spec.Alpha {
+				p += "*"
+			}
+		}
+		s := c.Summary
+		fmt.Printf("%4d %-40s %4d %12.6f %13.6f - %11.6f %12.6f %12.6f %9s\n", i+1, pairFlags(c.Params).String(), s.N,
+			s.Mean, s.CILow, s.CIHigh, s.Median, s.SD, p)
+	}
+	fmt.Printf("* - istotnie gorszy od najlepszego (p < %g)\n", spec.Alpha)
+	return nil
+}
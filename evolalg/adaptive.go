@@ -0,0 +1,153 @@
+package evolalg
+
+import (
+	"errors"
+	"math"
+)
+
+// AdaptationMethod describes the way the crossover and mutation probabilities change during Solve.
+type AdaptationMethod int
+
+const (
+	// FixedRates keeps the probabilities passed to Solve for the whole run. This is the default.
+	FixedRates AdaptationMethod = iota
+	// LinearDecay changes the probabilities linearly from the ones passed to Solve in the first
+	// epoch to FinalCP and FinalMP in the last one.
+	LinearDecay
+	// ExponentialDecay changes the probabilities geometrically from the ones passed to Solve in the
+	// first epoch to FinalCP and FinalMP in the last one.
+	ExponentialDecay
+	// DiversityFeedback multiplies the mutation probability by Boost in the epochs in which the
+	// mean locus entropy of the population is lower than DiversityThreshold.
+	DiversityFeedback
+	// SrinivasPatnaik calculates the probabilities based on the fits relatively to the best and the
+	// average fit of the population (Srinivas & Patnaik, 1994): the crossover probability of a pair
+	// of parents from the better one and the mutation probability of each child from the parent it
+	// inherits its head from. With the InPlace replacement the mean of these probabilities is used
+	// for the whole population.
+	SrinivasPatnaik
+)
+
+// Adaptation contains the configuration of the control of crossover and mutation probabilities.
+type Adaptation struct {
+	Method             AdaptationMethod
+	FinalCP            float64 // crossover probability in the last epoch of the decay schedules
+	FinalMP            float64 // mutation probability in the last epoch of the decay schedules
+	DiversityThreshold float64 // mean locus entropy below which the diversity collapsed (0.2 if 0)
+	Boost              float64 // mutation probability multiplier used when the diversity collapsed (2 if 0)
+	K1, K2, K3, K4     float64 // Srinivas-Patnaik constants (1, 0.5, 1, 0.5 if 0)
+}
+
+// AdaptationByName returns the adaptation method based on its name: "fixed", "linear",
+// "exponential", "diversity" or "sp". An empty name returns FixedRates.
+func AdaptationByName(name string) (AdaptationMethod, error) {
+	switch name {
+	case "", "fixed":
+		return FixedRates, nil
+	case "linear":
+		return LinearDecay, nil
+	case "exponential":
+		return ExponentialDecay, nil
+	case "diversity":
+		return DiversityFeedback, nil
+	case "sp":
+		return SrinivasPatnaik, nil
+	}
+	return FixedRates, errors.New("unknown adaptation method " + name)
+}
+
+// SetAdaptation sets the control of the crossover and mutation probabilities used by Solve.
+func (gas *GeneticAlgorithmSolver) SetAdaptation(ad Adaptation) error {
+	if ad.Method == LinearDecay || ad.Method == ExponentialDecay {
+		if ad.FinalCP <= 0 || ad.FinalCP > 1 || ad.FinalMP <= 0 || ad.FinalMP > 1 {
+			return errors.New("final probabilities have to be in (0, 1> set")
+		}
+	}
+	if ad.DiversityThreshold == 0 {
+		ad.DiversityThreshold = 0.2
+	}
+	if ad.Boost == 0 {
+		ad.Boost = 2
+	}
+	if ad.K1 == 0 {
+		ad.K1 = 1
+	}
+	if ad.K2 == 0 {
+		ad.K2 = 0.5
+	}
+	if ad.K3 == 0 {
+		ad.K3 = 1
+	}
+	if ad.K4 == 0 {
+		ad.K4 = 0.5
+	}
+	gas.adaptation = ad
+	return nil
+}
+
+// adaptRates calculates the probabilities used in the current epoch and saves them in the solver.
+func (gas *GeneticAlgorithmSolver) adaptRates(cp, mp float64, epochs int) (float64, float64) {
+	ad := gas.adaptation
+	t := 0.0
+	if epochs > 1 {
+		t = float64(gas.epoch-1) / float64(epochs-1)
+	}
+
+	switch ad.Method {
+	case LinearDecay:
+		cp = cp + (ad.FinalCP-cp)*t
+		mp = mp + (ad.FinalMP-mp)*t
+	case ExponentialDecay:
+		cp = cp * math.Pow(ad.FinalCP/cp, t)
+		mp = mp * math.Pow(ad.FinalMP/mp, t)
+	case DiversityFeedback:
+		div := MeasureDiversity(gas.popArr, gas.popVals, gas.fitCache)
+		if div.MeanEntropy < ad.DiversityThreshold {
+			mp = math.Min(1, mp*ad.Boost)
+		}
+	case SrinivasPatnaik:
+		gas.spFMax, gas.spFAvg = math.Inf(-1), 0
+		for _, f := range gas.fitCache {
+			gas.spFMax = math.Max(gas.spFMax, f)
+			gas.spFAvg += f
+		}
+		gas.spFAvg /= float64(len(gas.fitCache))
+
+		// The mean of the rates of every possible individual is reported for the epoch
+		sumCP, sumMP := 0.0, 0.0
+		for _, f := range gas.fitCache {
+			c, m := gas.spRates(f)
+			sumCP += c
+			sumMP += m
+		}
+		cp = sumCP / float64(len(gas.fitCache))
+		mp = sumMP / float64(len(gas.fitCache))
+	}
+
+	gas.epochCP, gas.epochMP = cp, mp
+	return cp, mp
+}
+
+// pairRates returns the crossover probability used for mating the p1 and p2 members of the
+// population and the mutation probabilities of their children inheriting from p1 and p2.
+func (gas *GeneticAlgorithmSolver) pairRates(p1, p2 int, cp, mp float64) (float64, [2]float64) {
+	if gas.adaptation.Method != SrinivasPatnaik {
+		return cp, [2]float64{mp, mp}
+	}
+	cp, _ = gas.spRates(math.Max(gas.fitCache[p1], gas.fitCache[p2]))
+	_, mp1 := gas.spRates(gas.fitCache[p1])
+	_, mp2 := gas.spRates(gas.fitCache[p2])
+	return cp, [2]float64{mp1, mp2}
+}
+
+// spRates calculates the Srinivas-Patnaik probabilities for the given fit. The individuals better
+// than average are disrupted less the closer they are to the best one.
+func (gas *GeneticAlgorithmSolver) spRates(f float64) (cp, mp float64) {
+	// The average of a converged population can differ from its best fit by the rounding error
+	ad := gas.adaptation
+	if f < gas.spFAvg || gas.spFMax-gas.spFAvg <= 1e-12*math.Max(1, math.Abs(gas.spFMax)) {
+		return ad.K3, ad.K4
+	}
+	scale := (gas.spFMax - f) / (gas.spFMax - gas.spFAvg)
+	return ad.K1 * scale, ad.K2 * scale
+}
@@ -0,0 +1,109 @@
+package evolalg
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestAdaptation(t *testing.T) {
+	newSolver := func() GeneticAlgorithmSolver {
+		gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, func(x float64) float64 {
+			return math.Mod(x, 1*(math.Cos(20*math.Pi*x)-math.Sin(x)))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		gas.SetSeed(5)
+		return gas
+	}
+
+	// Decay schedules have to start with the passed probabilities and end with the final ones
+	for _, method := range []AdaptationMethod{LinearDecay, ExponentialDecay} {
+		gas := newSolver()
+		err := gas.SetAdaptation(Adaptation{Method: method, FinalCP: 0.5, FinalMP: 0.001})
+		if err != nil {
+			t.Fatal(err)
+		}
+		hist, err := gas.Solve(10, 20, 0.9, 0.01)
+		if err != nil {
+			t.Fatal(err)
+		}
+		first, last := hist[1], hist[len(hist)-1]
+		if math.Abs(first.CP-0.9) > 1e-9 || math.Abs(first.MP-0.01) > 1e-9 {
+			t.Log(fmt.Sprintf("schedule %d started with cp=%f mp=%f", method, first.CP, first.MP))
+			t.Fail()
+		}
+		if math.Abs(last.CP-0.5) > 1e-9 || math.Abs(last.MP-0.001) > 1e-9 {
+			t.Log(fmt.Sprintf("schedule %d ended with cp=%f mp=%f", method, last.CP, last.MP))
+			t.Fail()
+		}
+		for i := 2; i < len(hist); i++ {
+			if hist[i].CP > hist[i-1].CP || hist[i].MP > hist[i-1].MP {
+				t.Log(fmt.Sprintf("schedule %d increased the probabilities in epoch %d", method, i))
+				t.Fail()
+			}
+		}
+	}
+
+	// Diversity feedback raises mp in an already converged population
+	gas := newSolver()
+	gas.SetAdaptation(Adaptation{Method: DiversityFeedback, DiversityThreshold: 1.1, Boost: 3})
+	hist, err := gas.Solve(10, 3, 0.75, 0.005)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(hist[1].MP-0.015) > 1e-9 {
+		t.Log(fmt.Sprintf("diversity feedback did not boost mp - %f", hist[1].MP))
+		t.Fail()
+	}
+
+	// Srinivas-Patnaik works with both the in place and the offspring based replacement
+	for _, r := range []Replacement{InPlace, Generational} {
+		gas := newSolver()
+		gas.SetReplacement(r, 0)
+		gas.SetAdaptation(Adaptation{Method: SrinivasPatnaik})
+		hist, err := gas.Solve(10, 10, 0.75, 0.005)
+		if err != nil {
+			t.Log(err.Error())
+			t.Fail()
+			continue
+		}
+		for i := 1; i < len(hist); i++ {
+			if hist[i].CP <= 0 || hist[i].CP > 1 || hist[i].MP <= 0 || hist[i].MP > 0.5 {
+				t.Log(fmt.Sprintf("incorrect Srinivas-Patnaik rates in epoch %d - cp=%f mp=%f", i, hist[i].CP, hist[i].MP))
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestSrinivasPatnaikRates(t *testing.T) {
+	gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, LabFunction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gas.SetAdaptation(Adaptation{Method: SrinivasPatnaik})
+
+	// Crossover depends on the better parent and mutation on the parent the child inherits from
+	gas.fitCache = []float64{4, 3, 1, 0}
+	gas.spFMax, gas.spFAvg = 4, 2
+	cp, mps := gas.pairRates(1, 2, 0.75, 0.01)
+	if cp != 0.5 || mps != [2]float64{0.25, 0.5} {
+		t.Log(fmt.Sprintf("pair of fits 3 and 1 got cp=%f and mp=%v", cp, mps))
+		t.Fail()
+	}
+	cp, mps = gas.pairRates(0, 3, 0.75, 0.01)
+	if cp != 0 || mps != [2]float64{0, 0.5} {
+		t.Log(fmt.Sprintf("pair of fits 4 and 0 got cp=%f and mp=%v", cp, mps))
+		t.Fail()
+	}
+
+	// A converged population is disrupted with the highest probabilities despite the rounding error
+	gas.fitCache = []float64{0.1, 0.1, 0.1}
+	gas.spFMax, gas.spFAvg = 0.1, (0.1+0.1+0.1)/3
+	if cp, mps = gas.pairRates(0, 1, 0.75, 0.01); cp != 1 || mps != [2]float64{0.5, 0.5} {
+		t.Log(fmt.Sprintf("converged population got cp=%f and mp=%v", cp, mps))
+		t.Fail()
+	}
+}
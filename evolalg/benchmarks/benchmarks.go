@@ -0,0 +1,109 @@
+// Package benchmarks contains the standard test functions of continuous optimization together with
+// their known optima, default bounds and supported dimensionalities, and the binary problems defined
+// directly on the genomes of the genetic algorithm.
+package benchmarks
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/TheSlipper/isa/evolalg"
+)
+
+// Function is a benchmark function. Most of the benchmarks are minimized while the solvers of
+// evolalg maximize, so Problem negates them.
+type Function struct {
+	Name       string  `json:"name"`       // key of the function (e.g. "rastrigin")
+	Label      string  `json:"label"`      // human readable name
+	Maximize   bool    `json:"maximize"`   // the function is maximized instead of minimized
+	DefaultDim int     `json:"defaultDim"` // dimensionality used if none is given
+	MinDim     int     `json:"minDim"`     // lowest supported dimensionality
+	MaxDim     int     `json:"maxDim"`     // highest supported dimensionality (no limit if 0)
+	Lower      float64 `json:"lower"`      // default lower bound of every coordinate
+	Upper      float64 `json:"upper"`      // default upper bound of every coordinate
+
+	F func(x []float64) float64 `json:"-"`
+
+	// optima returns the locations and the value of the global optima in n dimensions. ok is false
+	// if they aren't known.
+	optima func(n int) (xs [][]float64, f float64, ok bool)
+}
+
+// functions contains the built-in benchmarks in the order in which they're offered.
+var functions = []Function{
+	lab, sphere, rastrigin, ackley, rosenbrock, schwefel, griewank, michalewicz, himmelblau, easom,
+	deb1, deb2, gramacyLee,
+}
+
+// All returns all of the built-in benchmarks.
+func All() []Function {
+	return append([]Function(nil), functions...)
+}
+
+// ByName returns the built-in benchmark with the given name.
+func ByName(name string) (Function, error) {
+	for _, f := range functions {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	return Function{}, errors.New("unknown benchmark function " + name)
+}
+
+// CheckDim checks if the function is defined in n dimensions.
+func (f Function) CheckDim(n int) error {
+	if n < f.MinDim || (f.MaxDim != 0 && n > f.MaxDim) {
+		if f.MinDim == f.MaxDim {
+			return fmt.Errorf("%s is defined only in %d dimensions", f.Name, f.MinDim)
+		}
+		return fmt.Errorf("%s needs at least %d dimensions", f.Name, f.MinDim)
+	}
+	return nil
+}
+
+// Optima returns the locations and the value of the global optima of the function in n dimensions.
+// ok is false if they aren't known and xs is nil if only the value is known.
+func (f Function) Optima(n int) (xs [][]float64, val float64, ok bool) {
+	if f.optima == nil || f.CheckDim(n) != nil {
+		return nil, 0, false
+	}
+	return f.optima(n)
+}
+
+// Grade converts a value of the function to the grade maximized by the solvers.
+func (f Function) Grade(val float64) float64 {
+	if f.Maximize || val == 0 {
+		return val // avoids the negative zero
+	}
+	return -val
+}
+
+// Problem returns the maximization problem of the function in n dimensions within the default
+// bounds. The best grade of the problem is Grade of the optimum value.
+func (f Function) Problem(n int) (evolalg.Problem, error) {
+	return f.ProblemIn(n, f.Lower, f.Upper)
+}
+
+// ProblemIn returns the maximization problem of the function in n dimensions with every coordinate
+// in the <lower, upper> set.
+func (f Function) ProblemIn(n int, lower, upper float64) (p evolalg.Problem, err error) {
+	err = f.CheckDim(n)
+	if err != nil {
+		return
+	} else if lower >= upper {
+		err = errors.New("lower bound has to be lower than the upper one")
+		return
+	}
+	p.Lower, p.Upper = make([]float64, n), make([]float64, n)
+	for j := 0; j < n; j++ {
+		p.Lower[j], p.Upper[j] = lower, upper
+	}
+	p.Name = f.Name
+	fn := f.F
+	if f.Maximize {
+		p.F = fn
+	} else {
+		p.F = func(x []float64) float64 { return -fn(x) }
+	}
+	return
+}
@@ -0,0 +1,73 @@
+package benchmarks
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TheSlipper/isa/evolalg"
+)
+
+func TestByName(t *testing.T) {
+	for _, name := range []string{"lab", "sphere", "rastrigin", "ackley", "rosenbrock", "schwefel",
+		"griewank", "michalewicz", "himmelblau", "easom", "deb1", "deb2", "gramacy-lee"} {
+		f, err := ByName(name)
+		if err != nil {
+			t.Log(err.Error())
+			t.Fail()
+			continue
+		}
+		if err := f.CheckDim(f.DefaultDim); err != nil {
+			t.Log(fmt.Sprintf("%s: default dimensionality is unsupported: %s", name, err.Error()))
+			t.Fail()
+		}
+	}
+	if _, err := ByName("x"); err == nil {
+		t.Log("unknown function was found")
+		t.Fail()
+	}
+}
+
+func TestProblem(t *testing.T) {
+	if _, err := himmelblau.Problem(3); err == nil {
+		t.Log("himmelblau accepted 3 dimensions")
+		t.Fail()
+	}
+	if _, err := rosenbrock.Problem(1); err == nil {
+		t.Log("rosenbrock accepted 1 dimension")
+		t.Fail()
+	}
+	if _, err := sphere.ProblemIn(2, 1, 1); err == nil {
+		t.Log("empty bounds were accepted")
+		t.Fail()
+	}
+
+	// Minimized functions are negated so that the solvers can maximize them
+	p, err := sphere.Problem(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Lower) != 3 || p.Lower[0] != -5.12 || p.Upper[2] != 5.12 {
+		t.Log(fmt.Sprintf("bounds %v %v aren't the default ones", p.Lower, p.Upper))
+		t.Fail()
+	}
+	if v := p.F([]float64{1, 1, 1}); v != -3 {
+		t.Log(fmt.Sprintf("problem graded (1, 1, 1) with %f instead of -3", v))
+		t.Fail()
+	}
+
+	// A solver should get close to the optimum of an easy benchmark
+	de, err := evolalg.NewDifferentialEvolutionSolver(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	de.SetSeed(1)
+	hist, err := de.Solve(20, 100, 0.5, 0.9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, val, _ := sphere.Optima(3)
+	if gap := sphere.Grade(val) - hist[len(hist)-1].EliteFit; gap > 1e-3 {
+		t.Log(fmt.Sprintf("differential evolution ended %f away from the optimum", gap))
+		t.Fail()
+	}
+}
@@ -0,0 +1,218 @@
+package benchmarks
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/TheSlipper/isa/evolalg"
+)
+
+// Lengths of the shortest and the longest genomes supported by evolalg.NewGenotypeSolver.
+const (
+	minGenome = 2
+	maxGenome = 32
+)
+
+// OneMax counts the ones of the genome. Its optimum is the genome of L ones.
+type OneMax struct {
+	L int
+}
+
+// Len returns the length of the genome.
+func (f OneMax) Len() int {
+	return f.L
+}
+
+// Fitness returns the amount of ones in the genome.
+func (f OneMax) Fitness(genome []byte) float64 {
+	return float64(ones(genome))
+}
+
+// Optimum returns the highest fitness of the problem.
+func (f OneMax) Optimum() float64 {
+	return float64(f.L)
+}
+
+// LeadingOnes counts the consecutive ones at the start of the genome. Its optimum is the genome of
+// L ones.
+type LeadingOnes struct {
+	L int
+}
+
+// Len returns the length of the genome.
+func (f LeadingOnes) Len() int {
+	return f.L
+}
+
+// Fitness returns the amount of ones preceding the first zero of the genome.
+func (f LeadingOnes) Fitness(genome []byte) float64 {
+	n := 0
+	for n < len(genome) && genome[n] == 1 {
+		n++
+	}
+	return float64(n)
+}
+
+// Optimum returns the highest fitness of the problem.
+func (f LeadingOnes) Optimum() float64 {
+	return float64(f.L)
+}
+
+// RoyalRoad is the Royal Road function R1 of Mitchell, Forrest and Holland. The genome is split into
+// Blocks blocks of K bits and every block made of ones only adds K to the fitness.
+type RoyalRoad struct {
+	Blocks, K int
+}
+
+// Len returns the length of the genome.
+func (f RoyalRoad) Len() int {
+	return f.Blocks * f.K
+}
+
+// Fitness returns K times the amount of complete blocks of the genome.
+func (f RoyalRoad) Fitness(genome []byte) float64 {
+	sum := 0
+	for i := 0; i < f.Blocks; i++ {
+		if ones(genome[i*f.K:(i+1)*f.K]) == f.K {
+			sum += f.K
+		}
+	}
+	return float64(sum)
+}
+
+// Optimum returns the highest fitness of the problem.
+func (f RoyalRoad) Optimum() float64 {
+	return float64(f.Blocks * f.K)
+}
+
+// Trap is the concatenation of Blocks deceptive trap functions of K bits. A block of K ones is worth
+// K while a block of u < K ones is worth K - 1 - u, so the gradient of every block leads towards the
+// zeros and away from the optimum of all ones.
+type Trap struct {
+	Blocks, K int
+}
+
+// Len returns the length of the genome.
+func (f Trap) Len() int {
+	return f.Blocks * f.K
+}
+
+// Fitness returns the sum of the traps of the blocks of the genome.
+func (f Trap) Fitness(genome []byte) float64 {
+	sum := 0
+	for i := 0; i < f.Blocks; i++ {
+		u := ones(genome[i*f.K : (i+1)*f.K])
+		if u == f.K {
+			sum += f.K
+		} else {
+			sum += f.K - 1 - u
+		}
+	}
+	return float64(sum)
+}
+
+// Optimum returns the highest fitness of the problem.
+func (f Trap) Optimum() float64 {
+	return float64(f.Blocks * f.K)
+}
+
+// NK is the NK landscape of Kauffman. The fitness is the mean of the contributions of N bits, each
+// of which depends on the bit itself and on K other randomly chosen bits through a random table.
+// The tables and the neighbourhoods are drawn from a seeded source, so equal seeds give equal
+// landscapes. The optimum of a landscape isn't known in advance.
+type NK struct {
+	n, k       int
+	neighbours [][]int     // neighbours[i] contains the bit i followed by the K bits it depends on
+	tables     [][]float64 // tables[i] contains the contributions of bit i for all 2^(K+1) patterns
+}
+
+// NewNK creates an NK landscape of n bits, each depending on k other ones, from the given seed.
+func NewNK(n, k int, seed int64) (nk NK, err error) {
+	if n < minGenome || n > maxGenome {
+		err = fmt.Errorf("genome length has to be between %d and %d bits", minGenome, maxGenome)
+		return
+	} else if k < 0 || k >= n {
+		err = errors.New("amount of epistatic links has to be between 0 and n - 1")
+		return
+	}
+	rng := rand.New(rand.NewSource(seed))
+	nk.n, nk.k = n, k
+	nk.neighbours = make([][]int, n)
+	nk.tables = make([][]float64, n)
+	for i := 0; i < n; i++ {
+		// the k neighbours are the first k of a random permutation of the other bits
+		nk.neighbours[i] = append(nk.neighbours[i], i)
+		for _, j := range rng.Perm(n - 1)[:k] {
+			if j >= i {
+				j++
+			}
+			nk.neighbours[i] = append(nk.neighbours[i], j)
+		}
+		nk.tables[i] = make([]float64, 1<<uint(k+1))
+		for p := range nk.tables[i] {
+			nk.tables[i][p] = rng.Float64()
+		}
+	}
+	return
+}
+
+// Len returns the length of the genome.
+func (nk NK) Len() int {
+	return nk.n
+}
+
+// K returns the amount of bits each of the bits depends on.
+func (nk NK) K() int {
+	return nk.k
+}
+
+// Fitness returns the mean contribution of the bits of the genome.
+func (nk NK) Fitness(genome []byte) float64 {
+	sum := 0.0
+	for i, nb := range nk.neighbours {
+		p := 0
+		for _, j := range nb {
+			p = p<<1 | int(genome[j])
+		}
+		sum += nk.tables[i][p]
+	}
+	return sum / float64(nk.n)
+}
+
+// ones returns the amount of ones in the bits.
+func ones(bits []byte) (n int) {
+	for _, b := range bits {
+		n += int(b)
+	}
+	return
+}
+
+// GenotypeNames are the names accepted by GenotypeByName.
+var GenotypeNames = []string{"onemax", "leadingones", "royalroad", "trap", "nk"}
+
+// GenotypeByName creates the binary problem with the given name on genomes of l bits. k is the size
+// of the blocks of royalroad and trap (l has to be its multiple) and the amount of epistatic links
+// of nk, whose tables are drawn from seed.
+func GenotypeByName(name string, l, k int, seed int64) (evolalg.Genotype, error) {
+	if l < minGenome || l > maxGenome {
+		return nil, fmt.Errorf("genome length has to be between %d and %d bits", minGenome, maxGenome)
+	}
+	switch name {
+	case "onemax":
+		return OneMax{l}, nil
+	case "leadingones":
+		return LeadingOnes{l}, nil
+	case "royalroad", "trap":
+		if k < 1 || l%k != 0 {
+			return nil, errors.New("genome length has to be a multiple of the block size")
+		}
+		if name == "trap" {
+			return Trap{l / k, k}, nil
+		}
+		return RoyalRoad{l / k, k}, nil
+	case "nk":
+		return NewNK(l, k, seed)
+	}
+	return nil, errors.New("unknown binary problem " + name)
+}
@@ -0,0 +1,93 @@
+package benchmarks
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBinary(t *testing.T) {
+	cases := []struct {
+		name   string
+		f      interface{ Fitness([]byte) float64 }
+		genome []byte
+		want   float64
+	}{
+		{"onemax", OneMax{6}, []byte{1, 0, 1, 1, 0, 1}, 4},
+		{"leadingones", LeadingOnes{6}, []byte{1, 1, 0, 1, 1, 1}, 2},
+		{"royalroad", RoyalRoad{2, 3}, []byte{1, 1, 1, 1, 0, 1}, 3},
+		{"royalroad optimum", RoyalRoad{2, 3}, []byte{1, 1, 1, 1, 1, 1}, 6},
+		{"trap ones", Trap{2, 3}, []byte{1, 1, 1, 0, 0, 0}, 5},
+		{"trap deceptive", Trap{2, 3}, []byte{1, 1, 0, 0, 1, 0}, 1},
+	}
+	for _, c := range cases {
+		if got := c.f.Fitness(c.genome); got != c.want {
+			t.Log(fmt.Sprintf("%s: fitness of %v is %f instead of %f", c.name, c.genome, got, c.want))
+			t.Fail()
+		}
+	}
+}
+
+func TestNK(t *testing.T) {
+	a, err := NewNK(10, 3, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := NewNK(10, 3, 7)
+	c, _ := NewNK(10, 3, 8)
+	genome := []byte{1, 0, 0, 1, 1, 0, 1, 0, 1, 1}
+	fa, fb, fc := a.Fitness(genome), b.Fitness(genome), c.Fitness(genome)
+	if fa != fb {
+		t.Log(fmt.Sprintf("equal seeds gave different fitness %f and %f", fa, fb))
+		t.Fail()
+	} else if fa == fc {
+		t.Log("different seeds gave equal landscapes")
+		t.Fail()
+	}
+	if fa < 0 || fa > 1 {
+		t.Log(fmt.Sprintf("fitness %f is out of <0, 1>", fa))
+		t.Fail()
+	}
+	for i, nb := range a.neighbours {
+		seen := map[int]bool{}
+		for _, j := range nb {
+			if seen[j] || j < 0 || j >= 10 {
+				t.Log(fmt.Sprintf("invalid neighbourhood %v of bit %d", nb, i))
+				t.Fail()
+			}
+			seen[j] = true
+		}
+	}
+	if _, err := NewNK(10, 10, 1); err == nil {
+		t.Log("k equal to n was accepted")
+		t.Fail()
+	}
+	if _, err := NewNK(1, 0, 1); err == nil {
+		t.Log("single bit genome was accepted")
+		t.Fail()
+	}
+}
+
+func TestGenotypeByName(t *testing.T) {
+	for _, name := range GenotypeNames {
+		g, err := GenotypeByName(name, 12, 4, 1)
+		if err != nil {
+			t.Log(fmt.Sprintf("%s: %s", name, err.Error()))
+			t.Fail()
+		} else if g.Len() != 12 {
+			t.Log(fmt.Sprintf("%s: genome has %d bits instead of 12", name, g.Len()))
+			t.Fail()
+		}
+	}
+	if _, err := GenotypeByName("trap", 10, 4, 1); err == nil {
+		t.Log("genome length not divisible by the block size was accepted")
+		t.Fail()
+	}
+	if _, err := GenotypeByName("onemax", 33, 0, 1); err == nil {
+		t.Log("too long genome was accepted")
+		t.Fail()
+	}
+	if _, err := GenotypeByName("onemax", 1, 1, 1); err == nil {
+		t.Log("genome without a cut point was accepted")
+		t.Fail()
+	}
+}
@@ -0,0 +1,60 @@
+package benchmarks
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/TheSlipper/isa/evolalg"
+)
+
+// objective returns the Objective of the problem or, if it has none, F that doesn't depend on the
+// epoch.
+func objective(p evolalg.Problem) func(x []float64, epoch int, rng *rand.Rand) float64 {
+	if p.Objective != nil {
+		return p.Objective
+	}
+	f := p.F
+	return func(x []float64, _ int, _ *rand.Rand) float64 { return f(x) }
+}
+
+// WithNoise returns the problem graded with an additive Gaussian noise of the standard deviation
+// sigma. F stays noise-free so it can still be used for the presentation of the results.
+func WithNoise(p evolalg.Problem, sigma float64) evolalg.Problem {
+	obj := objective(p)
+	p.Objective = func(x []float64, epoch int, rng *rand.Rand) float64 {
+		return obj(x, epoch, rng) + sigma*rng.NormFloat64()
+	}
+	return p
+}
+
+// WithDrift returns the problem whose landscape moves every period epochs by shift times the width
+// of the bounds in every dimension. The landscape wraps around the bounds, so the optimum moves
+// cyclically and stays within them. F stays the landscape of the first epoch.
+func WithDrift(p evolalg.Problem, shift float64, period int) evolalg.Problem {
+	if period < 1 {
+		period = 1
+	}
+	obj := objective(p)
+	lower, upper := p.Lower, p.Upper
+	p.Objective = func(x []float64, epoch int, rng *rand.Rand) float64 {
+		moves := float64(epoch / period)
+		if moves == 0 {
+			return obj(x, epoch, rng)
+		}
+		moved := make([]float64, len(x))
+		for i := range x {
+			width := upper[i] - lower[i]
+			if width == 0 {
+				moved[i] = x[i]
+				continue
+			}
+			off := math.Mod(x[i]-lower[i]-moves*shift*width, width)
+			if off < 0 {
+				off += width
+			}
+			moved[i] = lower[i] + off
+		}
+		return obj(moved, epoch, rng)
+	}
+	return p
+}
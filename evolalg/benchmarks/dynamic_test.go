@@ -0,0 +1,70 @@
+package benchmarks
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestWithNoise(t *testing.T) {
+	p, err := sphere.Problem(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noisy := WithNoise(p, 0.5)
+	rng := rand.New(rand.NewSource(1))
+	x := []float64{1, 2}
+	n, sum, sq := 20000, 0.0, 0.0
+	for k := 0; k < n; k++ {
+		v := noisy.Objective(x, k, rng) - p.F(x)
+		sum += v
+		sq += v * v
+	}
+	mean := sum / float64(n)
+	std := math.Sqrt(sq/float64(n) - mean*mean)
+	if math.Abs(mean) > 0.02 || math.Abs(std-0.5) > 0.02 {
+		t.Log(fmt.Sprintf("noise has the mean %f and the standard deviation %f", mean, std))
+		t.Fail()
+	}
+	if noisy.F(x) != p.F(x) {
+		t.Log("noise changed F")
+		t.Fail()
+	}
+}
+
+func TestWithDrift(t *testing.T) {
+	p, err := sphere.ProblemIn(1, -2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drifting := WithDrift(p, 0.25, 5)
+	for _, x := range []float64{-2, -1, 0, 0.5, 1.9} {
+		if v := drifting.Objective([]float64{x}, 4, nil); v != p.F([]float64{x}) {
+			t.Log(fmt.Sprintf("landscape moved before the first period at x = %f", x))
+			t.Fail()
+		}
+		// After one period the landscape moves by a quarter of the width, i.e. by 1
+		moved := x + 1
+		if moved > 2 {
+			moved -= 4
+		}
+		if v, want := drifting.Objective([]float64{moved}, 5, nil), p.F([]float64{x}); math.Abs(v-want) > 1e-12 {
+			t.Log(fmt.Sprintf("moved landscape graded %f with %f instead of %f", moved, v, want))
+			t.Fail()
+		}
+	}
+
+	// Four moves by a quarter bring the landscape back
+	if v := drifting.Objective([]float64{0.3}, 20, nil); math.Abs(v-p.F([]float64{0.3})) > 1e-12 {
+		t.Log(fmt.Sprintf("landscape didn't return after a full cycle (%f)", v))
+		t.Fail()
+	}
+
+	// Drift composes with noise
+	both := WithDrift(WithNoise(p, 1), 0.25, 5)
+	if v := both.Objective([]float64{0}, 5, rand.New(rand.NewSource(1))); v == both.F([]float64{0}) {
+		t.Log("noise was lost by the drift")
+		t.Fail()
+	}
+}
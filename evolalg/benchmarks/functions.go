@@ -0,0 +1,198 @@
+package benchmarks
+
+import (
+	"math"
+
+	"github.com/TheSlipper/isa/evolalg"
+)
+
+// filled returns the optimum in n dimensions with every coordinate equal to v.
+func filled(n int, v float64, f float64) ([][]float64, float64, bool) {
+	x := make([]float64, n)
+	for j := range x {
+		x[j] = v
+	}
+	return [][]float64{x}, f, true
+}
+
+// lab is the function of the laboratory assignment. Its optimum isn't known analytically.
+var lab = Function{
+	Name: "lab", Label: "funkcja z zadania laboratoryjnego", Maximize: true,
+	DefaultDim: 1, MinDim: 1, MaxDim: 1, Lower: -4, Upper: 12,
+	F: func(x []float64) float64 { return evolalg.LabFunction(x[0]) },
+}
+
+// sphere is the sum of the squares of the coordinates.
+var sphere = Function{
+	Name: "sphere", Label: "sfera",
+	DefaultDim: 2, MinDim: 1, Lower: -5.12, Upper: 5.12,
+	F: func(x []float64) float64 {
+		sum := 0.0
+		for _, v := range x {
+			sum += v * v
+		}
+		return sum
+	},
+	optima: func(n int) ([][]float64, float64, bool) { return filled(n, 0, 0) },
+}
+
+// rastrigin is the sphere with a regular grid of local minima added by the cosine term.
+var rastrigin = Function{
+	Name: "rastrigin", Label: "Rastrigin",
+	DefaultDim: 2, MinDim: 1, Lower: -5.12, Upper: 5.12,
+	F: func(x []float64) float64 {
+		sum := 10 * float64(len(x))
+		for _, v := range x {
+			sum += v*v - 10*math.Cos(2*math.Pi*v)
+		}
+		return sum
+	},
+	optima: func(n int) ([][]float64, float64, bool) { return filled(n, 0, 0) },
+}
+
+// ackley is a nearly flat plateau with a deep hole in the origin and many shallow local minima.
+var ackley = Function{
+	Name: "ackley", Label: "Ackley",
+	DefaultDim: 2, MinDim: 1, Lower: -32.768, Upper: 32.768,
+	F: func(x []float64) float64 {
+		n := float64(len(x))
+		sq, cos := 0.0, 0.0
+		for _, v := range x {
+			sq += v * v
+			cos += math.Cos(2 * math.Pi * v)
+		}
+		return -20*math.Exp(-0.2*math.Sqrt(sq/n)) - math.Exp(cos/n) + 20 + math.E
+	},
+	optima: func(n int) ([][]float64, float64, bool) { return filled(n, 0, 0) },
+}
+
+// rosenbrock is a narrow curved valley with the minimum in (1, ..., 1).
+var rosenbrock = Function{
+	Name: "rosenbrock", Label: "Rosenbrock",
+	DefaultDim: 2, MinDim: 2, Lower: -5, Upper: 10,
+	F: func(x []float64) float64 {
+		sum := 0.0
+		for i := 0; i < len(x)-1; i++ {
+			a, b := x[i+1]-x[i]*x[i], 1-x[i]
+			sum += 100*a*a + b*b
+		}
+		return sum
+	},
+	optima: func(n int) ([][]float64, float64, bool) { return filled(n, 1, 0) },
+}
+
+// schwefel is a deceptive function with the global minimum far from the second best local ones.
+var schwefel = Function{
+	Name: "schwefel", Label: "Schwefel",
+	DefaultDim: 2, MinDim: 1, Lower: -500, Upper: 500,
+	F: func(x []float64) float64 {
+		sum := 418.9828872724338 * float64(len(x))
+		for _, v := range x {
+			sum -= v * math.Sin(math.Sqrt(math.Abs(v)))
+		}
+		return sum
+	},
+	optima: func(n int) ([][]float64, float64, bool) { return filled(n, 420.9687463, 0) },
+}
+
+// griewank is a paraboloid with a product of cosines making it multimodal.
+var griewank = Function{
+	Name: "griewank", Label: "Griewank",
+	DefaultDim: 2, MinDim: 1, Lower: -600, Upper: 600,
+	F: func(x []float64) float64 {
+		sum, prod := 0.0, 1.0
+		for i, v := range x {
+			sum += v * v / 4000
+			prod *= math.Cos(v / math.Sqrt(float64(i+1)))
+		}
+		return 1 + sum - prod
+	},
+	optima: func(n int) ([][]float64, float64, bool) { return filled(n, 0, 0) },
+}
+
+// michalewicz has steep valleys and ridges (m = 10). Its optimum is known only for a few
+// dimensionalities.
+var michalewicz = Function{
+	Name: "michalewicz", Label: "Michalewicz",
+	DefaultDim: 2, MinDim: 1, Lower: 0, Upper: math.Pi,
+	F: func(x []float64) float64 {
+		sum := 0.0
+		for i, v := range x {
+			sum -= math.Sin(v) * math.Pow(math.Sin(float64(i+1)*v*v/math.Pi), 20)
+		}
+		return sum
+	},
+	optima: func(n int) ([][]float64, float64, bool) {
+		switch n {
+		case 2:
+			return [][]float64{{2.20290552, 1.57079633}}, -1.8013034, true
+		case 5:
+			return nil, -4.687658, true
+		case 10:
+			return nil, -9.66015, true
+		}
+		return nil, 0, false
+	},
+}
+
+// himmelblau has four global minima.
+var himmelblau = Function{
+	Name: "himmelblau", Label: "Himmelblau",
+	DefaultDim: 2, MinDim: 2, MaxDim: 2, Lower: -5, Upper: 5,
+	F: func(x []float64) float64 {
+		a, b := x[0]*x[0]+x[1]-11, x[0]+x[1]*x[1]-7
+		return a*a + b*b
+	},
+	optima: func(n int) ([][]float64, float64, bool) {
+		return [][]float64{
+			{3, 2},
+			{-2.805118, 3.131312},
+			{-3.779310, -3.283186},
+			{3.584428, -1.848126},
+		}, 0, true
+	},
+}
+
+// easom is flat everywhere except for a small area around its minimum.
+var easom = Function{
+	Name: "easom", Label: "Easom",
+	DefaultDim: 2, MinDim: 2, MaxDim: 2, Lower: -100, Upper: 100,
+	F: func(x []float64) float64 {
+		a, b := x[0]-math.Pi, x[1]-math.Pi
+		return -math.Cos(x[0]) * math.Cos(x[1]) * math.Exp(-a*a-b*b)
+	},
+	optima: func(n int) ([][]float64, float64, bool) { return [][]float64{{math.Pi, math.Pi}}, -1, true },
+}
+
+// deb1 has five equally high peaks in <0, 1> (Deb's function F1).
+var deb1 = Function{
+	Name: "deb1", Label: "Deb F1 (równe szczyty)", Maximize: true,
+	DefaultDim: 1, MinDim: 1, MaxDim: 1, Lower: 0, Upper: 1,
+	F: func(x []float64) float64 { return math.Pow(math.Sin(5*math.Pi*x[0]), 6) },
+	optima: func(n int) ([][]float64, float64, bool) {
+		return [][]float64{{0.1}, {0.3}, {0.5}, {0.7}, {0.9}}, 1, true
+	},
+}
+
+// deb2 has five peaks in <0, 1> decreasing exponentially (Deb's function F2).
+var deb2 = Function{
+	Name: "deb2", Label: "Deb F2 (malejące szczyty)", Maximize: true,
+	DefaultDim: 1, MinDim: 1, MaxDim: 1, Lower: 0, Upper: 1,
+	F: func(x []float64) float64 {
+		a := (x[0] - 0.1) / 0.8
+		return math.Exp(-2*math.Ln2*a*a) * math.Pow(math.Sin(5*math.Pi*x[0]), 6)
+	},
+	optima: func(n int) ([][]float64, float64, bool) { return [][]float64{{0.1}}, 1, true },
+}
+
+// gramacyLee is a one-dimensional function with many local minima (Gramacy & Lee, 2012).
+var gramacyLee = Function{
+	Name: "gramacy-lee", Label: "Gramacy-Lee",
+	DefaultDim: 1, MinDim: 1, MaxDim: 1, Lower: 0.5, Upper: 2.5,
+	F: func(x []float64) float64 {
+		return math.Sin(10*math.Pi*x[0])/(2*x[0]) + math.Pow(x[0]-1, 4)
+	},
+	optima: func(n int) ([][]float64, float64, bool) {
+		return [][]float64{{0.548563444114526}}, -0.869011134989500, true
+	},
+}
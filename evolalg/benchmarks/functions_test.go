@@ -0,0 +1,60 @@
+package benchmarks
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestOptima(t *testing.T) {
+	for _, f := range All() {
+		for _, n := range []int{1, 2, 5} {
+			xs, val, ok := f.Optima(n)
+			if !ok {
+				continue
+			}
+			for _, x := range xs {
+				if len(x) != n {
+					t.Log(fmt.Sprintf("%s: optimum %v doesn't have %d dimensions", f.Name, x, n))
+					t.Fail()
+					continue
+				}
+				if got := f.F(x); math.Abs(got-val) > 1e-4 {
+					t.Log(fmt.Sprintf("%s: f(%v) = %f instead of %f", f.Name, x, got, val))
+					t.Fail()
+				}
+
+				// No point around the optimum can be better
+				for j := range x {
+					for _, d := range []float64{-1e-3, 1e-3} {
+						y := append([]float64(nil), x...)
+						y[j] += d
+						if f.Grade(f.F(y)) > f.Grade(val)+1e-9 {
+							t.Log(fmt.Sprintf("%s: %v is better than the optimum", f.Name, y))
+							t.Fail()
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMultimodal(t *testing.T) {
+	// The local minima of Rastrigin lie close to the integer points
+	if v := rastrigin.F([]float64{1, 0}); math.Abs(v-1) > 0.1 {
+		t.Log(fmt.Sprintf("rastrigin(1, 0) = %f instead of about 1", v))
+		t.Fail()
+	}
+
+	// Deb's F2 peaks decrease
+	prev := math.Inf(1)
+	for _, x := range []float64{0.1, 0.3, 0.5, 0.7, 0.9} {
+		v := deb2.F([]float64{x})
+		if v >= prev {
+			t.Log(fmt.Sprintf("deb2 peak in %f (%f) isn't lower than the previous one", x, v))
+			t.Fail()
+		}
+		prev = v
+	}
+}
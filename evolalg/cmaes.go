@@ -0,0 +1,271 @@
+package evolalg
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// CMAESSolver is a covariance matrix adaptation evolution strategy solver of continuous
+// multi-dimensional problems (Hansen, 2016). It uses weighted recombination of the mu best
+// samples, cumulative step-size adaptation and rank-one and rank-mu updates of the covariance
+// matrix. Samples that leave the domain are clamped to its bounds. The history it returns has the
+// same format as the one of GeneticAlgorithmSolver with the mean and sigma of every generation.
+type CMAESSolver struct {
+	problem     Problem     // maximized function and its bounds
+	termination Termination // conditions that stop Solve before all of the epochs
+	rng         *rand.Rand  // source of all of the random numbers used by the solver
+
+	mean       []float64   // mean of the search distribution
+	sigma      float64     // step size
+	c          [][]float64 // covariance matrix
+	b          [][]float64 // eigenvectors of the covariance matrix (columns)
+	d          []float64   // square roots of the eigenvalues of the covariance matrix
+	pc, ps     []float64   // evolution paths of the covariance matrix and the step size
+	elite      []float64   // best sample found so far
+	eliteGrade float64     // grade of the elite
+	eliteEpoch int         // epoch in which the current elite was found for the first time
+	evals      int         // amount of fitness evaluations since the start of Solve
+	epoch      int         // currently processed generation (0 is the initial mean)
+	epochs     int         // amount of generations passed to Start
+
+	lambda, mu  int       // amount of samples and of the recombined best samples
+	weights     []float64 // recombination weights
+	mueff       float64   // variance effective selection mass
+	cc, cs      float64   // learning rates of the evolution paths
+	c1, cmu     float64   // learning rates of the rank-one and rank-mu updates
+	damps, chiN float64   // damping of the step size and the expected norm of N(0, I)
+}
+
+// NewCMAESSolver creates a new instance of a CMA-ES solver of the given problem.
+func NewCMAESSolver(p Problem) (cma CMAESSolver, err error) {
+	err = p.check()
+	if err != nil {
+		return
+	}
+	cma.problem = p
+	return
+}
+
+// SetSeed makes the solver use its own random number source seeded with the given seed so that the
+// runs can be repeated.
+func (cma *CMAESSolver) SetSeed(seed int64) {
+	cma.rng = rand.New(rand.NewSource(seed))
+}
+
+// SetTermination sets the conditions that stop Solve before it runs for all of the epochs. The
+// history returned by Solve ends with the epoch in which the run was stopped.
+func (cma *CMAESSolver) SetTermination(t Termination) {
+	cma.termination = t
+}
+
+// Evaluations returns the amount of fitness evaluations made since the start of the last Solve.
+func (cma CMAESSolver) Evaluations() int {
+	return cma.evals
+}
+
+// random returns the random number source of the solver. If none was seeded then a new one is
+// created based on the current time.
+func (cma *CMAESSolver) random() *rand.Rand {
+	if cma.rng == nil {
+		cma.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return cma.rng
+}
+
+// Grade calculates the grade of the passed vector.
+func (cma *CMAESSolver) Grade(x []float64) float64 {
+	cma.evals++
+	return cma.problem.F(x)
+}
+
+// Solve runs the CMA-ES with lambda samples per generation (4 + 3 ln n if 0) for a given amount of
+// epochs starting from a random mean and the step size sigma0 and returns a history of the
+// algorithm's execution. The first entry of the history holds only the initial mean.
+func (cma *CMAESSolver) Solve(lambda, epochs int, sigma0 float64) (hist []EpochData, err error) {
+	ed, err := cma.Start(lambda, epochs, sigma0)
+	if err != nil {
+		return
+	}
+	hist = append(make([]EpochData, 0, epochs+1), ed)
+	for done := epochs == 0; !done; {
+		ed, done, err = cma.Step()
+		if err != nil {
+			return
+		}
+		hist = append(hist, ed)
+	}
+	return
+}
+
+// Start initializes the solver the same way as Solve and returns the history entry of the initial
+// mean. The following generations are run with Step.
+func (cma *CMAESSolver) Start(lambda, epochs int, sigma0 float64) (ed EpochData, err error) {
+	p := cma.problem
+	n := p.Dim()
+	if lambda == 0 {
+		lambda = 4 + int(3*math.Log(float64(n)))
+	}
+	if lambda < 2 {
+		err = errors.New("CMA-ES needs at least 2 samples per generation")
+		return
+	} else if sigma0 <= 0 {
+		err = errors.New("initial step size has to be greater than zero")
+		return
+	}
+
+	// Recombination weights and the learning rates
+	mu := lambda / 2
+	weights := make([]float64, mu)
+	sumW, sumW2 := 0.0, 0.0
+	for i := range weights {
+		weights[i] = math.Log(float64(mu)+0.5) - math.Log(float64(i+1))
+		sumW += weights[i]
+	}
+	for i := range weights {
+		weights[i] /= sumW
+		sumW2 += weights[i] * weights[i]
+	}
+	nf := float64(n)
+	mueff := 1 / sumW2
+	cma.lambda, cma.mu, cma.weights, cma.mueff = lambda, mu, weights, mueff
+	cma.cc = (4 + mueff/nf) / (nf + 4 + 2*mueff/nf)
+	cma.cs = (mueff + 2) / (nf + mueff + 5)
+	cma.c1 = 2 / ((nf+1.3)*(nf+1.3) + mueff)
+	cma.cmu = math.Min(1-cma.c1, 2*(mueff-2+1/mueff)/((nf+2)*(nf+2)+mueff))
+	cma.damps = 1 + 2*math.Max(0, math.Sqrt((mueff-1)/(nf+1))-1) + cma.cs
+	cma.chiN = math.Sqrt(nf) * (1 - 1/(4*nf) + 1/(21*nf*nf))
+
+	// Initialize the solver
+	cma.mean = make([]float64, n)
+	for j := range cma.mean {
+		cma.mean[j] = p.Lower[j] + cma.random().Float64()*(p.Upper[j]-p.Lower[j])
+	}
+	cma.sigma = sigma0
+	cma.c, cma.b = identity(n), identity(n)
+	cma.d = make([]float64, n)
+	for j := range cma.d {
+		cma.d[j] = 1
+	}
+	cma.pc, cma.ps = make([]float64, n), make([]float64, n)
+	cma.evals = 0
+	cma.epoch, cma.epochs = 0, epochs
+	cma.elite, cma.eliteEpoch = append([]float64(nil), cma.mean...), 0
+	cma.eliteGrade = cma.Grade(cma.mean)
+	return cma.epochData([][]float64{cma.mean}, []float64{cma.eliteGrade}), nil
+}
+
+// Step runs a single generation of the solver started with Start and returns its history entry.
+// done is set if any of the termination conditions is met or the generation is the last one passed
+// to Start.
+func (cma *CMAESSolver) Step() (ed EpochData, done bool, err error) {
+	if cma.mean == nil {
+		err = errors.New("solver has to be started before running a generation")
+		return
+	}
+	p := cma.problem
+	n, nf := p.Dim(), float64(p.Dim())
+	lambda, mu, weights, mueff := cma.lambda, cma.mu, cma.weights, cma.mueff
+	cc, cs, c1, cmu := cma.cc, cma.cs, cma.c1, cma.cmu
+	cma.epoch++
+	g := cma.epoch
+
+	// Sample the population
+	xs, ys, grades := make([][]float64, lambda), make([][]float64, lambda), make([]float64, lambda)
+	for k := 0; k < lambda; k++ {
+		z := make([]float64, n)
+		for j := range z {
+			z[j] = cma.d[j] * cma.random().NormFloat64()
+		}
+		y := matVec(cma.b, z)
+		xs[k], ys[k] = make([]float64, n), y
+		for j := range y {
+			x := cma.mean[j] + cma.sigma*y[j]
+			xs[k][j] = math.Max(p.Lower[j], math.Min(p.Upper[j], x))
+			ys[k][j] = (xs[k][j] - cma.mean[j]) / cma.sigma
+		}
+		grades[k] = cma.Grade(xs[k])
+		if grades[k] > cma.eliteGrade {
+			cma.elite, cma.eliteGrade, cma.eliteEpoch = append([]float64(nil), xs[k]...), grades[k], g
+		}
+	}
+	order := make([]int, lambda)
+	for k := range order {
+		order[k] = k
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return grades[order[i]] > grades[order[j]]
+	})
+
+	// Move the mean to the weighted recombination of the mu best samples
+	yw := make([]float64, n)
+	for i := 0; i < mu; i++ {
+		for j := range yw {
+			yw[j] += weights[i] * ys[order[i]][j]
+		}
+	}
+	for j := range cma.mean {
+		cma.mean[j] = math.Max(p.Lower[j], math.Min(p.Upper[j], cma.mean[j]+cma.sigma*yw[j]))
+	}
+
+	// Update the evolution paths. C^-1/2 yw = B D^-1 B^T yw
+	bty := make([]float64, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			bty[i] += cma.b[j][i] * yw[j]
+		}
+		bty[i] /= cma.d[i]
+	}
+	invSqrtCyw := matVec(cma.b, bty)
+	for j := range cma.ps {
+		cma.ps[j] = (1-cs)*cma.ps[j] + math.Sqrt(cs*(2-cs)*mueff)*invSqrtCyw[j]
+	}
+	hsig := 0.0
+	if norm(cma.ps)/math.Sqrt(1-math.Pow(1-cs, 2*float64(g)))/cma.chiN < 1.4+2/(nf+1) {
+		hsig = 1
+	}
+	for j := range cma.pc {
+		cma.pc[j] = (1-cc)*cma.pc[j] + hsig*math.Sqrt(cc*(2-cc)*mueff)*yw[j]
+	}
+
+	// Rank-one and rank-mu updates of the covariance matrix
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			rankMu := 0.0
+			for k := 0; k < mu; k++ {
+				rankMu += weights[k] * ys[order[k]][i] * ys[order[k]][j]
+			}
+			cij := (1-c1-cmu)*cma.c[i][j] +
+				c1*(cma.pc[i]*cma.pc[j]+(1-hsig)*cc*(2-cc)*cma.c[i][j]) +
+				cmu*rankMu
+			cma.c[i][j], cma.c[j][i] = cij, cij
+		}
+	}
+
+	// Cumulative step-size adaptation
+	cma.sigma *= math.Exp((cs / cma.damps) * (norm(cma.ps)/cma.chiN - 1))
+
+	// Decompose the covariance matrix for the next generation
+	vals, vecs := symmetricEigen(cma.c)
+	cma.b = vecs
+	for j := range vals {
+		cma.d[j] = math.Sqrt(math.Max(vals[j], 1e-20))
+	}
+
+	ed = cma.epochData(xs, grades)
+	done = g >= cma.epochs || cma.termination.Done(g, cma.evals, cma.eliteGrade, cma.eliteEpoch)
+	return
+}
+
+// epochData creates the history entry of the sampled population and the current state of the
+// search distribution.
+func (cma *CMAESSolver) epochData(pop [][]float64, grades []float64) EpochData {
+	ed := vecEpochData(pop, grades, cma.elite, cma.eliteGrade)
+	ed.EliteEpoch = cma.eliteEpoch
+	ed.Evaluations = cma.evals
+	ed.Mean = append([]float64(nil), cma.mean...)
+	ed.Sigma = cma.sigma
+	return ed
+}
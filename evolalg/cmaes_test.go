@@ -0,0 +1,61 @@
+package evolalg
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// rotatedEllipsoid is a negated ill-conditioned ellipsoid rotated by 45 degrees with its maximum 0
+// in (1, 1).
+func rotatedEllipsoid(x []float64) float64 {
+	u, v := (x[0]+x[1]-2)/math.Sqrt2, (x[0]-x[1])/math.Sqrt2
+	return -(u*u + 1000*v*v)
+}
+
+func TestCMAES(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		p    Problem
+		opt  []float64
+	}{
+		{"sphere", Problem{Lower: []float64{-5, -5, -5, -5}, Upper: []float64{5, 5, 5, 5}, F: sphere}, []float64{0, 0, 0, 0}},
+		{"ellipsoid", Problem{Lower: []float64{-5, -5}, Upper: []float64{5, 5}, F: rotatedEllipsoid}, []float64{1, 1}},
+	} {
+		cma, err := NewCMAESSolver(tc.p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cma.SetSeed(8)
+		hist, err := cma.Solve(0, 200, 2)
+		if err != nil {
+			t.Log(fmt.Sprintf("%s: %s", tc.name, err.Error()))
+			t.Fail()
+			continue
+		}
+
+		last := hist[len(hist)-1]
+		for j := range tc.opt {
+			if math.Abs(last.EliteVec[j]-tc.opt[j]) > 1e-3 || math.Abs(last.Mean[j]-tc.opt[j]) > 1e-3 {
+				t.Log(fmt.Sprintf("%s: elite %v and mean %v are too far from %v", tc.name, last.EliteVec, last.Mean, tc.opt))
+				t.Fail()
+				break
+			}
+		}
+		if last.Sigma >= hist[0].Sigma {
+			t.Log(fmt.Sprintf("%s: step size didn't shrink (%f)", tc.name, last.Sigma))
+			t.Fail()
+		}
+		lambda := 4 + int(3*math.Log(float64(tc.p.Dim())))
+		if len(hist[1].PopulationVec) != lambda || last.Evaluations != 1+200*lambda {
+			t.Log(fmt.Sprintf("%s: %d samples and %d evaluations", tc.name, len(hist[1].PopulationVec), last.Evaluations))
+			t.Fail()
+		}
+	}
+
+	cma, _ := NewCMAESSolver(Problem{Lower: []float64{-1}, Upper: []float64{1}, F: sphere})
+	if _, err := cma.Solve(10, 10, 0); err == nil {
+		t.Log("solver accepted zero initial step size")
+		t.Fail()
+	}
+}
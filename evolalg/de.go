@@ -0,0 +1,308 @@
+package evolalg
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// DEStrategy describes the way the mutant vectors of differential evolution are created.
+type DEStrategy int
+
+const (
+	// Rand1Bin adds the scaled difference of two random vectors to a third random vector. This is
+	// the default.
+	Rand1Bin DEStrategy = iota
+	// Best1Bin adds the scaled difference of two random vectors to the best vector of the
+	// population.
+	Best1Bin
+	// CurrentToBest1 moves the target vector towards the best one and adds the scaled difference of
+	// two random vectors. With JADE the best vector is picked from the P best ones of the population
+	// (current-to-pbest/1).
+	CurrentToBest1
+)
+
+// DEStrategyByName returns the differential evolution strategy based on its name: "rand1bin",
+// "best1bin" or "current-to-best1". An empty name returns Rand1Bin.
+func DEStrategyByName(name string) (DEStrategy, error) {
+	switch name {
+	case "", "rand1bin":
+		return Rand1Bin, nil
+	case "best1bin":
+		return Best1Bin, nil
+	case "current-to-best1":
+		return CurrentToBest1, nil
+	}
+	return Rand1Bin, errors.New("unknown differential evolution strategy " + name)
+}
+
+// JADE contains the configuration of the JADE-style adaptation of the scale factor and the
+// crossover rate (Zhang & Sanderson, 2009). Every vector draws its own parameters around the means
+// which follow the parameters of the successful trial vectors.
+type JADE struct {
+	C float64 // learning rate of the means (0.1 if 0)
+	P float64 // fraction of the best vectors used as pbest by CurrentToBest1 (0.05 if 0)
+}
+
+// DifferentialEvolutionSolver is a differential evolution solver of continuous multi-dimensional
+// problems. The history it returns has the same format as the one of GeneticAlgorithmSolver with
+// the CP and MP fields holding the mean crossover rate and scale factor used in the epoch.
+type DifferentialEvolutionSolver struct {
+	problem     Problem     // maximized function and its bounds
+	strategy    DEStrategy  // the way the mutant vectors are created
+	jade        *JADE       // adaptation of the parameters (disabled if nil)
+	termination Termination // conditions that stop Solve before all of the epochs
+	rng         *rand.Rand  // source of all of the random numbers used by the solver
+
+	pop        [][]float64 // current population
+	grades     []float64   // grades of the current population
+	elite      []float64   // best vector found so far
+	eliteGrade float64     // grade of the elite
+	eliteEpoch int         // epoch in which the current elite was found for the first time
+	evals      int         // amount of fitness evaluations since the start of Solve
+	muF, muCR  float64     // means of the JADE parameter distributions
+	epoch      int         // currently processed epoch (0 is the initial population)
+	epochs     int         // amount of epochs passed to Start
+}
+
+// NewDifferentialEvolutionSolver creates a new instance of a differential evolution solver of the
+// given problem.
+func NewDifferentialEvolutionSolver(p Problem) (de DifferentialEvolutionSolver, err error) {
+	err = p.check()
+	if err != nil {
+		return
+	}
+	de.problem = p
+	return
+}
+
+// SetSeed makes the solver use its own random number source seeded with the given seed so that the
+// runs can be repeated.
+func (de *DifferentialEvolutionSolver) SetSeed(seed int64) {
+	de.rng = rand.New(rand.NewSource(seed))
+}
+
+// SetStrategy sets the strategy used for creating the mutant vectors.
+func (de *DifferentialEvolutionSolver) SetStrategy(s DEStrategy) {
+	de.strategy = s
+}
+
+// SetJADE enables the JADE-style adaptation of the scale factor and the crossover rate. The values
+// passed to Solve become the initial means of the parameter distributions.
+func (de *DifferentialEvolutionSolver) SetJADE(j JADE) error {
+	if j.C < 0 || j.C > 1 || j.P < 0 || j.P > 1 {
+		return errors.New("JADE learning rate and pbest fraction have to be in <0, 1> set")
+	}
+	if j.C == 0 {
+		j.C = 0.1
+	}
+	if j.P == 0 {
+		j.P = 0.05
+	}
+	de.jade = &j
+	return nil
+}
+
+// SetTermination sets the conditions that stop Solve before it runs for all of the epochs. The
+// history returned by Solve ends with the epoch in which the run was stopped.
+func (de *DifferentialEvolutionSolver) SetTermination(t Termination) {
+	de.termination = t
+}
+
+// Evaluations returns the amount of fitness evaluations made since the start of the last Solve.
+func (de DifferentialEvolutionSolver) Evaluations() int {
+	return de.evals
+}
+
+// random returns the random number source of the solver. If none was seeded then a new one is
+// created based on the current time.
+func (de *DifferentialEvolutionSolver) random() *rand.Rand {
+	if de.rng == nil {
+		de.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return de.rng
+}
+
+// Grade calculates the grade of the passed vector.
+func (de *DifferentialEvolutionSolver) Grade(x []float64) float64 {
+	de.evals++
+	return de.problem.F(x)
+}
+
+// Solve runs the differential evolution for N random vectors, for a given amount of epochs, with a
+// given scale factor f and crossover rate cr and returns a history of the algorithm's execution.
+func (de *DifferentialEvolutionSolver) Solve(N, epochs int, f, cr float64) (hist []EpochData, err error) {
+	ed, err := de.Start(N, epochs, f, cr)
+	if err != nil {
+		return
+	}
+	hist = append(make([]EpochData, 0, epochs+1), ed)
+	for done := epochs == 0; !done; {
+		ed, done, err = de.Step()
+		if err != nil {
+			return
+		}
+		hist = append(hist, ed)
+	}
+	return
+}
+
+// Start initializes the solver the same way as Solve and returns the history entry of the initial
+// population. The following epochs are run with Step.
+func (de *DifferentialEvolutionSolver) Start(N, epochs int, f, cr float64) (ed EpochData, err error) {
+	if N < 4 {
+		err = errors.New("differential evolution needs a population of at least 4 vectors")
+		return
+	} else if f <= 0 || f > 2 {
+		err = errors.New("scale factor has to be in (0, 2> set")
+		return
+	} else if cr < 0 || cr > 1 {
+		err = errors.New("crossover rate has to be in <0, 1> set")
+		return
+	}
+
+	// Initialize the solver
+	p := de.problem
+	de.pop, de.grades = make([][]float64, N), make([]float64, N)
+	de.elite, de.eliteGrade, de.eliteEpoch = nil, math.Inf(-1), 0
+	de.evals = 0
+	de.epoch, de.epochs = 0, epochs
+	de.muF, de.muCR = f, cr
+	for i := 0; i < N; i++ {
+		de.pop[i] = make([]float64, p.Dim())
+		for j := range de.pop[i] {
+			de.pop[i][j] = p.Lower[j] + de.random().Float64()*(p.Upper[j]-p.Lower[j])
+		}
+		de.grades[i] = de.Grade(de.pop[i])
+	}
+	de.updateElite(0)
+	return de.epochData(0, 0), nil
+}
+
+// Step runs a single epoch of the solver started with Start and returns its history entry. done is
+// set if any of the termination conditions is met or the epoch is the last one passed to Start.
+func (de *DifferentialEvolutionSolver) Step() (ed EpochData, done bool, err error) {
+	if de.pop == nil {
+		err = errors.New("solver has to be started before running an epoch")
+		return
+	}
+	de.epoch++
+	meanF, meanCR := de.evolve()
+	de.updateElite(de.epoch)
+	ed = de.epochData(meanF, meanCR)
+	done = de.epoch >= de.epochs || de.termination.Done(de.epoch, de.evals, de.eliteGrade, de.eliteEpoch)
+	return
+}
+
+// evolve creates the next population and returns the mean scale factor and crossover rate used
+// for creating it.
+func (de *DifferentialEvolutionSolver) evolve() (meanF, meanCR float64) {
+	N, dim := len(de.pop), de.problem.Dim()
+	order := make([]int, N)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return de.grades[order[i]] > de.grades[order[j]]
+	})
+
+	trials, trialGrades := make([][]float64, N), make([]float64, N)
+	fs, crs := make([]float64, N), make([]float64, N)
+	for i := 0; i < N; i++ {
+		fs[i], crs[i] = de.parameters()
+		meanF += fs[i]
+		meanCR += crs[i]
+
+		// Pick three distinct vectors other than the target
+		r := make([]int, 0, 3)
+		for len(r) < 3 {
+			k := de.random().Intn(N)
+			if k == i || (len(r) > 0 && r[0] == k) || (len(r) > 1 && r[1] == k) {
+				continue
+			}
+			r = append(r, k)
+		}
+
+		best := de.pop[order[0]]
+		if de.jade != nil && de.strategy == CurrentToBest1 {
+			top := int(math.Ceil(de.jade.P * float64(N)))
+			best = de.pop[order[de.random().Intn(top)]]
+		}
+
+		// Create the mutant vector and cross it with the target
+		x, F := de.pop[i], fs[i]
+		jrand := de.random().Intn(dim)
+		trial := make([]float64, dim)
+		for j := 0; j < dim; j++ {
+			if j != jrand && de.random().Float64() >= crs[i] {
+				trial[j] = x[j]
+				continue
+			}
+			var v float64
+			switch de.strategy {
+			case Best1Bin:
+				v = best[j] + F*(de.pop[r[0]][j]-de.pop[r[1]][j])
+			case CurrentToBest1:
+				v = x[j] + F*(best[j]-x[j]) + F*(de.pop[r[0]][j]-de.pop[r[1]][j])
+			default:
+				v = de.pop[r[0]][j] + F*(de.pop[r[1]][j]-de.pop[r[2]][j])
+			}
+			trial[j] = clampToBounds(v, x[j], de.problem.Lower[j], de.problem.Upper[j])
+		}
+		trials[i], trialGrades[i] = trial, de.Grade(trial)
+	}
+
+	// The trial vectors replace their targets if they are not worse
+	var sF, sF2, sCR float64
+	successes := 0
+	for i := 0; i < N; i++ {
+		if trialGrades[i] >= de.grades[i] {
+			de.pop[i], de.grades[i] = trials[i], trialGrades[i]
+			sF += fs[i]
+			sF2 += fs[i] * fs[i]
+			sCR += crs[i]
+			successes++
+		}
+	}
+	if de.jade != nil && successes > 0 {
+		c := de.jade.C
+		de.muCR = (1-c)*de.muCR + c*sCR/float64(successes)
+		de.muF = (1-c)*de.muF + c*sF2/sF // Lehmer mean favours larger scale factors
+	}
+
+	return meanF / float64(N), meanCR / float64(N)
+}
+
+// parameters returns the scale factor and the crossover rate used for a single vector.
+func (de *DifferentialEvolutionSolver) parameters() (f, cr float64) {
+	if de.jade == nil {
+		return de.muF, de.muCR
+	}
+	cr = math.Min(1, math.Max(0, de.muCR+0.1*de.random().NormFloat64()))
+	for f <= 0 {
+		f = de.muF + 0.1*math.Tan(math.Pi*(de.random().Float64()-0.5))
+	}
+	return math.Min(f, 1), cr
+}
+
+// updateElite saves the best vector of the population as the elite if it's better than the
+// current one.
+func (de *DifferentialEvolutionSolver) updateElite(epoch int) {
+	for i := range de.pop {
+		if de.grades[i] > de.eliteGrade {
+			de.elite = append([]float64(nil), de.pop[i]...)
+			de.eliteGrade, de.eliteEpoch = de.grades[i], epoch
+		}
+	}
+}
+
+// epochData creates the history entry of the current state of the solver.
+func (de *DifferentialEvolutionSolver) epochData(meanF, meanCR float64) EpochData {
+	ed := vecEpochData(de.pop, de.grades, de.elite, de.eliteGrade)
+	ed.EliteEpoch = de.eliteEpoch
+	ed.Evaluations = de.evals
+	ed.CR, ed.F = meanCR, meanF
+	return ed
+}
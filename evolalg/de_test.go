@@ -0,0 +1,82 @@
+package evolalg
+
+import (
+	"fmt"
+	"testing"
+)
+
+// sphere is the negated sphere function with its maximum 0 in the origin.
+func sphere(x []float64) float64 {
+	sum := 0.0
+	for _, v := range x {
+		sum -= v * v
+	}
+	return sum
+}
+
+func TestDifferentialEvolution(t *testing.T) {
+	p := Problem{Lower: []float64{-5, -5, -5}, Upper: []float64{5, 5, 5}, F: sphere}
+	for _, name := range []string{"rand1bin", "best1bin", "current-to-best1"} {
+		for _, jade := range []bool{false, true} {
+			strategy, err := DEStrategyByName(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			de, err := NewDifferentialEvolutionSolver(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			de.SetSeed(3)
+			de.SetStrategy(strategy)
+			if jade {
+				err = de.SetJADE(JADE{})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			hist, err := de.Solve(20, 100, 0.5, 0.9)
+			if err != nil {
+				t.Log(fmt.Sprintf("%s (jade %t): %s", name, jade, err.Error()))
+				t.Fail()
+				continue
+			}
+
+			if len(hist) != 101 {
+				t.Log(fmt.Sprintf("%s (jade %t): history has %d entries instead of 101", name, jade, len(hist)))
+				t.Fail()
+			}
+			last := hist[len(hist)-1]
+			if last.EliteFit < -1e-3 {
+				t.Log(fmt.Sprintf("%s (jade %t): elite %v graded %f is too far from the optimum", name, jade, last.EliteVec, last.EliteFit))
+				t.Fail()
+			}
+			if last.EliteGrade != last.EliteFit || last.F <= 0 || last.CR <= 0 || last.CP != 0 || last.MP != 0 {
+				t.Log(fmt.Sprintf("%s (jade %t): elite graded %f (fit %f) with CR %f, F %f, Pk %f, Pm %f", name, jade,
+					last.EliteGrade, last.EliteFit, last.CR, last.F, last.CP, last.MP))
+				t.Fail()
+			}
+			if last.Evaluations != 20*101 {
+				t.Log(fmt.Sprintf("%s (jade %t): %d evaluations instead of %d", name, jade, last.Evaluations, 20*101))
+				t.Fail()
+			}
+			for i, x := range last.PopulationVec {
+				for j, v := range x {
+					if v < p.Lower[j] || v > p.Upper[j] {
+						t.Log(fmt.Sprintf("%s (jade %t): vector %d out of bounds: %v", name, jade, i, x))
+						t.Fail()
+					}
+				}
+			}
+		}
+	}
+
+	if _, err := NewDifferentialEvolutionSolver(Problem{Lower: []float64{1}, Upper: []float64{0}, F: sphere}); err == nil {
+		t.Log("solver accepted a lower bound greater than the higher bound")
+		t.Fail()
+	}
+	de, _ := NewDifferentialEvolutionSolver(p)
+	if _, err := de.Solve(3, 10, 0.5, 0.9); err == nil {
+		t.Log("solver accepted a population of 3 vectors")
+		t.Fail()
+	}
+}
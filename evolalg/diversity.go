@@ -0,0 +1,123 @@
+package evolalg
+
+import (
+	"math"
+	"sort"
+)
+
+// Percentiles contains the chosen percentiles of a set of values.
+type Percentiles struct {
+	P10 float64 `json:"p10"`
+	P25 float64 `json:"p25"`
+	P50 float64 `json:"p50"`
+	P75 float64 `json:"p75"`
+	P90 float64 `json:"p90"`
+}
+
+// Diversity contains the metrics describing how diverse a population is.
+type Diversity struct {
+	MeanHamming     float64     `json:"meanHamming"`     // mean hamming distance between every pair of genotypes
+	LocusEntropy    []float64   `json:"locusEntropy"`    // shannon entropy (in bits) of every locus
+	MeanEntropy     float64     `json:"meanEntropy"`     // mean of the LocusEntropy
+	UniqueGenotypes int         `json:"uniqueGenotypes"` // number of distinct genotypes
+	XStdDev         float64     `json:"xStdDev"`         // standard deviation of the decoded x
+	FitPercentiles  Percentiles `json:"fitPercentiles"`
+}
+
+// HammingDistance returns the number of loci on which the two genotypes differ. If the genotypes
+// are of different length then the missing loci are counted as different.
+func HammingDistance(x, y []byte) int {
+	if len(x) < len(y) {
+		x, y = y, x
+	}
+	dist := len(x) - len(y)
+	for i := 0; i < len(y); i++ {
+		if x[i] != y[i] {
+			dist++
+		}
+	}
+	return dist
+}
+
+// MeasureDiversity calculates the diversity metrics of the given population, its decoded values
+// and its fits.
+func MeasureDiversity(pop [][]byte, vals []float64, fits []float64) (div Diversity) {
+	N := len(pop)
+	if N == 0 {
+		return
+	}
+
+	// Mean pairwise hamming distance. Every locus contributes ones*zeros differing pairs so there
+	// is no need to compare every pair of genotypes.
+	l := len(pop[0])
+	div.LocusEntropy = make([]float64, l)
+	pairs := float64(N*(N-1)) / 2
+	for j := 0; j < l; j++ {
+		ones := 0
+		for i := 0; i < N; i++ {
+			if j < len(pop[i]) && pop[i][j] == 1 {
+				ones++
+			}
+		}
+		if pairs > 0 {
+			div.MeanHamming += float64(ones*(N-ones)) / pairs
+		}
+
+		p := float64(ones) / float64(N)
+		if p > 0 && p < 1 {
+			div.LocusEntropy[j] = -p*math.Log2(p) - (1-p)*math.Log2(1-p)
+		}
+		div.MeanEntropy += div.LocusEntropy[j]
+	}
+	if l > 0 {
+		div.MeanEntropy /= float64(l)
+	}
+
+	// Unique genotypes
+	unique := make(map[string]struct{}, N)
+	for i := 0; i < N; i++ {
+		unique[string(pop[i])] = struct{}{}
+	}
+	div.UniqueGenotypes = len(unique)
+
+	// Standard deviation of x
+	div.XStdDev = stdDev(vals)
+
+	div.FitPercentiles = MeasurePercentiles(fits)
+	return
+}
+
+// stdDev returns the population standard deviation of the given values.
+func stdDev(vals []float64) float64 {
+	if len(vals) == 0 {
+		return 0
+	}
+	mean := 0.0
+	for _, v := range vals {
+		mean += v
+	}
+	mean /= float64(len(vals))
+	variance := 0.0
+	for _, v := range vals {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance / float64(len(vals)))
+}
+
+// MeasurePercentiles calculates the percentiles of the values by linear interpolation between the
+// closest ranks.
+func MeasurePercentiles(vals []float64) (p Percentiles) {
+	if len(vals) == 0 {
+		return
+	}
+	sorted := append([]float64(nil), vals...)
+	sort.Float64s(sorted)
+	at := func(q float64) float64 {
+		pos := q * float64(len(sorted)-1)
+		lo := int(math.Floor(pos))
+		hi := int(math.Ceil(pos))
+		return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+	}
+	p.P10, p.P25, p.P50, p.P75, p.P90 = at(0.10), at(0.25), at(0.50), at(0.75), at(0.90)
+	return
+}
@@ -0,0 +1,46 @@
+package evolalg
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestMeasureDiversity(t *testing.T) {
+	pop := [][]byte{
+		{0, 0, 1, 1},
+		{0, 1, 1, 0},
+		{0, 0, 1, 1},
+		{1, 1, 1, 1},
+	}
+	div := MeasureDiversity(pop, []float64{1, 2, 3, 4}, []float64{1, 2, 3, 4})
+
+	// Compare against the naive pairwise definition
+	sum, pairs := 0, 0
+	for i := 0; i < len(pop); i++ {
+		for j := i + 1; j < len(pop); j++ {
+			sum += HammingDistance(pop[i], pop[j])
+			pairs++
+		}
+	}
+	if math.Abs(div.MeanHamming-float64(sum)/float64(pairs)) > 1e-9 {
+		t.Log(fmt.Sprintf("incorrect mean hamming distance - %f instead of %f", div.MeanHamming, float64(sum)/float64(pairs)))
+		t.Fail()
+	}
+	if div.UniqueGenotypes != 3 {
+		t.Log(fmt.Sprintf("incorrect number of unique genotypes - %d instead of 3", div.UniqueGenotypes))
+		t.Fail()
+	}
+	if div.LocusEntropy[2] != 0 || div.LocusEntropy[1] != 1 {
+		t.Log(fmt.Sprintf("incorrect locus entropy - %v", div.LocusEntropy))
+		t.Fail()
+	}
+	if math.Abs(div.XStdDev-math.Sqrt(1.25)) > 1e-9 {
+		t.Log(fmt.Sprintf("incorrect standard deviation - %f", div.XStdDev))
+		t.Fail()
+	}
+	if div.FitPercentiles.P50 != 2.5 {
+		t.Log(fmt.Sprintf("incorrect median fit - %f", div.FitPercentiles.P50))
+		t.Fail()
+	}
+}
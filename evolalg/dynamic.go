@@ -0,0 +1,150 @@
+package evolalg
+
+import (
+	"errors"
+	"math"
+)
+
+// ChangeResponse describes how the genetic algorithm reacts to the changes of a dynamic objective.
+// A change is detected when the grade of the elite graded anew at the start of an epoch differs
+// from its previous grade by more than the tolerance.
+type ChangeResponse int
+
+const (
+	// IgnoreChanges doesn't look for the changes, so the population keeps its outdated grades. This
+	// is the default.
+	IgnoreChanges ChangeResponse = iota
+	// Reevaluate grades the population anew and picks a new elite after a change.
+	Reevaluate
+	// Hypermutation additionally multiplies the mutation probability for a few epochs after a change.
+	Hypermutation
+	// Memory additionally remembers the elite from before every change and puts the remembered
+	// individuals back in the place of the worst ones if they're better after a change.
+	Memory
+)
+
+// ChangeResponseByName returns the response to changes based on its name: "none", "reevaluate",
+// "hypermutation" or "memory". An empty name returns the default one.
+func ChangeResponseByName(name string) (ChangeResponse, error) {
+	switch name {
+	case "", "none":
+		return IgnoreChanges, nil
+	case "reevaluate":
+		return Reevaluate, nil
+	case "hypermutation":
+		return Hypermutation, nil
+	case "memory":
+		return Memory, nil
+	}
+	return IgnoreChanges, errors.New("unknown response to changes " + name)
+}
+
+// Dynamics configures the detection of the changes of a dynamic objective and the response to them.
+type Dynamics struct {
+	Response   ChangeResponse
+	Tolerance  float64 // largest difference of the grades of the elite that is not a change (e.g. for noise)
+	Rate       float64 // mutation probability multiplier of Hypermutation (10 if 0)
+	Epochs     int     // amount of epochs of Hypermutation after a change (3 if 0)
+	MemorySize int     // amount of remembered elites of Memory (5 if 0)
+}
+
+// remembered is an elite remembered by the Memory response.
+type remembered struct {
+	x  float64
+	id int // genealogy ID (0 if the genealogy is not recorded)
+}
+
+// SetDynamics sets the detection of the changes of a dynamic objective (see SetObjective) and the
+// response to them.
+func (gas *GeneticAlgorithmSolver) SetDynamics(dyn Dynamics) error {
+	if dyn.Tolerance < 0 || dyn.Rate < 0 || dyn.Epochs < 0 || dyn.MemorySize < 0 {
+		return errors.New("parameters of the response to changes can't be negative")
+	}
+	if dyn.Rate == 0 {
+		dyn.Rate = 10
+	}
+	if dyn.Epochs == 0 {
+		dyn.Epochs = 3
+	}
+	if dyn.MemorySize == 0 {
+		dyn.MemorySize = 5
+	}
+	gas.dynamics = dyn
+	return nil
+}
+
+// resetDynamics forgets the memory and the hypermutation of the previous run.
+func (gas *GeneticAlgorithmSolver) resetDynamics() {
+	gas.memory, gas.hyperUntil = nil, -1
+}
+
+// respondToChange grades the elite anew and, if it has changed, responds to the change of the
+// objective. Returns whether a change was detected.
+func (gas *GeneticAlgorithmSolver) respondToChange() (bool, error) {
+	dyn := gas.dynamics
+	if dyn.Response == IgnoreChanges {
+		return false, nil
+	}
+	grade, old := gas.Grade(gas.elite), gas.eliteGrade
+	if math.Abs(grade-old) <= dyn.Tolerance {
+		return false, nil
+	}
+
+	if dyn.Response == Memory {
+		id := 0
+		if gas.genealogy != nil {
+			id = gas.genealogy.EliteID
+		}
+		gas.memory = append(gas.memory, remembered{gas.elite, id})
+		if len(gas.memory) > dyn.MemorySize {
+			gas.memory = gas.memory[1:]
+		}
+	}
+
+	// The outdated grades and elite are replaced with the current ones
+	err := gas.Selection(gas.popVals...)
+	if err != nil {
+		return true, err
+	}
+	if dyn.Response == Memory {
+		gas.recall()
+	}
+	gas.eliteFit, gas.eliteGrade = -10000000, -10000000
+	gas.updateElite(gas.popVals)
+
+	if dyn.Response == Hypermutation {
+		gas.hyperUntil = gas.epoch + dyn.Epochs - 1
+	}
+	return true, nil
+}
+
+// recall puts the remembered elites in the place of the worst individuals of the population if
+// they're better than them in the changed environment.
+func (gas *GeneticAlgorithmSolver) recall() {
+	for k := len(gas.memory) - 1; k >= 0; k-- {
+		m := gas.memory[k]
+		grade := gas.Grade(m.x)
+		fit := gas.fitFromGrade(grade)
+		worst := gas.worstIndex()
+		if fit <= gas.fitCache[worst] {
+			continue
+		}
+
+		genome := gas.XIntToXBin(uint32(gas.XRealToXInt(m.x)))
+		gas.popArr[worst], gas.popVals[worst], gas.popAge[worst] = genome, m.x, gas.epoch
+		gas.gradeCache[worst], gas.fitCache[worst] = grade, fit
+		if gas.popID != nil {
+			gas.popID[worst] = gas.born(Individual{Parents: []int{m.id}, Operator: OpMemory, CutPoint: -1}, genome, m.x, grade)
+		}
+	}
+	gas.roulette(gas.popVals)
+}
+
+// hypermutate returns the mutation probability of the epoch raised by Hypermutation.
+func (gas *GeneticAlgorithmSolver) hypermutate(mp float64) float64 {
+	if gas.epoch <= gas.hyperUntil {
+		mp = math.Min(1, mp*gas.dynamics.Rate)
+		gas.epochMP = mp
+	}
+	return mp
+}
@@ -0,0 +1,127 @@
+package evolalg
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// flipping is an objective that alternates every 10 epochs between favouring the upper and the lower
+// bound of <-4, 12>.
+func flipping(x float64, epoch int, _ *rand.Rand) float64 {
+	if (epoch/10)%2 == 1 {
+		return 12 - x
+	}
+	return x + 4
+}
+
+func TestChangeResponseByName(t *testing.T) {
+	for name, want := range map[string]ChangeResponse{"": IgnoreChanges, "none": IgnoreChanges,
+		"reevaluate": Reevaluate, "hypermutation": Hypermutation, "memory": Memory} {
+		if r, err := ChangeResponseByName(name); err != nil || r != want {
+			t.Log(fmt.Sprintf("%q was parsed as %d (%v)", name, r, err))
+			t.Fail()
+		}
+	}
+	if _, err := ChangeResponseByName("x"); err == nil {
+		t.Log("unknown response was parsed")
+		t.Fail()
+	}
+}
+
+// dynamicRun runs the genetic algorithm on the flipping objective with the given dynamics.
+func dynamicRun(t *testing.T, dyn Dynamics, genealogy bool) []EpochData {
+	gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, func(x float64) float64 { return x + 4 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = gas.SetDynamics(dyn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gas.SetObjective(flipping)
+	gas.SetGenealogy(genealogy)
+	gas.SetSeed(1)
+	hist, err := gas.Solve(30, 25, 0.75, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hist
+}
+
+func TestChangeDetection(t *testing.T) {
+	gas, _ := NewGeneticAlgorithmSolver(-4, 12, 3, LabFunction)
+	if err := gas.SetDynamics(Dynamics{Response: Reevaluate, Tolerance: -1}); err == nil {
+		t.Log("negative tolerance was accepted")
+		t.Fail()
+	}
+
+	for _, response := range []ChangeResponse{IgnoreChanges, Reevaluate, Hypermutation, Memory} {
+		hist := dynamicRun(t, Dynamics{Response: response}, false)
+		for e, ed := range hist {
+			want := response != IgnoreChanges && (e == 10 || e == 20)
+			if ed.Change != want {
+				t.Log(fmt.Sprintf("response %d: change of epoch %d was reported as %t", response, e, ed.Change))
+				t.Fail()
+			}
+		}
+
+		// The outdated elite is replaced right away only if the change is detected
+		if response == IgnoreChanges && hist[10].Elite < hist[9].Elite {
+			t.Log(fmt.Sprintf("ignored change replaced the elite %f with %f", hist[9].Elite, hist[10].Elite))
+			t.Fail()
+		} else if response != IgnoreChanges && hist[10].Elite >= hist[9].Elite {
+			t.Log(fmt.Sprintf("response %d kept the outdated elite %f", response, hist[10].Elite))
+			t.Fail()
+		}
+	}
+}
+
+func TestHypermutation(t *testing.T) {
+	hist := dynamicRun(t, Dynamics{Response: Hypermutation, Rate: 20, Epochs: 2}, false)
+	for e, ed := range hist[1:] {
+		e++
+		want := 0.01
+		if e == 10 || e == 11 || e == 20 || e == 21 {
+			want = 0.2
+		}
+		if ed.MP != want {
+			t.Log(fmt.Sprintf("epoch %d used the mutation probability %f instead of %f", e, ed.MP, want))
+			t.Fail()
+		}
+	}
+}
+
+func TestMemory(t *testing.T) {
+	hist := dynamicRun(t, Dynamics{Response: Memory}, true)
+
+	// The elite from before the first change is the best individual of the first landscape, so it's
+	// put back when the landscape returns
+	remembered := -4.0
+	for _, ed := range hist[:10] {
+		for _, x := range ed.PopulationF64 {
+			if x > remembered {
+				remembered = x
+			}
+		}
+	}
+	if hist[20].Elite < remembered {
+		t.Log(fmt.Sprintf("elite %f of the returned landscape is worse than the remembered %f", hist[20].Elite, remembered))
+		t.Fail()
+	}
+
+	found := false
+	for _, ind := range hist[len(hist)-1].Genealogy.Individuals {
+		if ind.Operator == OpMemory {
+			found = found || (ind.Epoch == 20 && ind.X == remembered)
+			if ind.Epoch != 10 && ind.Epoch != 20 {
+				t.Log(fmt.Sprintf("remembered individual was put back in epoch %d", ind.Epoch))
+				t.Fail()
+			}
+		}
+	}
+	if !found {
+		t.Log(fmt.Sprintf("genealogy doesn't record putting back %f", remembered))
+		t.Fail()
+	}
+}
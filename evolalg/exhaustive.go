@@ -0,0 +1,177 @@
+package evolalg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// MaxExhaustiveBits is the length of the longest genome whose search space can be enumerated by
+// ExactOptimum.
+const MaxExhaustiveBits = 24
+
+// exactBatch is the amount of points graded by ExactOptimum with a single call of the fitness.
+const exactBatch = 1024
+
+// ExactOptimum is the global optimum of a discretized problem found by grading every point of its
+// search space.
+type ExactOptimum struct {
+	X      float64 `json:"x"`
+	Grade  float64 `json:"grade"`
+	Points int     `json:"points"` // amount of graded points (2^l)
+}
+
+// Reference compares a run with the exact optimum of its problem.
+type Reference struct {
+	ExactOptimum
+	Gap      float64 `json:"gap"`      // grade of the optimum minus the best grade found so far
+	HitEpoch int     `json:"hitEpoch"` // first epoch in which the optimum was found (-1 if it wasn't)
+}
+
+// Best returns the best grade found by the run.
+func (r Reference) Best() float64 {
+	return r.Grade - r.Gap
+}
+
+// ExactOptimum grades all of the 2^l points of the search space of the solver with the given
+// amount of goroutines (runtime.NumCPU() if 0) and returns the best one. Of the points with equal
+// grades the one with the lowest genome is returned. The gradings are not counted as fitness
+// evaluations, the fitness has to be safe for concurrent use and any failure of it is returned.
+func (gas *GeneticAlgorithmSolver) ExactOptimum(workers int) (ExactOptimum, error) {
+	if gas.objective != nil {
+		return ExactOptimum{}, errors.New("exact optimum of a noisy or dynamic objective is undefined")
+	} else if gas.l > MaxExhaustiveBits {
+		return ExactOptimum{}, fmt.Errorf("search space of 2^%d points is too large to be enumerated", gas.l)
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	points := 1 << uint(gas.l)
+	if workers > points {
+		workers = points
+	}
+
+	// Every worker grades a contiguous chunk of the genomes in batches
+	best := make([]ExactOptimum, workers)
+	errs := make([]error, workers)
+	ctx := gas.context()
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			opt := ExactOptimum{Grade: math.Inf(-1)}
+			end := (w + 1) * points / workers
+			for from := w * points / workers; from < end; from += exactBatch {
+				xs := make([]float64, 0, exactBatch)
+				for xint := from; xint < end && len(xs) < exactBatch; xint++ {
+					xs = append(xs, gas.XIntToXReal(xint))
+				}
+				grades, failed := gas.fitness.Evaluate(ctx, xs)
+				for i, grade := range grades {
+					if failed != nil && failed[i] != nil {
+						errs[w] = &EvaluationError{X: xs[i], Attempts: 1, Err: failed[i]}
+						return
+					}
+					if grade > opt.Grade {
+						opt.X, opt.Grade = xs[i], grade
+					}
+				}
+			}
+			best[w] = opt
+		}(w)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return ExactOptimum{}, err
+		}
+	}
+
+	opt := best[0]
+	for _, o := range best[1:] {
+		if o.Grade > opt.Grade {
+			opt = o
+		}
+	}
+	opt.Points = points
+	return opt, nil
+}
+
+// exactKey identifies the search space of an exact optimum in the cache.
+type exactKey struct {
+	name string
+	a, b float64
+	d    byte
+	l    int
+}
+
+// exactEntry is an exact optimum computed (or being computed) for the cache.
+type exactEntry struct {
+	once sync.Once
+	opt  ExactOptimum
+	err  error
+}
+
+var (
+	exactMu    sync.Mutex
+	exactCache = make(map[exactKey]*exactEntry)
+)
+
+// CachedExactOptimum returns ExactOptimum of the solver computed only once for every problem name
+// and encoding. Concurrent calls for the same problem wait for a single computation. An empty name
+// disables the caching as the grading functions can't be told apart.
+func (gas *GeneticAlgorithmSolver) CachedExactOptimum(name string, workers int) (ExactOptimum, error) {
+	if name == "" || gas.objective != nil {
+		return gas.ExactOptimum(workers)
+	}
+	key := exactKey{name, gas.a, gas.b, gas.d, gas.l}
+	exactMu.Lock()
+	e, ok := exactCache[key]
+	if !ok {
+		e = &exactEntry{}
+		exactCache[key] = e
+	}
+	exactMu.Unlock()
+
+	e.once.Do(func() { e.opt, e.err = gas.ExactOptimum(workers) })
+	return e.opt, e.err
+}
+
+// SetReference makes the solver compare every epoch of its runs with the given exact optimum. The
+// comparison is saved in the Reference of the history entries.
+func (gas *GeneticAlgorithmSolver) SetReference(opt ExactOptimum) {
+	gas.reference = &Reference{ExactOptimum: opt, HitEpoch: -1}
+}
+
+// resetReference forgets the epoch in which the optimum was found by the previous run.
+func (gas *GeneticAlgorithmSolver) resetReference() {
+	if gas.reference != nil {
+		gas.reference.HitEpoch = -1
+	}
+}
+
+// compareWithReference saves the comparison of the best grade found so far with the exact optimum
+// in the history entry. The grades predicted by the surrogate model are not taken into account.
+func (gas GeneticAlgorithmSolver) compareWithReference(ed *EpochData) {
+	if gas.reference == nil {
+		return
+	}
+	best := gas.eliteGrade
+	for i, x := range gas.popVals {
+		if gas.realGrade(x) && gas.gradeCache[i] > best {
+			best = gas.gradeCache[i]
+		}
+	}
+	if gas.reference.HitEpoch < 0 && best >= gas.reference.Grade {
+		gas.reference.HitEpoch = gas.epoch
+	}
+	ref := *gas.reference
+	ref.Gap = math.Max(0, ref.Grade-best)
+	if ref.HitEpoch >= 0 {
+		ref.Gap = 0
+	}
+	ed.Reference = &ref
+}
@@ -0,0 +1,177 @@
+package evolalg
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+func TestExactOptimum(t *testing.T) {
+	gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, LabFunction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Compare with grading the points one by one
+	want := ExactOptimum{Grade: math.Inf(-1), Points: 1 << uint(gas.L())}
+	for xint := 0; xint < want.Points; xint++ {
+		x := gas.XIntToXReal(xint)
+		if grade := LabFunction(x); grade > want.Grade {
+			want.X, want.Grade = x, grade
+		}
+	}
+	for _, workers := range []int{1, 3, 8, 0} {
+		opt, err := gas.ExactOptimum(workers)
+		if err != nil {
+			t.Fatal(err)
+		} else if opt != want {
+			t.Log(fmt.Sprintf("%d workers found %+v instead of %+v", workers, opt, want))
+			t.Fail()
+		}
+	}
+	if gas.Evaluations() != 0 {
+		t.Log(fmt.Sprintf("exact optimum made %d fitness evaluations", gas.Evaluations()))
+		t.Fail()
+	}
+
+	// Genotypes are enumerated as well
+	ones := func(genome []byte) (n float64) {
+		for _, bit := range genome {
+			n += float64(bit)
+		}
+		return
+	}
+	gs, err := NewGenotypeSolver(GenomeFunc{L: 10, F: ones})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opt, err := gs.ExactOptimum(0)
+	if err != nil {
+		t.Fatal(err)
+	} else if opt.Grade != 10 || opt.Points != 1024 {
+		t.Log(fmt.Sprintf("optimum of OneMax is %+v", opt))
+		t.Fail()
+	}
+	gs, _ = NewGenotypeSolver(GenomeFunc{L: MaxExhaustiveBits + 1, F: ones})
+	if _, err := gs.ExactOptimum(0); err == nil {
+		t.Log("too large search space was enumerated")
+		t.Fail()
+	}
+}
+
+func TestCachedExactOptimum(t *testing.T) {
+	var calls int64
+	f := func(x float64) float64 {
+		atomic.AddInt64(&calls, 1)
+		return -x * x
+	}
+	solver := func(d byte) GeneticAlgorithmSolver {
+		gas, err := NewGeneticAlgorithmSolver(-1, 1, d, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		atomic.StoreInt64(&calls, 0)
+		return gas
+	}
+
+	gas := solver(2)
+	first, err := gas.CachedExactOptimum("cache test", 2)
+	if err != nil {
+		t.Fatal(err)
+	} else if calls != int64(first.Points) || first.Grade != 0 {
+		t.Log(fmt.Sprintf("first computation made %d gradings and found %+v", calls, first))
+		t.Fail()
+	}
+	gas = solver(2)
+	second, _ := gas.CachedExactOptimum("cache test", 2)
+	if calls != 0 || second != first {
+		t.Log(fmt.Sprintf("cached computation made %d gradings and found %+v", calls, second))
+		t.Fail()
+	}
+
+	// A different encoding or no name are computed anew
+	gas = solver(3)
+	third, _ := gas.CachedExactOptimum("cache test", 2)
+	if calls != int64(third.Points) || third.Points == first.Points {
+		t.Log(fmt.Sprintf("computation with another accuracy made %d gradings of %d points", calls, third.Points))
+		t.Fail()
+	}
+	gas = solver(2)
+	gas.CachedExactOptimum("", 2)
+	if calls != int64(first.Points) {
+		t.Log(fmt.Sprintf("computation without a name made %d gradings", calls))
+		t.Fail()
+	}
+}
+
+func TestReference(t *testing.T) {
+	ones := func(genome []byte) float64 {
+		n := 0.0
+		for _, bit := range genome {
+			n += float64(bit)
+		}
+		return n
+	}
+	gas, err := NewGenotypeSolver(GenomeFunc{L: 8, F: ones})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opt, err := gas.ExactOptimum(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gas.SetSeed(1)
+	gas.SetReference(opt)
+	for run := 0; run < 2; run++ {
+		hist, err := gas.Solve(20, 60, 0.75, 0.01)
+		if err != nil {
+			t.Fatal(err)
+		}
+		last := hist[len(hist)-1].Reference
+		if last == nil || last.HitEpoch < 0 || last.Gap != 0 || last.Grade != 8 {
+			t.Log(fmt.Sprintf("run %d ended with reference %+v", run, last))
+			t.Fail()
+			continue
+		}
+		for e, ed := range hist {
+			hit := e >= last.HitEpoch
+			if (ed.Reference.HitEpoch >= 0) != hit || (ed.Reference.Gap == 0) != hit {
+				t.Log(fmt.Sprintf("run %d: epoch %d has reference %+v although optimum was hit in %d", run, e, ed.Reference,
+					last.HitEpoch))
+				t.Fail()
+			}
+		}
+	}
+
+	// The registry computes the reference when asked to
+	s, err := newGeneticAlgorithm(Problem{Genotype: GenomeFunc{L: 8, F: ones}, Name: "ones"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Configure(Params{"optimum": "true", "epoki": "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hist, err := s.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	} else if ref := hist[len(hist)-1].Reference; ref == nil || ref.Grade != 8 {
+		t.Log(fmt.Sprintf("registry run ended with reference %+v", ref))
+		t.Fail()
+	}
+
+	// The optimum of a problem graded in batches would grade its whole search space
+	f := &countingFitness{}
+	s, err = newGeneticAlgorithm(Problem{Lower: []float64{-4}, Upper: []float64{12}, Fitness: f, Name: "batch"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	graded := f.graded
+	if err = s.Configure(Params{"optimum": "true", "epoki": "3"}); err == nil || f.graded-graded > 1<<10 {
+		t.Log(fmt.Sprintf("optimum of a problem graded in batches was configured with %d evaluations",
+			f.graded-graded))
+		t.Fail()
+	}
+}
@@ -0,0 +1,384 @@
+// Package external grades candidates with external programs, e.g. Python scripts or models exported
+// from MATLAB. An Evaluator launches the command once and keeps it alive, exchanging JSON lines over
+// its standard input and output: for every candidate it writes a line
+//
+//	{"x": 1.5}
+//
+// and reads back either the grade or the error of the evaluation
+//
+//	{"fitness": 0.75}
+//	{"error": "outside of the model domain"}
+//
+// A pool of several processes grades the candidates of a batch in parallel. An evaluation that
+// exceeds the timeout or is cancelled kills its process and a process that exited is started again
+// on the next evaluation, so a single crash fails only the candidate being graded.
+package external
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrTimeout is the error of the evaluations that exceeded the timeout.
+	ErrTimeout = errors.New("evaluation timed out")
+	// ErrCrashed is the error of the evaluations interrupted by the exit of the process.
+	ErrCrashed = errors.New("process exited")
+	// ErrClosed is the error of the evaluations requested after closing the evaluator.
+	ErrClosed = errors.New("evaluator is closed")
+)
+
+// stopGrace is the time a process gets to exit on its own after its input is closed or after it's
+// killed.
+const stopGrace = time.Second
+
+// Config describes the external program and its pool of processes.
+type Config struct {
+	Command   []string      // program and its arguments
+	Dir       string        // working directory of the processes (the current one if empty)
+	Processes int           // amount of the processes grading in parallel (1 if 0)
+	Timeout   time.Duration // limit of a single evaluation (none if 0)
+}
+
+// Evaluator grades the candidates with a pool of processes of an external program. It implements
+// evolalg.Fitness and is safe for concurrent use. Close has to be called to stop the processes.
+type Evaluator struct {
+	cfg  Config
+	idle chan *process // processes that aren't grading at the moment (nil if not started yet)
+
+	mu       sync.Mutex
+	closed   bool
+	restarts int
+}
+
+// NewEvaluator creates the evaluator of the program. The processes are started on their first
+// evaluation.
+func NewEvaluator(cfg Config) (*Evaluator, error) {
+	if len(cfg.Command) == 0 || cfg.Command[0] == "" {
+		return nil, errors.New("no command of the external program")
+	} else if cfg.Processes < 0 {
+		return nil, errors.New("amount of the processes can't be negative")
+	} else if cfg.Timeout < 0 {
+		return nil, errors.New("timeout can't be negative")
+	}
+	if _, err := exec.LookPath(cfg.Command[0]); err != nil {
+		return nil, err
+	}
+	if cfg.Processes == 0 {
+		cfg.Processes = 1
+	}
+	e := &Evaluator{cfg: cfg, idle: make(chan *process, cfg.Processes)}
+	for i := 0; i < cfg.Processes; i++ {
+		e.idle <- nil
+	}
+	return e, nil
+}
+
+// Evaluate grades the candidates in parallel with the processes of the pool. The candidates left
+// when ctx is cancelled fail with its error.
+func (e *Evaluator) Evaluate(ctx context.Context, xs []float64) (grades []float64, errs []error) {
+	grades = make([]float64, len(xs))
+	jobs := make(chan int, len(xs))
+	for i := range xs {
+		jobs <- i
+	}
+	close(jobs)
+
+	var mu sync.Mutex
+	fail := func(i int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if errs == nil {
+			errs = make([]error, len(xs))
+		}
+		errs[i] = err
+	}
+
+	workers := e.cfg.Processes
+	if len(xs) < workers {
+		workers = len(xs)
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			var p *process
+			select {
+			case p = <-e.idle:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { e.idle <- p }()
+			for i := range jobs {
+				var grade float64
+				var err error
+				p, grade, err = e.grade(ctx, p, xs[i])
+				if err != nil {
+					fail(i, err)
+					continue
+				}
+				grades[i] = grade
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The candidates of the workers that gave up waiting for a process
+	for i := range jobs {
+		fail(i, ctx.Err())
+	}
+	return
+}
+
+// Restarts returns the amount of the processes started again after the previous one exited or was
+// killed.
+func (e *Evaluator) Restarts() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.restarts
+}
+
+// Close stops all of the processes after their current evaluations. The processes get a moment to
+// exit on their own after their input is closed and are killed afterwards.
+func (e *Evaluator) Close() error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil
+	}
+	e.closed = true
+	e.mu.Unlock()
+
+	pool := make([]*process, 0, e.cfg.Processes)
+	for i := 0; i < e.cfg.Processes; i++ {
+		p := <-e.idle
+		if p != nil {
+			p.stop()
+		}
+		pool = append(pool, p)
+	}
+	for _, p := range pool {
+		e.idle <- p
+	}
+	return nil
+}
+
+// request is the line sent to the program for every candidate.
+type request struct {
+	X float64 `json:"x"`
+}
+
+// response is the line read back from the program.
+type response struct {
+	Fitness *float64 `json:"fitness"`
+	Error   string   `json:"error"`
+}
+
+// grade evaluates x with the process, replacing it with a new one first if it isn't running. It
+// returns the process that graded x.
+func (e *Evaluator) grade(ctx context.Context, p *process, x float64) (*process, float64, error) {
+	e.mu.Lock()
+	closed := e.closed
+	e.mu.Unlock()
+	if closed {
+		return p, 0, ErrClosed
+	} else if err := ctx.Err(); err != nil {
+		return p, 0, err
+	}
+
+	if !p.running() {
+		if p != nil {
+			e.mu.Lock()
+			e.restarts++
+			e.mu.Unlock()
+		}
+		np, err := start(e.cfg)
+		if err != nil {
+			return p, 0, err
+		}
+		p = np
+	}
+
+	line, _ := json.Marshal(request{X: x})
+	if _, err := p.stdin.Write(append(line, '\n')); err != nil {
+		return p, 0, p.crashed()
+	}
+
+	var timeout <-chan time.Time
+	if e.cfg.Timeout > 0 {
+		timer := time.NewTimer(e.cfg.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case line, ok := <-p.lines:
+		if !ok {
+			return p, 0, p.crashed()
+		}
+		var res response
+		err := json.Unmarshal(line, &res)
+		if err == nil && res.Error == "" && res.Fitness == nil {
+			err = errors.New("no fitness nor error")
+		}
+		if err != nil {
+			// The output can't be trusted to stay in step with the requests anymore
+			p.kill()
+			return p, 0, fmt.Errorf("invalid response %q: %w", line, err)
+		} else if res.Error != "" {
+			return p, 0, errors.New(res.Error)
+		}
+		return p, *res.Fitness, nil
+	case <-timeout:
+		p.kill()
+		return p, 0, fmt.Errorf("%w after %s", ErrTimeout, e.cfg.Timeout)
+	case <-ctx.Done():
+		p.kill()
+		return p, 0, ctx.Err()
+	}
+}
+
+// process is a running process of the program (or one that already exited or was killed). It's
+// used by a single goroutine at a time.
+type process struct {
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	stderr   *tail
+	lines    chan []byte   // lines of the output, closed when the output ends
+	quit     chan struct{} // closed to stop passing the lines of the output
+	quitOnce sync.Once
+	done     chan struct{} // closed once the process exited
+	err      error         // result of waiting for the process (valid once done is closed)
+	killed   bool          // the process was killed (its output may not have ended yet)
+}
+
+// start launches a new process of the program.
+func start(cfg Config) (*process, error) {
+	cmd := exec.Command(cfg.Command[0], cfg.Command[1:]...)
+	cmd.Dir = cfg.Dir
+	isolate(cmd)
+	p := &process{cmd: cmd, stderr: &tail{}, lines: make(chan []byte), quit: make(chan struct{}),
+		done: make(chan struct{})}
+	cmd.Stderr = p.stderr
+	var err error
+	if p.stdin, err = cmd.StdinPipe(); err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		s := bufio.NewScanner(stdout)
+		s.Buffer(nil, 1<<20)
+		for s.Scan() {
+			select {
+			case p.lines <- append([]byte(nil), s.Bytes()...):
+			case <-p.quit:
+			}
+		}
+		close(p.lines)
+		p.err = cmd.Wait()
+		close(p.done)
+	}()
+	return p, nil
+}
+
+// running checks if the process was started and has neither exited nor been killed.
+func (p *process) running() bool {
+	if p == nil || p.killed {
+		return false
+	}
+	select {
+	case <-p.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// crashed kills what's left of the process and returns the error describing its exit.
+func (p *process) crashed() error {
+	p.kill()
+	select {
+	case <-p.done:
+	default:
+		return fmt.Errorf("%w: output of %s ended", ErrCrashed, p.cmd.Path)
+	}
+	msg := ""
+	if p.err != nil {
+		msg = ": " + p.err.Error()
+	}
+	if s := strings.TrimSpace(p.stderr.String()); s != "" {
+		msg += ": " + s
+	}
+	return fmt.Errorf("%w%s", ErrCrashed, msg)
+}
+
+// kill stops the process together with the processes it forked right away and waits a moment for
+// it to exit. The process isn't used again even if its output doesn't end in that time.
+func (p *process) kill() {
+	if !p.running() {
+		return
+	}
+	p.killed = true
+	p.quitOnce.Do(func() { close(p.quit) })
+	killGroup(p.cmd)
+	select {
+	case <-p.done:
+	case <-time.After(stopGrace):
+	}
+}
+
+// stop closes the input of the process, so that it can exit on its own, and kills it if it doesn't.
+func (p *process) stop() {
+	if !p.running() {
+		return
+	}
+	p.stdin.Close()
+	select {
+	case <-p.done:
+	case <-time.After(stopGrace):
+		p.kill()
+	}
+}
+
+// tailSize is the amount of the last bytes of the error output of a process kept for the error
+// messages.
+const tailSize = 512
+
+// tail is a writer keeping the end of the error output of a process.
+type tail struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+// Write appends the bytes to the kept end of the output.
+func (t *tail) Write(b []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, b...)
+	if len(t.buf) > tailSize {
+		t.buf = append([]byte(nil), t.buf[len(t.buf)-tailSize:]...)
+	}
+	return len(b), nil
+}
+
+// String returns the kept end of the output.
+func (t *tail) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return string(t.buf)
+}
@@ -0,0 +1,205 @@
+package external
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/TheSlipper/isa/evolalg"
+)
+
+// hill is a smooth function with the maximum 5 at x = 3.
+func hill(x float64) float64 {
+	return 5 - (x-3)*(x-3)/10
+}
+
+// Special candidates making the helper process misbehave.
+const (
+	crashX   = 100 // exits without answering
+	hangX    = 101 // never answers
+	errorX   = 102 // answers with an error
+	garbageX = 103 // answers with a line that isn't JSON
+	pidX     = 104 // answers with its process ID after a moment
+)
+
+// TestHelperProcess is the external program of the tests when run with the "helper" argument.
+func TestHelperProcess(t *testing.T) {
+	if len(os.Args) < 2 {
+		return
+	}
+	switch os.Args[len(os.Args)-1] {
+	case "helper":
+	case "wrapper":
+		// The wrapper forks a child holding its output open before answering
+		child := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$", "--", "child")
+		child.Stdout = os.Stdout
+		if err := child.Start(); err != nil {
+			os.Exit(2)
+		}
+	case "child":
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	default:
+		return
+	}
+	s := bufio.NewScanner(os.Stdin)
+	for s.Scan() {
+		var req request
+		if err := json.Unmarshal(s.Bytes(), &req); err != nil {
+			fmt.Println(`{"error": "bad request"}`)
+			continue
+		}
+		switch req.X {
+		case crashX:
+			fmt.Fprintln(os.Stderr, "model diverged")
+			os.Exit(3)
+		case hangX:
+			time.Sleep(time.Minute)
+		case errorX:
+			fmt.Println(`{"error": "outside of the domain"}`)
+		case garbageX:
+			fmt.Println("fitness: 1")
+		case pidX:
+			time.Sleep(50 * time.Millisecond)
+			fmt.Printf("{\"fitness\": %d}\n", os.Getpid())
+		default:
+			fmt.Printf("{\"fitness\": %g}\n", hill(req.X))
+		}
+	}
+	os.Exit(0)
+}
+
+// helper creates the evaluator running the helper process.
+func helper(t *testing.T, processes int, timeout time.Duration) *Evaluator {
+	return helperMode(t, "helper", processes, timeout)
+}
+
+// helperMode creates the evaluator running the helper process in the given mode.
+func helperMode(t *testing.T, mode string, processes int, timeout time.Duration) *Evaluator {
+	e, err := NewEvaluator(Config{Command: []string{os.Args[0], "-test.run=^TestHelperProcess$", "--", mode},
+		Processes: processes, Timeout: timeout})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestNewEvaluator(t *testing.T) {
+	for _, cfg := range []Config{{}, {Command: []string{"isa-no-such-program"}},
+		{Command: []string{os.Args[0]}, Processes: -1}, {Command: []string{os.Args[0]}, Timeout: -1}} {
+		if _, err := NewEvaluator(cfg); err == nil {
+			t.Log(fmt.Sprintf("configuration %v was accepted", cfg))
+			t.Fail()
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	e := helper(t, 1, 500*time.Millisecond)
+	defer e.Close()
+
+	xs := []float64{1, crashX, 2, hangX, 3, errorX, 4, garbageX, 5}
+	grades, errs := e.Evaluate(context.Background(), xs)
+	if errs == nil {
+		t.Fatal("no evaluation failed")
+	}
+	for i, x := range xs {
+		if x < crashX && (errs[i] != nil || grades[i] != hill(x)) {
+			t.Log(fmt.Sprintf("%f was graded %f with the error %v", x, grades[i], errs[i]))
+			t.Fail()
+		}
+	}
+	if !errors.Is(errs[1], ErrCrashed) || !errors.Is(errs[3], ErrTimeout) || errs[5] == nil ||
+		errs[5].Error() != "outside of the domain" || errs[7] == nil {
+		t.Log(fmt.Sprintf("failures were reported as %v", errs))
+		t.Fail()
+	}
+
+	// The process is started again after the crash, the timeout and the invalid response
+	if e.Restarts() != 3 {
+		t.Log(fmt.Sprintf("process was restarted %d times", e.Restarts()))
+		t.Fail()
+	}
+
+	e.Close()
+	if _, errs = e.Evaluate(context.Background(), xs[:1]); errs == nil || !errors.Is(errs[0], ErrClosed) {
+		t.Log(fmt.Sprintf("closed evaluator returned %v", errs))
+		t.Fail()
+	}
+}
+
+func TestPool(t *testing.T) {
+	e := helper(t, 3, 0)
+	defer e.Close()
+
+	xs := make([]float64, 12)
+	for i := range xs {
+		xs[i] = pidX
+	}
+	grades, errs := e.Evaluate(context.Background(), xs)
+	if errs != nil {
+		t.Fatal(errs)
+	}
+	pids := make(map[float64]bool)
+	for _, g := range grades {
+		pids[g] = true
+	}
+	if len(pids) != 3 {
+		t.Log(fmt.Sprintf("batch was graded by %d processes", len(pids)))
+		t.Fail()
+	}
+
+	// The processes are kept alive between the batches
+	grades, _ = e.Evaluate(context.Background(), xs[:3])
+	for _, g := range grades {
+		if !pids[g] {
+			t.Log(fmt.Sprintf("process %f was started for the second batch", g))
+			t.Fail()
+		}
+	}
+}
+
+func TestCancel(t *testing.T) {
+	e := helper(t, 2, 0)
+	defer e.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, errs := e.Evaluate(ctx, []float64{hangX, hangX, 1, 2})
+	if time.Since(start) > 10*time.Second || errs == nil {
+		t.Fatal("cancelled evaluation didn't stop")
+	}
+	for i, err := range errs {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Log(fmt.Sprintf("candidate %d failed with %v", i, err))
+			t.Fail()
+		}
+	}
+}
+
+func TestGeneticAlgorithm(t *testing.T) {
+	e := helper(t, 2, 5*time.Second)
+	defer e.Close()
+
+	gas, err := evolalg.NewBatchGeneticAlgorithmSolver(-4, 12, 3, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gas.SetSeed(1)
+	hist, err := gas.Solve(20, 10, 0.75, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elite := hist[len(hist)-1].Elite; math.Abs(elite-3) > 1 {
+		t.Log(fmt.Sprintf("external hill was maximized at %f", elite))
+		t.Fail()
+	}
+}
@@ -0,0 +1,46 @@
+//go:build !windows
+// +build !windows
+
+package external
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestKillForkedChildren(t *testing.T) {
+	e := helperMode(t, "wrapper", 1, 300*time.Millisecond)
+	defer e.Close()
+
+	// The killed wrapper is replaced even though its child could keep its output open
+	_, errs := e.Evaluate(context.Background(), []float64{hangX})
+	if errs == nil {
+		t.Fatal("hanging evaluation succeeded")
+	}
+	for i := 1; i <= 3; i++ {
+		grades, errs := e.Evaluate(context.Background(), []float64{1})
+		if errs != nil || grades[0] != hill(1) {
+			t.Log(fmt.Sprintf("evaluation %d after the timeout graded %v with the errors %v", i, grades, errs))
+			t.Fail()
+		}
+	}
+	if e.Restarts() != 1 {
+		t.Log(fmt.Sprintf("process was restarted %d times", e.Restarts()))
+		t.Fail()
+	}
+
+	// The child is killed together with the wrapper, so the output of the wrapper ends
+	p, _, err := e.grade(context.Background(), nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.kill()
+	select {
+	case <-p.done:
+	case <-time.After(5 * time.Second):
+		t.Log("output of the killed wrapper didn't end")
+		t.Fail()
+	}
+}
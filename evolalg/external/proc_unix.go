@@ -0,0 +1,20 @@
+//go:build !windows
+// +build !windows
+
+package external
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// isolate starts the process in its own process group, so that the processes it forks can be killed
+// together with it.
+func isolate(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killGroup kills the process group of the started process.
+func killGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,11 @@
+package external
+
+import "os/exec"
+
+// isolate does nothing as there are no process groups to start the process in.
+func isolate(cmd *exec.Cmd) {}
+
+// killGroup kills only the process itself.
+func killGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
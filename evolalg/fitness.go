@@ -0,0 +1,245 @@
+package evolalg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Fitness grades whole batches of candidates, e.g. with a vectorized function or an external
+// simulator. Evaluate returns the grades of all of the candidates and the errors of the failed ones
+// at their indices. A nil errs slice means that all of the evaluations succeeded. The grades of the
+// failed candidates are ignored.
+type Fitness interface {
+	Evaluate(ctx context.Context, xs []float64) (grades []float64, errs []error)
+}
+
+// FitnessFunc adapts a plain grading function of a single point to the Fitness interface. The
+// candidates left when ctx is cancelled fail with its error.
+type FitnessFunc func(x float64) float64
+
+// Evaluate grades the candidates one by one.
+func (f FitnessFunc) Evaluate(ctx context.Context, xs []float64) (grades []float64, errs []error) {
+	grades = make([]float64, len(xs))
+	for i, x := range xs {
+		if err := ctx.Err(); err != nil {
+			return grades, failAll(errs, len(xs), i, err)
+		}
+		grades[i] = f(x)
+	}
+	return
+}
+
+// FallibleFunc adapts a grading function of a single point that can fail to the Fitness interface.
+type FallibleFunc func(ctx context.Context, x float64) (float64, error)
+
+// Evaluate grades the candidates one by one.
+func (f FallibleFunc) Evaluate(ctx context.Context, xs []float64) (grades []float64, errs []error) {
+	grades = make([]float64, len(xs))
+	for i, x := range xs {
+		if err := ctx.Err(); err != nil {
+			return grades, failAll(errs, len(xs), i, err)
+		}
+		var err error
+		grades[i], err = f(ctx, x)
+		if err != nil {
+			if errs == nil {
+				errs = make([]error, len(xs))
+			}
+			errs[i] = err
+		}
+	}
+	return
+}
+
+// BatchFunc adapts a vectorized grading function whose failure fails the whole batch to the Fitness
+// interface.
+type BatchFunc func(ctx context.Context, xs []float64) ([]float64, error)
+
+// Evaluate grades all of the candidates with a single call.
+func (f BatchFunc) Evaluate(ctx context.Context, xs []float64) ([]float64, []error) {
+	grades, err := f(ctx, xs)
+	if err == nil && len(grades) != len(xs) {
+		err = fmt.Errorf("batch of %d candidates was graded with %d values", len(xs), len(grades))
+	}
+	if err != nil {
+		return make([]float64, len(xs)), failAll(nil, len(xs), 0, err)
+	}
+	return grades, nil
+}
+
+// failAll sets err as the error of the candidates from the index from on.
+func failAll(errs []error, n, from int, err error) []error {
+	if errs == nil {
+		errs = make([]error, n)
+	}
+	for i := from; i < n; i++ {
+		errs[i] = err
+	}
+	return errs
+}
+
+// FailurePolicy describes how the solver handles failed fitness evaluations.
+type FailurePolicy int
+
+const (
+	// AbortOnFailure stops the run with the error of the first failed evaluation. This is the
+	// default.
+	AbortOnFailure FailurePolicy = iota
+	// RetryOnFailure evaluates the failed candidates again and aborts if they still fail after the
+	// given amount of retries.
+	RetryOnFailure
+	// WorstOnFailure gives the failed candidates the lowest grade the fits are based on, so that they
+	// are unlikely to be selected.
+	WorstOnFailure
+)
+
+// FailurePolicyByName returns the failure policy based on its name: "abort", "retry" or "worst". An
+// empty name returns the default one.
+func FailurePolicyByName(name string) (FailurePolicy, error) {
+	switch name {
+	case "", "abort":
+		return AbortOnFailure, nil
+	case "retry":
+		return RetryOnFailure, nil
+	case "worst":
+		return WorstOnFailure, nil
+	}
+	return AbortOnFailure, errors.New("unknown failure policy " + name)
+}
+
+// Failures configures the handling of failed fitness evaluations.
+type Failures struct {
+	Policy  FailurePolicy
+	Retries int // amount of retries of RetryOnFailure (3 if 0)
+}
+
+// EvaluationError is the error of a run aborted by a failed fitness evaluation.
+type EvaluationError struct {
+	X        float64 // candidate whose evaluation failed
+	Attempts int     // amount of the evaluations of the candidate
+	Err      error   // error of the last evaluation
+}
+
+// Error describes the failed evaluation.
+func (e *EvaluationError) Error() string {
+	return fmt.Sprintf("evaluation of %g failed after %d attempts: %s", e.X, e.Attempts, e.Err.Error())
+}
+
+// Unwrap returns the error of the last evaluation.
+func (e *EvaluationError) Unwrap() error {
+	return e.Err
+}
+
+// SetFitness makes the solver grade the candidates with the batch fitness instead of the one it was
+// created with. The lowest grade the fits are based on stays the same.
+func (gas *GeneticAlgorithmSolver) SetFitness(f Fitness) {
+	gas.fitness = f
+}
+
+// SetFailures sets the handling of failed fitness evaluations.
+func (gas *GeneticAlgorithmSolver) SetFailures(f Failures) error {
+	if f.Retries < 0 {
+		return errors.New("amount of retries can't be negative")
+	}
+	if f.Retries == 0 {
+		f.Retries = 3
+	}
+	gas.failures = f
+	return nil
+}
+
+// SetContext sets the context passed to the fitness. Once it's cancelled the evaluations fail with
+// its error. A nil context is replaced with context.Background().
+func (gas *GeneticAlgorithmSolver) SetContext(ctx context.Context) {
+	gas.ctx = ctx
+}
+
+// context returns the context passed to the fitness.
+func (gas *GeneticAlgorithmSolver) context() context.Context {
+	if gas.ctx == nil {
+		return context.Background()
+	}
+	return gas.ctx
+}
+
+// evaluate grades the batch with the fitness and handles the failed evaluations according to the
+// failure policy. Every attempt is counted as a fitness evaluation. A failure that aborts the run
+// is kept until the end of the epoch (see takeFailure) and the candidates graded after it get the
+// lowest grade.
+func (gas *GeneticAlgorithmSolver) evaluate(xs []float64) []float64 {
+	grades := make([]float64, len(xs))
+	pending := make([]int, len(xs))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	for attempt := 1; len(pending) > 0; attempt++ {
+		if gas.failure != nil {
+			break
+		}
+		batch := make([]float64, len(pending))
+		for k, i := range pending {
+			batch[k] = xs[i]
+		}
+		res, errs := gas.fitness.Evaluate(gas.context(), batch)
+		gas.evals += len(batch)
+
+		var failed []int
+		var first error
+		for k, i := range pending {
+			if errs != nil && errs[k] != nil {
+				if failed == nil {
+					first = errs[k]
+				}
+				failed = append(failed, i)
+				continue
+			}
+			grades[i] = res[k]
+			gas.archiveGrade(xs[i], res[k])
+		}
+		gas.failed += len(failed)
+		pending = failed
+		if len(pending) == 0 {
+			break
+		}
+
+		switch gas.failures.Policy {
+		case RetryOnFailure:
+			if attempt <= gas.failures.Retries && gas.context().Err() == nil {
+				continue
+			}
+		case WorstOnFailure:
+			for _, i := range pending {
+				grades[i] = gas.fmin
+			}
+			return grades
+		}
+		gas.failure = &EvaluationError{X: xs[pending[0]], Attempts: attempt, Err: first}
+	}
+
+	for _, i := range pending {
+		grades[i] = gas.fmin
+	}
+	return grades
+}
+
+// gradeReal grades all of the values with the fitness, in a single batch unless the grades are
+// resampled.
+func (gas *GeneticAlgorithmSolver) gradeReal(vals []float64) []float64 {
+	if gas.objective == nil && gas.samples <= 1 {
+		return gas.evaluate(vals)
+	}
+	grades := make([]float64, len(vals))
+	for i, x := range vals {
+		grades[i] = gas.Grade(x)
+	}
+	return grades
+}
+
+// takeFailure returns the failure that aborts the run and forgets it.
+func (gas *GeneticAlgorithmSolver) takeFailure() error {
+	err := gas.failure
+	gas.failure = nil
+	return err
+}
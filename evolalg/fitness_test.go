@@ -0,0 +1,264 @@
+package evolalg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+)
+
+var errSimulator = errors.New("simulator crashed")
+
+// countingFitness grades the batches with hill and fails the first attempts of the candidates for
+// which fails returns true. It counts the calls and the graded candidates.
+type countingFitness struct {
+	mu       sync.Mutex
+	fails    func(x float64, attempt int) bool
+	attempts map[float64]int
+	calls    int
+	graded   int
+}
+
+func (f *countingFitness) Evaluate(ctx context.Context, xs []float64) (grades []float64, errs []error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.attempts == nil {
+		f.attempts = make(map[float64]int)
+	}
+	f.calls++
+	f.graded += len(xs)
+	grades = make([]float64, len(xs))
+	for i, x := range xs {
+		f.attempts[x]++
+		if f.fails != nil && f.fails(x, f.attempts[x]) {
+			errs = failAll(errs, len(xs), len(xs), nil)
+			errs[i] = errSimulator
+			continue
+		}
+		grades[i] = hill(x)
+	}
+	return
+}
+
+func TestFitnessAdapters(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	xs := []float64{1, 2, 3}
+
+	grades, errs := FitnessFunc(hill).Evaluate(ctx, xs)
+	if errs != nil || grades[2] != hill(3) {
+		t.Log(fmt.Sprintf("plain function graded %v with errors %v", grades, errs))
+		t.Fail()
+	}
+	fallible := FallibleFunc(func(_ context.Context, x float64) (float64, error) {
+		if x == 2 {
+			return 0, errSimulator
+		}
+		return hill(x), nil
+	})
+	grades, errs = fallible.Evaluate(ctx, xs)
+	if errs == nil || errs[0] != nil || errs[1] != errSimulator || errs[2] != nil || grades[2] != hill(3) {
+		t.Log(fmt.Sprintf("fallible function graded %v with errors %v", grades, errs))
+		t.Fail()
+	}
+	short := BatchFunc(func(_ context.Context, xs []float64) ([]float64, error) { return xs[1:], nil })
+	if _, errs = short.Evaluate(ctx, xs); errs == nil || errs[0] == nil || errs[2] == nil {
+		t.Log("batch function returning too few grades didn't fail")
+		t.Fail()
+	}
+
+	cancel()
+	for name, f := range map[string]Fitness{"plain": FitnessFunc(hill), "fallible": fallible} {
+		if _, errs = f.Evaluate(ctx, xs); errs == nil || !errors.Is(errs[0], context.Canceled) {
+			t.Log(fmt.Sprintf("%s function ignored the cancelled context: %v", name, errs))
+			t.Fail()
+		}
+	}
+}
+
+func TestFailurePolicyByName(t *testing.T) {
+	for name, want := range map[string]FailurePolicy{"": AbortOnFailure, "abort": AbortOnFailure,
+		"retry": RetryOnFailure, "worst": WorstOnFailure} {
+		if p, err := FailurePolicyByName(name); err != nil || p != want {
+			t.Log(fmt.Sprintf("%q was parsed as %d (%v)", name, p, err))
+			t.Fail()
+		}
+	}
+	if _, err := FailurePolicyByName("x"); err == nil {
+		t.Log("unknown policy was parsed")
+		t.Fail()
+	}
+}
+
+func TestBatchFitness(t *testing.T) {
+	f := &countingFitness{}
+	gas, err := NewBatchGeneticAlgorithmSolver(-4, 12, 3, f)
+	if err != nil {
+		t.Fatal(err)
+	} else if f.calls != 0 {
+		t.Log(fmt.Sprintf("solver was created with %d calls", f.calls))
+		t.Fail()
+	}
+
+	// Every population is graded with a single call
+	gas.SetSeed(1)
+	hist, err := gas.Solve(30, 10, 0.75, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := hist[len(hist)-1]
+	if f.graded != last.Evaluations || f.calls > 2*len(hist)+1 {
+		t.Log(fmt.Sprintf("%d evaluations were made with %d calls grading %d candidates", last.Evaluations, f.calls,
+			f.graded))
+		t.Fail()
+	}
+
+	// The lowest grade is taken from the initial population, whose failed evaluations get it as well
+	lowest := math.Inf(1)
+	for _, grade := range hist[0].Grades {
+		lowest = math.Min(lowest, grade)
+	}
+	if gas.fmin != lowest {
+		t.Log(fmt.Sprintf("lowest grade %f was taken instead of %f", gas.fmin, lowest))
+		t.Fail()
+	}
+	f.fails = func(x float64, _ int) bool { return x > 6 }
+	gas.SetFailures(Failures{Policy: WorstOnFailure})
+	hist, err = gas.Solve(30, 10, 0.75, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, x := range hist[0].PopulationF64 {
+		if grade := hist[0].Grades[i]; grade < gas.fmin || x > 6 && grade != gas.fmin || math.IsInf(grade, 0) {
+			t.Log(fmt.Sprintf("%f was graded %f with the lowest grade %f", x, grade, gas.fmin))
+			t.Fail()
+		}
+	}
+	gas.SetLowestGrade(-10)
+	if _, err = gas.Solve(30, 3, 0.75, 0.01); err != nil || gas.fmin != -10 {
+		t.Log(fmt.Sprintf("run with the lowest grade set ended with %f (%v)", gas.fmin, err))
+		t.Fail()
+	}
+}
+
+func TestFailurePolicies(t *testing.T) {
+	gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, hill)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gas.SetFailures(Failures{Retries: -1}); err == nil {
+		t.Log("negative amount of retries was accepted")
+		t.Fail()
+	}
+
+	// Flaky evaluations succeed when retried
+	f := &countingFitness{fails: func(x float64, attempt int) bool { return attempt == 1 && x > 6 }}
+	gas.SetFitness(f)
+	gas.SetFailures(Failures{Policy: RetryOnFailure})
+	gas.SetSeed(1)
+	hist, err := gas.Solve(30, 10, 0.75, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := hist[len(hist)-1]
+	if last.Failed == 0 || f.graded != last.Evaluations {
+		t.Log(fmt.Sprintf("retried run failed %d times in %d evaluations (%d graded)", last.Failed, last.Evaluations,
+			f.graded))
+		t.Fail()
+	}
+
+	// Evaluations failing every time abort the run after the retries
+	f = &countingFitness{fails: func(x float64, _ int) bool { return x > 6 }}
+	gas.SetFitness(f)
+	gas.SetFailures(Failures{Policy: RetryOnFailure, Retries: 2})
+	_, err = gas.Solve(30, 10, 0.75, 0.01)
+	var evalErr *EvaluationError
+	if !errors.As(err, &evalErr) || evalErr.Attempts != 3 || evalErr.X <= 6 || !errors.Is(err, errSimulator) {
+		t.Log(fmt.Sprintf("retried run ended with the error %v", err))
+		t.Fail()
+	}
+	gas.SetFailures(Failures{})
+	if _, err = gas.Solve(30, 10, 0.75, 0.01); !errors.As(err, &evalErr) || evalErr.Attempts != 1 {
+		t.Log(fmt.Sprintf("aborted run ended with the error %v", err))
+		t.Fail()
+	}
+
+	// The failed candidates get the lowest grade and never become the elite
+	gas.SetFailures(Failures{Policy: WorstOnFailure})
+	hist, err = gas.Solve(30, 10, 0.75, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for e, ed := range hist {
+		for i, x := range ed.PopulationF64 {
+			if x > 6 && ed.Grades[i] != gas.fmin {
+				t.Log(fmt.Sprintf("epoch %d: failed %f was graded %f", e, x, ed.Grades[i]))
+				t.Fail()
+			}
+		}
+		if ed.Elite > 6 {
+			t.Log(fmt.Sprintf("epoch %d: failed %f became the elite", e, ed.Elite))
+			t.Fail()
+		}
+	}
+	if hist[len(hist)-1].Failed == 0 {
+		t.Log("no failures were reported")
+		t.Fail()
+	}
+}
+
+func TestFitnessContext(t *testing.T) {
+	// The fitness cancels the run in its third batch if it's given the function to do that
+	var cancel context.CancelFunc
+	calls := 0
+	fitness := BatchFunc(func(ctx context.Context, xs []float64) ([]float64, error) {
+		if calls++; calls == 3 && cancel != nil {
+			cancel()
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		grades := make([]float64, len(xs))
+		for i, x := range xs {
+			grades[i] = hill(x)
+		}
+		return grades, nil
+	})
+	p := Problem{Lower: []float64{-4}, Upper: []float64{12}, Fitness: fitness}
+	if _, err := newDifferentialEvolution(p); err == nil {
+		t.Log("differential evolution accepted a problem graded in batches")
+		t.Fail()
+	}
+
+	for _, name := range []string{"ga", "sa", "tabu"} {
+		a, _ := AlgorithmByName(name)
+		s, err := a.New(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = s.Configure(Params{"epoki": "10", "awarie": "retry", "ziarno": "1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		calls, cancel = 0, nil
+		hist, err := s.Run(context.Background())
+		if err != nil || math.Abs(hist[len(hist)-1].Elite-3) > 3 {
+			t.Log(fmt.Sprintf("%s ended with %v", name, err))
+			t.Fail()
+		}
+
+		err = s.Configure(Params{"epoki": "10", "awarie": "retry", "ziarno": "1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ctx context.Context
+		calls = 0
+		ctx, cancel = context.WithCancel(context.Background())
+		if _, err := s.Run(ctx); !errors.Is(err, context.Canceled) {
+			t.Log(fmt.Sprintf("%s cancelled by the fitness ended with %v", name, err))
+			t.Fail()
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,235 @@
+package evolalg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Operators that produce the individuals recorded by the genealogy.
+const (
+	OpInit        = "init"         // individual of the initial population
+	OpCrossover   = "crossover"    // child of two parents (possibly mutated afterwards)
+	OpMutation    = "mutation"     // mutated copy of a single parent
+	OpCopy        = "copy"         // unchanged copy of a single parent bred outside of InPlace
+	OpLocalSearch = "local-search" // result of the local search started from its parent
+	OpRestart     = "restart"      // individual created by a restart of the population
+	OpElite       = "elite"        // elite put back into the population (encoded anew from its value)
+	OpMemory      = "memory"       // elite remembered before a change of a dynamic objective put back into the population
+)
+
+// Individual is the record of the birth of a single individual.
+type Individual struct {
+	ID        int     `json:"id"`
+	Parents   []int   `json:"parents,omitempty"`   // IDs of the parents (none for the init and restart operators)
+	Operator  string  `json:"operator"`            // operator that produced the individual
+	CutPoint  int     `json:"cutPoint"`            // -1 if the parents were not crossed over
+	Mutations []int   `json:"mutations,omitempty"` // bits flipped by the mutation
+	Epoch     int     `json:"epoch"`               // epoch in which the individual was born
+	Genome    string  `json:"genome"`
+	X         float64 `json:"x"`
+	Grade     float64 `json:"grade"`
+}
+
+// Genealogy is the record of all of the individuals created during a run of a genetic algorithm.
+// The IDs start from 1 and are given in the order of birth so that every parent has a lower ID
+// than its children.
+type Genealogy struct {
+	Individuals []Individual `json:"individuals"` // the individual with ID i is at index i-1
+	EliteID     int          `json:"eliteID"`     // ID of the current elite
+}
+
+// Individual returns the record of the individual with the given ID.
+func (g *Genealogy) Individual(id int) (Individual, bool) {
+	if id < 1 || id > len(g.Individuals) {
+		return Individual{}, false
+	}
+	return g.Individuals[id-1], true
+}
+
+// Ancestry returns the individual with the given ID together with all of its ancestors up to depth
+// generations back (all of them if depth is 0) ordered by their IDs.
+func (g *Genealogy) Ancestry(id, depth int) []Individual {
+	ind, ok := g.Individual(id)
+	if !ok {
+		return nil
+	}
+	seen := map[int]bool{id: true}
+	res := []Individual{ind}
+	level := []Individual{ind}
+	for d := 0; len(level) > 0 && (depth <= 0 || d < depth); d++ {
+		var next []Individual
+		for _, child := range level {
+			for _, p := range child.Parents {
+				if seen[p] {
+					continue
+				}
+				seen[p] = true
+				parent, _ := g.Individual(p)
+				res = append(res, parent)
+				next = append(next, parent)
+			}
+		}
+		level = next
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res
+}
+
+// EliteAncestry returns the ancestry of the current elite.
+func (g *Genealogy) EliteAncestry(depth int) []Individual {
+	return g.Ancestry(g.EliteID, depth)
+}
+
+// WriteDOT writes the ancestry graph of the individual with the given ID (see Ancestry) in the DOT
+// language of Graphviz. The edges lead from the parents to their children, the individuals born in
+// the same epoch are put in the same rank and the individual itself is highlighted.
+func (g *Genealogy) WriteDOT(w io.Writer, id, depth int) error {
+	ancestry := g.Ancestry(id, depth)
+	if ancestry == nil {
+		return fmt.Errorf("no individual with ID %d", id)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph genealogy {")
+	fmt.Fprintln(bw, "\tnode [shape=box, fontname=\"monospace\"];")
+	byEpoch := make(map[int][]int)
+	var epochs []int
+	included := make(map[int]bool, len(ancestry))
+	for _, ind := range ancestry {
+		included[ind.ID] = true
+		if byEpoch[ind.Epoch] == nil {
+			epochs = append(epochs, ind.Epoch)
+		}
+		byEpoch[ind.Epoch] = append(byEpoch[ind.Epoch], ind.ID)
+
+		style := ""
+		if ind.ID == id {
+			style = ", style=filled, fillcolor=gold"
+		}
+		fmt.Fprintf(bw, "\tn%d [label=%s%s];\n", ind.ID, strconv.Quote(label(ind)), style)
+	}
+	sort.Ints(epochs)
+	for _, e := range epochs {
+		nodes := make([]string, len(byEpoch[e]))
+		for i, n := range byEpoch[e] {
+			nodes[i] = "n" + strconv.Itoa(n)
+		}
+		fmt.Fprintf(bw, "\t{ rank=same; %s; }\n", strings.Join(nodes, "; "))
+	}
+	for _, ind := range ancestry {
+		for _, p := range ind.Parents {
+			if included[p] {
+				fmt.Fprintf(bw, "\tn%d -> n%d;\n", p, ind.ID)
+			}
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// label returns the description of the individual shown in its node of the DOT graph.
+func label(ind Individual) string {
+	op := ind.Operator
+	if ind.CutPoint >= 0 {
+		op += " at " + strconv.Itoa(ind.CutPoint)
+	}
+	if len(ind.Mutations) > 0 {
+		bits := make([]string, len(ind.Mutations))
+		for i, b := range ind.Mutations {
+			bits[i] = strconv.Itoa(b)
+		}
+		op += ", flipped " + strings.Join(bits, ",")
+	}
+	return fmt.Sprintf("#%d (epoch %d)\n%s\n%s\nx = %g, grade = %g", ind.ID, ind.Epoch, op, ind.Genome, ind.X, ind.Grade)
+}
+
+// SetGenealogy enables or disables the recording of the genealogy of the individuals created by the
+// genetic algorithm. The genealogy is reset by Start and saved in the history entry of the last
+// epoch of Solve.
+func (gas *GeneticAlgorithmSolver) SetGenealogy(on bool) {
+	gas.recordGenealogy = on
+}
+
+// Genealogy returns the genealogy of the current run or nil if it's not being recorded.
+func (gas *GeneticAlgorithmSolver) Genealogy() *Genealogy {
+	return gas.genealogy
+}
+
+// startGenealogy resets the genealogy and records the initial population.
+func (gas *GeneticAlgorithmSolver) startGenealogy() {
+	gas.genealogy, gas.popID = nil, nil
+	if !gas.recordGenealogy {
+		return
+	}
+	gas.genealogy = &Genealogy{}
+	gas.popID = make([]int, len(gas.popArr))
+	for i := range gas.popArr {
+		gas.popID[i] = gas.born(Individual{Operator: OpInit, CutPoint: -1}, gas.popArr[i], gas.popVals[i], gas.gradeCache[i])
+	}
+}
+
+// born records a new individual with the given genome and returns its ID (0 if the genealogy is not
+// recorded).
+func (gas *GeneticAlgorithmSolver) born(ind Individual, genome []byte, x, grade float64) int {
+	if gas.genealogy == nil {
+		return 0
+	}
+	if len(ind.Parents) == 2 && ind.Parents[0] == ind.Parents[1] {
+		ind.Parents = ind.Parents[:1]
+	}
+	ind.ID = len(gas.genealogy.Individuals) + 1
+	ind.Epoch = gas.epoch
+	ind.X, ind.Grade = x, grade
+	var sb strings.Builder
+	for _, bit := range genome {
+		sb.WriteByte('0' + bit)
+	}
+	ind.Genome = sb.String()
+	gas.genealogy.Individuals = append(gas.genealogy.Individuals, ind)
+	return ind.ID
+}
+
+// childOperator returns the operator that produced a child of the given cut point and mutations.
+func childOperator(cut int, mutations []int) string {
+	if cut >= 0 {
+		return OpCrossover
+	} else if len(mutations) > 0 {
+		return OpMutation
+	}
+	return OpCopy
+}
+
+// recordInPlaceEpoch records the individuals changed by the crossover and mutation of inPlaceEpoch.
+// parents holds the IDs of the population before the epoch and partners the index of the mate of
+// every individual (-1 if it was not crossed over).
+func (gas *GeneticAlgorithmSolver) recordInPlaceEpoch(parents, partners, cutpoints []int, mutations [][]int) {
+	for i := range gas.popArr {
+		if partners[i] < 0 && mutations[i] == nil {
+			continue
+		}
+		ind := Individual{Parents: []int{parents[i]}, CutPoint: -1, Mutations: mutations[i]}
+		if partners[i] >= 0 {
+			ind.Parents = append(ind.Parents, parents[partners[i]])
+			ind.CutPoint = cutpoints[i]
+		}
+		ind.Operator = childOperator(ind.CutPoint, ind.Mutations)
+		gas.popID[i] = gas.born(ind, gas.popArr[i], gas.popVals[i], gas.gradeCache[i])
+	}
+}
+
+// eliteCopy records the elite put back into the population at index i.
+func (gas *GeneticAlgorithmSolver) eliteCopy(i int) int {
+	ind := Individual{Parents: []int{gas.genealogy.EliteID}, Operator: OpElite, CutPoint: -1}
+	return gas.born(ind, gas.popArr[i], gas.popVals[i], gas.gradeCache[i])
+}
+
+// setEliteAt makes the i-th individual of the population the elite in the genealogy.
+func (gas *GeneticAlgorithmSolver) setEliteAt(i int) {
+	if gas.genealogy != nil {
+		gas.genealogy.EliteID = gas.popID[i]
+	}
+}
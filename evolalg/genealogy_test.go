@@ -0,0 +1,185 @@
+package evolalg
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGenealogy(t *testing.T) {
+	configs := map[string]func(gas *GeneticAlgorithmSolver) error{
+		"inplace": func(gas *GeneticAlgorithmSolver) error { return nil },
+		"generational": func(gas *GeneticAlgorithmSolver) error {
+			gas.SetReplacement(Generational, 0)
+			return nil
+		},
+		"plus": func(gas *GeneticAlgorithmSolver) error {
+			gas.SetReplacement(PlusSelection, 10)
+			return nil
+		},
+		"crowding": func(gas *GeneticAlgorithmSolver) error {
+			return gas.SetNiching(Niching{Method: DeterministicCrowding, Radius: 0.1})
+		},
+		"local search": func(gas *GeneticAlgorithmSolver) error {
+			return gas.SetLocalSearch(LocalSearch{Method: BitFlipHillClimbing, Fraction: 0.3})
+		},
+		"restart": func(gas *GeneticAlgorithmSolver) error {
+			return gas.SetRestart(Restart{Policy: IPOPRestart, StagnationEpochs: 3, Growth: 1.5})
+		},
+	}
+	for name, configure := range configs {
+		gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, func(x float64) float64 {
+			return math.Mod(x, 1*(math.Cos(20*math.Pi*x)-math.Sin(x)))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		gas.SetSeed(3)
+		gas.SetGenealogy(true)
+		err = configure(&gas)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hist, err := gas.Solve(20, 30, 0.75, 0.005)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g := hist[len(hist)-1].Genealogy
+		if g == nil {
+			t.Log(fmt.Sprintf("%s: no genealogy in the last epoch", name))
+			t.Fail()
+			continue
+		}
+
+		// Every member of every population is recorded with its genome
+		for e, ed := range hist {
+			if len(ed.IDs) != len(ed.PopulationF64) {
+				t.Log(fmt.Sprintf("%s: %d IDs for %d individuals in epoch %d", name, len(ed.IDs), len(ed.PopulationF64), e))
+				t.Fail()
+				continue
+			}
+			for i, id := range ed.IDs {
+				ind, ok := g.Individual(id)
+				if !ok || ind.Genome != genomeString(ed.PopulationBytes[i]) || ind.Epoch > e {
+					t.Log(fmt.Sprintf("%s: individual %d of epoch %d is recorded as %+v", name, i, e, ind))
+					t.Fail()
+				}
+			}
+		}
+
+		// The children are made of the genomes of their parents
+		for _, ind := range g.Individuals {
+			for _, p := range ind.Parents {
+				if p >= ind.ID {
+					t.Log(fmt.Sprintf("%s: individual %d has a younger parent %d", name, ind.ID, p))
+					t.Fail()
+				}
+			}
+			if ind.Operator != OpCrossover && ind.Operator != OpMutation {
+				continue
+			}
+			genome := []byte(ind.Genome)
+			for _, k := range ind.Mutations {
+				genome[k] ^= 1
+			}
+			a, _ := g.Individual(ind.Parents[0])
+			b := a
+			if len(ind.Parents) == 2 {
+				b, _ = g.Individual(ind.Parents[1])
+			}
+			cut := ind.CutPoint
+			if cut < 0 {
+				cut = len(genome)
+			}
+			if string(genome) != a.Genome[:cut]+b.Genome[cut:] && string(genome) != b.Genome[:cut]+a.Genome[cut:] {
+				t.Log(fmt.Sprintf("%s: individual %+v is not a child of %s and %s", name, ind, a.Genome, b.Genome))
+				t.Fail()
+			}
+		}
+
+		elite, ok := g.Individual(g.EliteID)
+		if !ok || elite.X != hist[len(hist)-1].Elite {
+			t.Log(fmt.Sprintf("%s: elite %g is recorded as %+v", name, hist[len(hist)-1].Elite, elite))
+			t.Fail()
+		}
+	}
+
+	// Nothing is recorded by default
+	gas, _ := NewGeneticAlgorithmSolver(-4, 12, 3, math.Sin)
+	hist, err := gas.Solve(10, 5, 0.75, 0.005)
+	if err != nil {
+		t.Fatal(err)
+	} else if hist[len(hist)-1].Genealogy != nil || hist[len(hist)-1].IDs != nil {
+		t.Log("genealogy was recorded without being enabled")
+		t.Fail()
+	}
+}
+
+func TestAncestry(t *testing.T) {
+	g := &Genealogy{Individuals: []Individual{
+		{ID: 1, Operator: OpInit, CutPoint: -1, Genome: "0000"},
+		{ID: 2, Operator: OpInit, CutPoint: -1, Genome: "1111"},
+		{ID: 3, Operator: OpInit, CutPoint: -1, Genome: "1010"},
+		{ID: 4, Parents: []int{1, 2}, Operator: OpCrossover, CutPoint: 2, Epoch: 1, Genome: "0011"},
+		{ID: 5, Parents: []int{3}, Operator: OpMutation, CutPoint: -1, Mutations: []int{0}, Epoch: 1, Genome: "0010"},
+		{ID: 6, Parents: []int{4, 5}, Operator: OpCrossover, CutPoint: 1, Mutations: []int{3}, Epoch: 2, Genome: "0011"},
+	}, EliteID: 6}
+
+	ids := func(inds []Individual) (res []int) {
+		for _, ind := range inds {
+			res = append(res, ind.ID)
+		}
+		return
+	}
+	for _, c := range []struct {
+		depth int
+		ids   string
+	}{{0, "[1 2 3 4 5 6]"}, {1, "[4 5 6]"}, {2, "[1 2 3 4 5 6]"}} {
+		got := fmt.Sprint(ids(g.EliteAncestry(c.depth)))
+		if got != c.ids {
+			t.Log(fmt.Sprintf("ancestry of depth %d is %s instead of %s", c.depth, got, c.ids))
+			t.Fail()
+		}
+	}
+	if got := fmt.Sprint(ids(g.Ancestry(5, 0))); got != "[3 5]" {
+		t.Log(fmt.Sprintf("ancestry of 5 is %s", got))
+		t.Fail()
+	}
+	if g.Ancestry(7, 0) != nil {
+		t.Log("ancestry of an unknown individual was found")
+		t.Fail()
+	}
+
+	var buf bytes.Buffer
+	err := g.WriteDOT(&buf, 6, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	for _, want := range []string{"digraph genealogy {", "n4 -> n6;", "n5 -> n6;", "n6 [label=", "fillcolor=gold",
+		"crossover at 1, flipped 3", "{ rank=same; n4; n5; }"} {
+		if !strings.Contains(dot, want) {
+			t.Log(fmt.Sprintf("DOT output lacks %q:\n%s", want, dot))
+			t.Fail()
+		}
+	}
+	if strings.Contains(dot, "n1 -> n4") {
+		t.Log("DOT output contains an edge deeper than the depth")
+		t.Fail()
+	}
+	if g.WriteDOT(&buf, 0, 0) == nil {
+		t.Log("DOT output of an unknown individual was written")
+		t.Fail()
+	}
+}
+
+// genomeString returns the genome in the form saved in the genealogy.
+func genomeString(genome []byte) string {
+	s := make([]byte, len(genome))
+	for i, bit := range genome {
+		s[i] = '0' + bit
+	}
+	return string(s)
+}
@@ -1,6 +1,7 @@
 package evolalg
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -11,36 +12,144 @@ import (
 // EpochData contains data on the state of a generic algorithm's solution after an iteration of
 // calculations.
 type EpochData struct {
-	PopulationBytes [][]byte  `json:"populationBytes"`
-	PopulationF64   []float64 `json:"populationF64"`
-	Fits            []float64 `json:"fits"`
-	Grades          []float64 `json:"grades"`
-	Elite           float64   `json:"elite"`
-	EliteFit        float64   `json:"eliteFit"`
-	FMin            float64   `json:"fMin"`
-	FAVG            float64   `json:"fAVG"`
-	FMax            float64   `json:"fMax"`
+	PopulationBytes [][]byte       `json:"populationBytes"`
+	PopulationF64   []float64      `json:"populationF64"`
+	Fits            []float64      `json:"fits"`
+	Grades          []float64      `json:"grades"`
+	Elite           float64        `json:"elite"`
+	EliteFit        float64        `json:"eliteFit"`   // fit of the elite (its grade shifted by the lowest grade in the genetic algorithm)
+	EliteGrade      float64        `json:"eliteGrade"` // grade of the elite
+	FMin            float64        `json:"fMin"`
+	FAVG            float64        `json:"fAVG"`
+	FMax            float64        `json:"fMax"`
+	Diversity       Diversity      `json:"diversity"`
+	EliteEpoch      int            `json:"eliteEpoch"`              // epoch in which the current elite was found for the first time
+	Optima          []Optimum      `json:"optima,omitempty"`        // distinct optima (only in the last epoch of a run with niching)
+	IDs             []int          `json:"ids,omitempty"`           // genealogy IDs of the population (only if the genealogy is recorded)
+	Genealogy       *Genealogy     `json:"genealogy,omitempty"`     // genealogy of the run (only in the last epoch)
+	Reference       *Reference     `json:"reference,omitempty"`     // comparison with the exact optimum (see SetReference)
+	Variance        float64        `json:"variance,omitempty"`      // mean variance of the resampled grades of the epoch
+	Change          bool           `json:"change,omitempty"`        // change of a dynamic objective detected at the start of the epoch
+	Saved           int            `json:"saved,omitempty"`         // fitness evaluations replaced by the surrogate model since the start of the run
+	Failed          int            `json:"failed,omitempty"`        // failed fitness evaluations made since the start of the run
+	Evaluations     int            `json:"evaluations"`             // fitness evaluations made since the start of the run
+	LSEvaluations   int            `json:"lsEvaluations"`           // part of the evaluations made by the local search
+	Restart         string         `json:"restart,omitempty"`       // restart policy applied at the end of the epoch
+	CP              float64        `json:"cp"`                      // effective crossover probability used in the epoch
+	MP              float64        `json:"mp"`                      // effective mutation probability used in the epoch
+	CR              float64        `json:"cr,omitempty"`            // mean crossover rate of differential evolution in the epoch
+	F               float64        `json:"f,omitempty"`             // mean scale factor of differential evolution in the epoch
+	PopulationVec   [][]float64    `json:"populationVec,omitempty"` // population of the solvers of multi-dimensional problems
+	EliteVec        []float64      `json:"eliteVec,omitempty"`      // elite of the solvers of multi-dimensional problems
+	Swarm           *SwarmSnapshot `json:"swarm,omitempty"`         // velocities and personal bests of the particles of a swarm
+	Temperature     float64        `json:"temperature,omitempty"`   // temperature of simulated annealing in the epoch
+	Mean            []float64      `json:"mean,omitempty"`          // mean of the search distribution of CMA-ES after the epoch
+	Sigma           float64        `json:"sigma,omitempty"`         // step size of CMA-ES after the epoch
+
+	Trace *EpochTrace `json:"trace,omitempty"`
+}
+
+// TraceLevel describes how much of the operators' work is recorded in the history of a solver.
+type TraceLevel int
+
+const (
+	// TraceOff disables the tracing. This is the default.
+	TraceOff TraceLevel = iota
+	// TraceSelection records the roulette table (probabilities and cumulative distribution bounds).
+	TraceSelection
+	// TraceOperators additionally records the mated pairs, cut points and mutated bits.
+	TraceOperators
+	// TraceFull additionally records every random number drawn during selection and mutation.
+	TraceFull
+)
+
+// EpochTrace contains a step by step record of the operations that produced the population of an
+// epoch. The roulette table is the one that was used for picking the parents of that population.
+type EpochTrace struct {
+	Probabilities   []float64   `json:"probabilities"`
+	CDFBounds       []float64   `json:"cdfBounds"`
+	SelectionDraws  []float64   `json:"selectionDraws,omitempty"`
+	Pairs           [][2]int    `json:"pairs,omitempty"`
+	CutPoints       []int       `json:"cutPoints,omitempty"`
+	Mutations       [][]int     `json:"mutations,omitempty"`
+	MutationDraws   [][]float64 `json:"mutationDraws,omitempty"`
+	EliteInsertedAt int         `json:"eliteInsertedAt"` // index the elite was put back at or -1 if it survived
+
+	Offspring []OffspringTrace `json:"offspring,omitempty"` // used instead of CutPoints and Mutations outside of InPlace
 }
 
 // GeneticAlgorithmSolver is a struct that contains all of the data related to a generic algorithm instance and shares
 // a set of functions for solving this certain genetic algorithm.
 type GeneticAlgorithmSolver struct {
-	a        float64                 // lower bound of the set (inclusive)
-	b        float64                 // higher bound of the set (inclusive)
-	d        byte                    // accuracy (e.g. accuracy 3 means 10^3 for each decimal therefore for 3 it would generate a 3001 population)
-	l        int                     // minimal bit size for representation of all of the population
-	elite    float64                 // the value used for the current best solution.
-	eliteFit float64                 // the fit of the currently best solution.
-	fmin     float64                 // lowest value of the gFunc in the <a, b> set
-	popSize  uint                    // actual population size (higher bound of <0, pop> set)
-	popArr   [][]byte                // population array in little endian
-	gFunc    func(x float64) float64 // function responsible for grading the received solution
-
-	fitSum      float64   // sum of all the cached fits. Stored in struct for optimization purposes.
+	a        float64   // lower bound of the set (inclusive)
+	b        float64   // higher bound of the set (inclusive)
+	d        byte      // accuracy (e.g. accuracy 3 means 10^3 for each decimal therefore for 3 it would generate a 3001 population)
+	l        int       // minimal bit size for representation of all of the population
+	elite    float64   // the value used for the current best solution.
+	eliteFit float64   // the fit of the currently best solution.
+	fmin     float64   // lowest value of the fitness in the <a, b> set
+	popSize  uint      // actual population size (higher bound of <0, pop> set)
+	popArr   [][]byte  // population array in little endian
+	popVals  []float64 // population decoded to floating point form
+	popAge   []int     // epoch in which each individual of the population was born
+	popID    []int     // genealogy IDs of the population (nil if the genealogy is not recorded)
+	fitness  Fitness   // function responsible for grading the received solutions
+
+	fitSum      float64   // sum of all the cached fits, recalculated by every roulette.
 	gradeCache  []float64 // grade cache. Holds the values of calculated grades.
 	fitCache    []float64 // fit cache. Holds the values of calculated fits until cleared.
 	probCache   []float64 // probability cache. Holds the values of calculated probabilities until cleared.
 	probHBCache []float64 // Holds the values of probability's higher bound in cumulative distribution bound.
+
+	epoch       int         // currently processed epoch (0 is the initial population)
+	eliteGrade  float64     // grade of the current elite
+	fminFromRun bool        // fmin is the lowest grade of the initial population of the run
+	eliteEpoch  int         // epoch in which the current elite was found for the first time
+	initFunc    Initializer // creates the initial population (UniformRealInit if nil)
+	replacement Replacement // the way offspring replace the population
+	niching     Niching     // niching method used for keeping several optima in the population
+	localSearch LocalSearch // local search applied to the population after every epoch
+	adaptation  Adaptation  // control of the crossover and mutation probabilities
+	epochCP     float64     // crossover probability used in the current epoch
+	epochMP     float64     // mutation probability used in the current epoch
+	runCP       float64     // crossover probability passed to Start
+	runMP       float64     // mutation probability passed to Start
+	runEpochs   int         // amount of epochs passed to Start
+	spFMax      float64     // highest fit of the population used by the Srinivas-Patnaik rates
+	spFAvg      float64     // average fit of the population used by the Srinivas-Patnaik rates
+	restart     Restart     // restarts of the population on stagnation
+	lastRestart int         // epoch of the last restart
+	evals       int         // amount of fitness evaluations since the start of Solve
+	lsEvals     int         // amount of fitness evaluations made by the local search
+	lambda      int         // amount of offspring bred in each epoch (N if 0). Unused for InPlace.
+	termination Termination // conditions that stop Solve before all of the epochs
+	rng         *rand.Rand  // source of all of the random numbers used by the solver
+	traceLevel  TraceLevel  // how much of the operators' work is saved in the history
+	trace       *EpochTrace // trace of the currently processed epoch (nil if tracing is off)
+
+	recordGenealogy bool       // whether the genealogy of the individuals is recorded
+	genealogy       *Genealogy // genealogy of the current run (nil if it's not recorded)
+	reference       *Reference // exact optimum the runs are compared with (nil if none)
+
+	objective  Objective    // noisy or dynamic grading function used instead of gFunc (nil if none)
+	samples    int          // amount of samples averaged by every grade (no resampling if 0 or 1)
+	noiseEpoch int          // epoch whose variances of the samples are accumulated
+	varSum     float64      // sum of the variances of the samples of the grades made in noiseEpoch
+	varCount   int          // amount of the resampled grades made in noiseEpoch
+	dynamics   Dynamics     // response to the changes of a dynamic objective
+	memory     []remembered // elites remembered before the changes of the objective
+	hyperUntil int          // last epoch of the hypermutation (-1 if none)
+
+	surrogate Surrogate           // pre-screening of the individuals with a surrogate model
+	archive   map[float64]float64 // real grades of the current run (nil without the surrogate model)
+	archiveX  []float64           // archived values in the order in which they were graded
+	predicted map[float64]float64 // grades predicted by the surrogate model in the current run
+	saved     int                 // fitness evaluations replaced by the surrogate model
+
+	ctx      context.Context // context passed to the fitness (context.Background() if nil)
+	failures Failures        // handling of the failed fitness evaluations
+	failure  error           // failure that aborts the current epoch (nil if none)
+	failed   int             // failed fitness evaluations made since the start of the run
 }
 
 // NewGeneticAlgorithm creates a new instance of a genetic algorithm solver.
@@ -53,6 +162,28 @@ type GeneticAlgorithmSolver struct {
 //
 // d - accuracy (e.g. accuracy 3 means 10^3 for each decimal therefore for 3 it would generate a 3001 population)
 func NewGeneticAlgorithmSolver(a float64, b float64, d byte, gFunc func(x float64) float64) (ga GeneticAlgorithmSolver, err error) {
+	ga, err = NewBatchGeneticAlgorithmSolver(a, b, d, FitnessFunc(gFunc))
+	if err != nil {
+		return
+	}
+
+	// calculate fmin
+	fmin := math.MaxFloat64
+	for i := ga.a; i < ga.b; i += math.Pow(1, -float64(ga.d)) { // TODO Check if this returns -0.001 for 10^-3
+		val := gFunc(i)
+		if fmin > val {
+			fmin = val
+		}
+	}
+	ga.SetLowestGrade(fmin)
+	return
+}
+
+// NewBatchGeneticAlgorithmSolver creates a new instance of a genetic algorithm solver grading the
+// candidates with the batch fitness (see NewGeneticAlgorithmSolver for the other arguments). The
+// fitness isn't called until the solver is started, so the lowest grade the fits are based on is
+// taken from the initial population of every run unless it's set with SetLowestGrade.
+func NewBatchGeneticAlgorithmSolver(a float64, b float64, d byte, f Fitness) (ga GeneticAlgorithmSolver, err error) {
 	if a > b {
 		err = errors.New("provided lower bound greater than higher bound")
 		return
@@ -65,16 +196,8 @@ func NewGeneticAlgorithmSolver(a float64, b float64, d byte, gFunc func(x float6
 	ga.a = a
 	ga.b = b
 	ga.d = d
-	ga.gFunc = gFunc
-
-	// calculate fmin
-	ga.fmin = math.MaxFloat64
-	for i := ga.a; i < ga.b; i += math.Pow(1, -float64(ga.d)) { // TODO Check if this returns -0.001 for 10^-3
-		val := ga.gFunc(i)
-		if ga.fmin > val {
-			ga.fmin = val
-		}
-	}
+	ga.fitness = f
+	ga.fminFromRun = true
 
 	// get the population size
 	var lb, hb float64
@@ -93,11 +216,48 @@ func NewGeneticAlgorithmSolver(a float64, b float64, d byte, gFunc func(x float6
 
 	// calculate size of the population array and create it
 	ga.l = int(math.Ceil(math.Log2((ga.b-ga.a)*(1/math.Pow(10, -float64(ga.d))) + 1)))
-	// ga.popArr = make([]byte, ga.l)
 
 	return
 }
 
+// SetLowestGrade sets the lowest grade of the fitness in the <a, b> set, which the fits are based on
+// and which the failed evaluations are given by WorstOnFailure.
+func (gas *GeneticAlgorithmSolver) SetLowestGrade(fmin float64) {
+	gas.fmin, gas.fminFromRun = fmin, false
+}
+
+// SetSeed makes the solver draw its random numbers from a source seeded with the given value so
+// that the runs can be reproduced.
+func (gas *GeneticAlgorithmSolver) SetSeed(seed int64) {
+	gas.rng = rand.New(rand.NewSource(seed))
+}
+
+// SetTraceLevel sets how much of the operators' work is going to be recorded in the history
+// returned by Solve.
+func (gas *GeneticAlgorithmSolver) SetTraceLevel(level TraceLevel) {
+	gas.traceLevel = level
+}
+
+// SetTermination sets the conditions that stop Solve before it runs for all of the epochs. The
+// history returned by Solve ends with the epoch in which the run was stopped.
+func (gas *GeneticAlgorithmSolver) SetTermination(t Termination) {
+	gas.termination = t
+}
+
+// SetInitializer sets the function used for creating the initial population in Solve.
+func (gas *GeneticAlgorithmSolver) SetInitializer(init Initializer) {
+	gas.initFunc = init
+}
+
+// random returns the random number source of the solver. If none was seeded then a new one is
+// created based on the current time.
+func (gas *GeneticAlgorithmSolver) random() *rand.Rand {
+	if gas.rng == nil {
+		gas.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return gas.rng
+}
+
 // XBinToXInt converts x in binary form to x in integer form.
 func (gas GeneticAlgorithmSolver) XBinToXInt(arr []byte) int {
 	res := 0
@@ -141,9 +301,15 @@ func (gas GeneticAlgorithmSolver) XIntToXReal(xint int) float64 {
 	return val
 }
 
-// XRealToXInt converts x in floating point form to x in integer form.
+// XRealToXInt converts x in floating point form to x in integer form. The values decoded with
+// XIntToXReal are converted back to integers of the same value, so that e.g. the reinserted elite
+// keeps its value.
 func (gas GeneticAlgorithmSolver) XRealToXInt(xreal float64) int {
-	return int(math.Ceil((xreal - gas.a) * (math.Pow(2, float64(gas.l)) - 1) / (gas.b - gas.a)))
+	xint := int(math.Ceil((xreal - gas.a) * (math.Pow(2, float64(gas.l)) - 1) / (gas.b - gas.a)))
+	if xint > 0 && gas.XIntToXReal(xint) != xreal && gas.XIntToXReal(xint-1) == xreal {
+		xint--
+	}
+	return xint
 }
 
 // L returns the minimal bit size for representation of all of the population.
@@ -174,20 +340,43 @@ func (gas *GeneticAlgorithmSolver) Selection(vals ...float64) error {
 
 	// Calculate the grades and fits
 	N := len(vals)
-	grades := make([]float64, len(vals))
+	grades := gas.gradeAll(vals)
 	fits := make([]float64, len(vals))
 	for i := 0; i < N; i++ {
-		grades[i] = gas.Grade(vals[i])
-		fits[i] = gas.fit(vals[i])
+		fits[i] = gas.fit(grades[i])
 	}
 	gas.gradeCache = grades
 	gas.fitCache = fits
+	gas.roulette(vals)
+
+	return nil
+}
+
+// roulette calculates the selection probabilities and the cumulative distribution of the cached
+// fits of the population.
+func (gas *GeneticAlgorithmSolver) roulette(vals []float64) {
+	N, fits := len(vals), gas.fitCache
+	gas.fitSum = 0
+	for _, f := range fits {
+		gas.fitSum += f
+	}
 
 	// Calculate the probability
 	prob := make([]float64, len(vals))
 	probHBounds := make([]float64, len(vals))
-	for i := 0; i < N; i++ {
-		prob[i] = gas.probability(i)
+	if niched := gas.nichedFits(vals, fits); niched != nil {
+		// Niched fits are only used for picking the parents
+		sum := 0.0
+		for _, f := range niched {
+			sum += f
+		}
+		for i := 0; i < N; i++ {
+			prob[i] = niched[i] / sum
+		}
+	} else {
+		for i := 0; i < N; i++ {
+			prob[i] = gas.probability(i)
+		}
 	}
 	gas.probCache = prob
 
@@ -196,24 +385,55 @@ func (gas *GeneticAlgorithmSolver) Selection(vals ...float64) error {
 		probHBounds[i] = gas.cdfUpperBound(i)
 	}
 	gas.probHBCache = probHBounds
+}
 
-	return nil
+// Grade calculates the grade of the x argument at the point x. Every call is counted as a fitness
+// evaluation (every sample if the grades are resampled).
+func (gas *GeneticAlgorithmSolver) Grade(x float64) float64 {
+	if gas.objective != nil || gas.samples > 1 {
+		return gas.sampledGrade(x)
+	}
+	return gas.evaluate([]float64{x})[0]
+}
+
+// Evaluations returns the number of fitness evaluations made since the start of the last Solve.
+func (gas GeneticAlgorithmSolver) Evaluations() int {
+	return gas.evals
 }
 
-// Grade calculates the grade of the x argument at the point x.
-func (gas GeneticAlgorithmSolver) Grade(x float64) float64 {
-	return gas.gFunc(x)
+// fit returns the fit of a graded individual (implemented for searching MAX). The individual is
+// graded only once so that the grade and the fit of a noisy objective agree.
+func (gas *GeneticAlgorithmSolver) fit(grade float64) float64 {
+	return gas.fitFromGrade(grade)
 }
 
-// fit returns x's fit (implemented for searching MAX).
-func (gas *GeneticAlgorithmSolver) fit(x float64) float64 {
-	fit := gas.Grade(x) - gas.fmin + math.Pow(1, -float64(gas.d))
-	gas.fitSum += fit
-	return fit
+// fitFromGrade returns the fit of an already graded x. The grades lower than fmin (which is only
+// known approximately) get the lowest fit.
+func (gas *GeneticAlgorithmSolver) fitFromGrade(grade float64) float64 {
+	return math.Max(grade-gas.fmin, 0) + math.Pow(1, -float64(gas.d))
 }
 
-// Probability calculates the probability of the i-th fit. Should be ran after all the Grade and Fit
-// calls.
+// takeLowestGrade sets fmin to the lowest grade of the initial population and gives it to the failed
+// evaluations of the population, which were graded with the infinite fmin. fmin is 0 if every
+// evaluation failed.
+func (gas *GeneticAlgorithmSolver) takeLowestGrade(grades []float64) {
+	for _, grade := range grades {
+		if grade < gas.fmin {
+			gas.fmin = grade
+		}
+	}
+	if math.IsInf(gas.fmin, 1) {
+		gas.fmin = 0
+	}
+	for i := range grades {
+		if math.IsInf(grades[i], 1) {
+			grades[i] = gas.fmin
+		}
+	}
+}
+
+// Probability calculates the probability of the i-th fit. Should be ran after the sum of the cached
+// fits is calculated by roulette.
 func (gas *GeneticAlgorithmSolver) probability(i int) float64 {
 	return gas.fitCache[i] / gas.fitSum
 }
@@ -249,7 +469,18 @@ func (gas *GeneticAlgorithmSolver) Crossover(cp float64) (parents [][]byte, offs
 		return nil, nil, nil, errors.New("provided invalid crossover probability value")
 	}
 
+	parents, offsprings, cutpoints, _ = gas.crossover(cp)
+	return
+}
+
+// crossover runs the crossover without checking the crossover probability (used with the adapted
+// probabilities). partners holds the index of the mate of every individual (-1 if it had none).
+func (gas *GeneticAlgorithmSolver) crossover(cp float64) (parents [][]byte, offsprings [][]byte, cutpoints []int, partners []int) {
 	cutpoints = make([]int, len(gas.popArr))
+	partners = make([]int, len(gas.popArr))
+	for i := range partners {
+		partners[i] = -1
+	}
 
 	// Pick parents in a random manner from the current population
 	// rand.Seed(time.Now().Unix())
@@ -290,7 +521,7 @@ func (gas *GeneticAlgorithmSolver) Crossover(cp float64) (parents [][]byte, offs
 		}
 
 		// Get random cut point and crossover them in place of i and j
-		cut := rand.Intn(gas.l - 1)
+		cut := gas.random().Intn(gas.l - 1)
 		slice1, slice2 := parentA[cut:], parentB[cut:]
 		for k := 0; k < cut; k++ {
 			offsprings[i][k] = parentA[k]
@@ -304,6 +535,10 @@ func (gas *GeneticAlgorithmSolver) Crossover(cp float64) (parents [][]byte, offs
 		}
 
 		cutpoints[i], cutpoints[j] = cut, cut
+		partners[i], partners[j] = j, i
+		if gas.trace != nil && gas.traceLevel >= TraceOperators {
+			gas.trace.Pairs = append(gas.trace.Pairs, [2]int{i, j})
+		}
 		parentA, parentB = nil, nil
 		i = j
 	}
@@ -315,6 +550,9 @@ func (gas *GeneticAlgorithmSolver) Crossover(cp float64) (parents [][]byte, offs
 		}
 		gas.popArr[i] = offsprings[i]
 	}
+	if gas.trace != nil && gas.traceLevel >= TraceOperators {
+		gas.trace.CutPoints = append([]int(nil), cutpoints...)
+	}
 
 	return
 }
@@ -328,13 +566,25 @@ func (gas *GeneticAlgorithmSolver) Mutate(mp float64) (mutations [][]int, err er
 		return nil, errors.New("provided invalid mutation probability value")
 	}
 
+	return gas.mutate(mp), nil
+}
+
+// mutate runs the mutation without checking the mutation probability (used with the adapted
+// probabilities).
+func (gas *GeneticAlgorithmSolver) mutate(mp float64) (mutations [][]int) {
 	mutations = make([][]int, len(gas.popArr))
+	var draws [][]float64
+	if gas.trace != nil && gas.traceLevel >= TraceFull {
+		draws = make([][]float64, len(gas.popArr))
+	}
 
-	// rand.Seed(time.Now().Unix())
 	for i := 0; i < len(gas.popArr); i++ {
 		var localMutations []int
 		for j := 0; j < len(gas.popArr[i]); j++ {
-			r := rand.Float64()
+			r := gas.random().Float64()
+			if draws != nil {
+				draws[i] = append(draws[i], r)
+			}
 			if r <= mp {
 				localMutations = append(localMutations, j)
 				if gas.popArr[i][j] == 0 {
@@ -348,12 +598,17 @@ func (gas *GeneticAlgorithmSolver) Mutate(mp float64) (mutations [][]int, err er
 			mutations[i] = localMutations
 		}
 	}
+	if gas.trace != nil && gas.traceLevel >= TraceOperators {
+		gas.trace.Mutations = mutations
+		gas.trace.MutationDraws = draws
+	}
 
 	return
 }
 
 // saveStateToHistory saves the current state of a genetic algorithm solver to an epoch data struct.
-func (gas GeneticAlgorithmSolver) saveStateToHistory(N int, vals []float64, ed *EpochData) (err error) {
+func (gas GeneticAlgorithmSolver) saveStateToHistory(ed *EpochData) (err error) {
+	N, vals := len(gas.popVals), gas.popVals
 	ed.PopulationF64 = make([]float64, N)
 	ed.Fits = make([]float64, N)
 	ed.Grades = make([]float64, N)
@@ -377,7 +632,17 @@ func (gas GeneticAlgorithmSolver) saveStateToHistory(N int, vals []float64, ed *
 
 	ed.PopulationBytes = gas.Population()
 	ed.Elite = gas.elite
-	ed.EliteFit = gas.eliteFit
+	ed.EliteFit, ed.EliteGrade = gas.eliteFit, gas.eliteGrade
+	ed.EliteEpoch = gas.eliteEpoch
+	ed.Evaluations, ed.LSEvaluations = gas.evals, gas.lsEvals
+	ed.Saved, ed.Failed = gas.saved, gas.failed
+	ed.CP, ed.MP = gas.epochCP, gas.epochMP
+	ed.Diversity = MeasureDiversity(gas.popArr, vals, gas.fitCache)
+	ed.Trace = gas.trace
+	ed.Variance = gas.variance()
+	if gas.popID != nil {
+		ed.IDs = append([]int(nil), gas.popID...)
+	}
 
 	// fmin, favg fmax - values of best, worst and max values of this epoch
 	ed.FMin, ed.FAVG, ed.FMax = 100000000000, 0, -100000
@@ -392,16 +657,35 @@ func (gas GeneticAlgorithmSolver) saveStateToHistory(N int, vals []float64, ed *
 		ed.FAVG += gas.gradeCache[i]
 	}
 	ed.FAVG = ed.FAVG / float64(i)
+	gas.compareWithReference(ed)
 
 	return
 }
 
-// updateElite searches for a new elite and updates the solver data.
+// beginTrace starts recording the trace of a new epoch if tracing is enabled. The roulette table
+// is copied from the last selection as that's the one used for picking the parents.
+func (gas *GeneticAlgorithmSolver) beginTrace() {
+	if gas.traceLevel == TraceOff {
+		gas.trace = nil
+		return
+	}
+	gas.trace = &EpochTrace{
+		Probabilities:   append([]float64(nil), gas.probCache...),
+		CDFBounds:       append([]float64(nil), gas.probHBCache...),
+		EliteInsertedAt: -1,
+	}
+}
+
+// updateElite searches for a new elite and updates the solver data. The individuals with the grades
+// predicted by the surrogate model are skipped.
 func (gas *GeneticAlgorithmSolver) updateElite(vals []float64) {
 	for i := 0; i < len(gas.fitCache); i++ {
-		if gas.eliteFit < gas.fitCache[i] {
+		if gas.eliteFit < gas.fitCache[i] && gas.realGrade(vals[i]) {
 			gas.eliteFit = gas.fitCache[i]
+			gas.eliteGrade = gas.gradeCache[i]
 			gas.elite = vals[i]
+			gas.eliteEpoch = gas.epoch
+			gas.setEliteAt(i)
 		}
 	}
 }
@@ -410,104 +694,258 @@ func (gas *GeneticAlgorithmSolver) updateElite(vals []float64) {
 // a given crossing probability, for a given mutation probability and returns a history of the
 // algorithm's execution.
 func (gas *GeneticAlgorithmSolver) Solve(N, epochs int, cp, mp float64) (hist []EpochData, err error) {
-	// Create a history
-	hist = make([]EpochData, epochs+1)
+	ed, err := gas.Start(N, epochs, cp, mp)
+	if err != nil {
+		return
+	}
+	if epochs == 0 && gas.niching.Method != NoNiching {
+		ed.Optima = gas.Optima(gas.niching.Radius)
+	}
+	if epochs == 0 {
+		ed.Genealogy = gas.genealogy
+	}
+	hist = append(make([]EpochData, 0, epochs+1), ed)
+
+	// Run as many times as it was specified (+1 because we saved in 0 the state before the algorithm)
+	for i := 1; i < epochs+1; i++ {
+		var done bool
+		ed, done, err = gas.Step()
+		if err != nil {
+			return
+		}
+		hist = append(hist, ed)
+		if done {
+			break
+		}
+	}
+
+	return
+}
+
+// Start initializes the solver the same way as Solve and returns the history entry of the initial
+// population. The following epochs are run with Step.
+func (gas *GeneticAlgorithmSolver) Start(N, epochs int, cp, mp float64) (ed EpochData, err error) {
+	err = checkProbabilities(cp, mp)
+	if err != nil {
+		return
+	}
+	if gas.replacement == CommaSelection && gas.lambda != 0 && gas.lambda < N {
+		err = errors.New("comma selection needs at least as many offspring as the population size")
+		return
+	}
+	err = gas.resetSurrogate()
+	if err != nil {
+		return
+	}
+	gas.failure, gas.failed = nil, 0
+	// The initializer can grade the individuals of the first epoch already
+	gas.epoch, gas.eliteEpoch = 0, 0
+	gas.evals, gas.lsEvals = 0, 0
+	gas.resetNoise()
 
 	// Initialize the solver
-	vals := make([]float64, N)
+	init := gas.initFunc
+	if init == nil {
+		init = UniformRealInit
+	}
+	vals, err := init(gas, N)
+	if err != nil {
+		return
+	} else if len(vals) != N {
+		err = fmt.Errorf("initializer created %d individuals instead of %d", len(vals), N)
+		return
+	}
+	gas.popVals = vals
 	gas.popArr = make([][]byte, N)
+	gas.popAge = make([]int, N)
 	gas.fitCache = make([]float64, N)
 	gas.gradeCache = make([]float64, N)
-	rand.Seed(time.Now().UnixNano())
-	gas.eliteFit = -10000000
+	gas.trace = nil
+	gas.lastRestart = 0
+	gas.epochCP, gas.epochMP = 0, 0
+	gas.runCP, gas.runMP, gas.runEpochs = cp, mp, epochs
+	gas.resetReference()
+	gas.resetDynamics()
+	gas.eliteFit, gas.eliteGrade = -10000000, -10000000
 	gas.elite = gas.eliteFit
 
+	eliteIdx := 0
+	if gas.fminFromRun {
+		// The failed evaluations are recognized by the grade given to them
+		gas.fmin = math.Inf(1)
+	}
+	grades := gas.gradeReal(vals)
+	if gas.fminFromRun {
+		gas.takeLowestGrade(grades)
+	}
 	for i := 0; i < N; i++ {
-		// Create a population
-		vals[i] = gas.a + rand.Float64()*(gas.b-gas.a)
-		vals[i] = math.Round(vals[i]*math.Pow10(int(gas.d))) / math.Pow10(int(gas.d))
-
-		// Calculate its grades
+		// Calculate the grades of the population
 		gas.popArr[i] = gas.XIntToXBin(uint32(gas.XRealToXInt(vals[i])))
-		gas.fitCache[i] = gas.fit(vals[i])
-		gas.gradeCache[i] = gas.Grade(vals[i])
+		gas.gradeCache[i] = grades[i]
+		gas.fitCache[i] = gas.fit(gas.gradeCache[i])
 
 		// If fit's the best then pick it as an elite
 		if gas.fitCache[i] > gas.eliteFit {
 			gas.elite = vals[i]
 			gas.eliteFit = gas.fitCache[i]
+			gas.eliteGrade = gas.gradeCache[i]
+			eliteIdx = i
 		}
 	}
+	gas.startGenealogy()
+	gas.setEliteAt(eliteIdx)
 
 	// Save the current state to the history
-	err = gas.saveStateToHistory(N, vals, &hist[0])
+	err = gas.saveStateToHistory(&ed)
 	if err != nil {
 		return
 	}
 
-	// Run the selection
-	err = gas.Selection(vals...)
+	// Prepare the selection from the cached grades
+	gas.roulette(gas.popVals)
+	err = gas.takeFailure()
+	return
+}
+
+// Step runs a single epoch of the solver started with Start and returns its history entry. done is
+// set if any of the termination conditions is met or the epoch is the last one passed to Start. The
+// entry of the last epoch holds the optima found by niching.
+func (gas *GeneticAlgorithmSolver) Step() (ed EpochData, done bool, err error) {
+	if gas.popVals == nil {
+		err = errors.New("solver has to be started before running an epoch")
+		return
+	}
+
+	// Updates elites
+	gas.updateElite(gas.popVals)
+	gas.epoch++
+	ed.Change, err = gas.respondToChange()
+	if err != nil {
+		return
+	}
+	gas.beginTrace()
+
+	// Adapt the probabilities and create the next population
+	ecp, emp := gas.adaptRates(gas.runCP, gas.runMP, gas.runEpochs)
+	emp = gas.hypermutate(emp)
+	if gas.niching.Method == DeterministicCrowding || gas.niching.Method == RestrictedTournament {
+		err = gas.crowdingEpoch(ecp, emp)
+	} else if gas.replacement == InPlace {
+		err = gas.inPlaceEpoch(ecp, emp)
+	} else {
+		err = gas.breedEpoch(ecp, emp)
+	}
 	if err != nil {
 		return
 	}
 
-	// Run as many times as it was specified (+1 because we saved in 0 the state before the algorithm)
-	for i := 1; i < epochs+1; i++ {
-		// Updates elites
-		gas.updateElite(vals)
+	// Refine the population with the local search
+	if gas.localSearch.Method != NoLocalSearch {
+		gas.refine()
+	}
 
-		// Run crossover
-		_, _, _, err = gas.Crossover(cp)
-		if err != nil {
-			return
-		}
+	// Grade the most promising individuals whose grades were only predicted
+	gas.verifyPredictions()
 
-		// Run mutation
-		_, err = gas.Mutate(mp)
-		if err != nil {
-			return
-		}
+	// Restart the population if the run stagnated
+	restarted, err := gas.restartIfStagnated()
+	if err != nil {
+		return
+	} else if restarted {
+		ed.Restart = gas.restart.Policy.String()
+	}
 
-		// Update f64 population and calculate the new fits
-		for i := 0; i < N; i++ {
-			vals[i] = gas.XIntToXReal(gas.XBinToXInt(gas.popArr[i]))
-		}
+	// Abort the run if an evaluation failed
+	err = gas.takeFailure()
+	if err != nil {
+		return
+	}
 
-		// Run selection before the next run (and for saving the state of the epoch after it)
-		err = gas.Selection(vals...)
-		if err != nil {
-			return
-		}
+	// Save to history
+	err = gas.saveStateToHistory(&ed)
+	if err != nil {
+		return
+	}
 
-		// Check if elite is still in - if not put it in a random place (unless the random place is better)
-		eliteIn := false
-		for i := 0; i < N; i++ {
-			if gas.eliteFit == gas.fitCache[i] {
-				eliteIn = true
-				break
-			}
+	// Stop early if any of the termination conditions is met
+	done = gas.epoch >= gas.runEpochs ||
+		gas.termination.Done(gas.epoch, gas.evals, gas.eliteGrade, gas.eliteEpoch)
+	if done && gas.niching.Method != NoNiching {
+		ed.Optima = gas.Optima(gas.niching.Radius)
+	}
+	if done {
+		ed.Genealogy = gas.genealogy
+	}
+	return
+}
+
+// checkProbabilities checks if the crossover and mutation probabilities are in the same bounds as
+// the ones accepted by Crossover and Mutate.
+func checkProbabilities(cp, mp float64) error {
+	if 0.5 > cp || cp > 1 {
+		return errors.New("provided invalid crossover probability value")
+	} else if mp <= 0 || mp > 0.01 {
+		return errors.New("provided invalid mutation probability value")
+	}
+	return nil
+}
+
+// inPlaceEpoch creates the next population by crossing over and mutating the current one in place.
+// If the elite got lost on the way then it's put back in a random place (unless the random place
+// is better).
+func (gas *GeneticAlgorithmSolver) inPlaceEpoch(cp, mp float64) (err error) {
+	N, vals := len(gas.popArr), gas.popVals
+
+	// Run crossover and mutation
+	parentIDs := append([]int(nil), gas.popID...)
+	_, _, cutpoints, partners := gas.crossover(cp)
+	mutations := gas.mutate(mp)
+
+	// Update f64 population and calculate the new fits
+	for i := 0; i < N; i++ {
+		vals[i] = gas.XIntToXReal(gas.XBinToXInt(gas.popArr[i]))
+		gas.popAge[i] = gas.epoch
+	}
+
+	// Run selection before the next run (and for saving the state of the epoch after it)
+	err = gas.Selection(vals...)
+	if err != nil {
+		return
+	}
+	if gas.genealogy != nil {
+		gas.recordInPlaceEpoch(parentIDs, partners, cutpoints, mutations)
+	}
+
+	// Check if elite is still in - if not put it in a random place (unless the random place is better)
+	eliteIn := false
+	for i := 0; i < N; i++ {
+		if vals[i] == gas.elite || gas.eliteFit == gas.fitCache[i] {
+			eliteIn = true
+			break
 		}
-		if !eliteIn {
-			i := rand.Intn(N)
-
-			if gas.eliteFit < gas.fitCache[i] {
-				gas.elite = vals[i]
-				gas.eliteFit = gas.fitCache[i]
-			} else {
-				gas.popArr[i] = gas.XIntToXBin(uint32(gas.XRealToXInt(gas.elite)))
-				vals[i] = gas.elite
-
-				err = gas.Selection(vals...)
-				if err != nil {
-					return
-				}
+	}
+	if !eliteIn {
+		i := gas.random().Intn(N)
+
+		if gas.eliteFit < gas.fitCache[i] && gas.realGrade(vals[i]) {
+			gas.elite = vals[i]
+			gas.eliteFit = gas.fitCache[i]
+			gas.eliteGrade = gas.gradeCache[i]
+			gas.eliteEpoch = gas.epoch
+			gas.setEliteAt(i)
+		} else {
+			gas.popArr[i] = gas.XIntToXBin(uint32(gas.XRealToXInt(gas.elite)))
+			vals[i] = gas.elite
+			if gas.trace != nil {
+				gas.trace.EliteInsertedAt = i
 			}
-		}
 
-		// Save to history
-		err = gas.saveStateToHistory(N, vals, &hist[i])
-		if err != nil {
-			return
+			// The elite keeps its grade instead of being graded again
+			gas.gradeCache[i], gas.fitCache[i] = gas.eliteGrade, gas.eliteFit
+			gas.roulette(vals)
+			if gas.genealogy != nil {
+				gas.popID[i] = gas.eliteCopy(i)
+			}
 		}
 	}
 
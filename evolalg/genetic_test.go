@@ -2,11 +2,8 @@ package evolalg
 
 import (
 	"fmt"
-	"io/ioutil"
 	"math"
-	"strings"
 	"testing"
-	"time"
 )
 
 // func TestGenAlgorithmConstructor(t *testing.T) {
@@ -152,138 +149,82 @@ import (
 // 	}
 // }
 
-func run(a, b, cp, mp float64, d byte, N, epochs int, bench *testing.B) (fmin, favg, fmax float64) {
-	// Generate the values if all the necessary data was given
-	var hist []EpochData
-
-	// solver for this assignment with a grading function described by this formula:
-	// F(x)= x MOD1 *(COS(20*π *x)–SIN(x))
-	// d = 0,001 -> 3
-	gas, err := NewGeneticAlgorithmSolver(a, b, d, func(x float64) float64 {
-		return math.Mod(x, 1*(math.Cos(20*math.Pi*x)-math.Sin(x)))
-	})
-	if err != nil {
-		bench.Log(err)
-		bench.Fail()
-		return
-	}
-
-	hist, err = gas.Solve(N, epochs, cp, mp)
-	if err != nil {
-		bench.Log(err)
-		bench.Fail()
-		return
-	}
-
-	// Create the fmax, favg, fmin graph
-	// fmax, favg, fmin := make([]float64, len(hist)), make([]float64, len(hist)), make([]float64, len(hist))
-	// epochsArr := make([]float64, len(hist))
-	// i := 0
-	// for ; i < len(hist); i++ {
-	// 	fmax[i] = hist[i].FMax
-	// 	favg[i] = hist[i].FAVG
-	// 	fmin[i] = hist[i].FMin
-	// 	epochsArr[i] = float64(i)
-	// }
-	fmin = hist[len(hist)-1].FMin
-	favg = hist[len(hist)-1].FAVG
-	fmax = hist[len(hist)-1].FMax
-	return
-
-	// sc <- fmt.Sprintf("%d, %d, %f, %f, %f, %f, %f", N, epochs, cp, mp, fmax, favg, fmin)
-}
-
-func runLoop(a, b, cp, mp float64, d byte, N, epochs, iters int, bench *testing.B, sc chan string) {
-	fmax, favg, fmin := 0.0, 0.0, 0.0
-	for i := 0; i < iters; i++ {
-		fminI, favgI, fmaxI := run(a, b, cp, mp, d, N, epochs, bench)
-		fmin += fminI
-		favg += favgI
-		fmax += fmaxI
+func TestSolveTrace(t *testing.T) {
+	solve := func(seed int64) []EpochData {
+		gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, func(x float64) float64 {
+			return math.Mod(x, 1*(math.Cos(20*math.Pi*x)-math.Sin(x)))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		gas.SetSeed(seed)
+		gas.SetTraceLevel(TraceFull)
+		hist, err := gas.Solve(10, 5, 0.75, 0.005)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hist
 	}
-	fmax, favg, fmin = fmax/float64(iters), favg/float64(iters), fmin/float64(iters)
-
-	sc <- fmt.Sprintf("%d, %d, %f, %f, %f, %f, %f\n", N, epochs, cp, mp, fmax, favg, fmin)
-}
-
-func BenchmarkLabs(bench *testing.B) {
-	a, b := float64(-4), float64(12)
-	d := byte(3)
 
-	// N set
-	var Ns []int
-	for i := 30; i <= 80; i += 5 {
-		Ns = append(Ns, i)
+	hist := solve(42)
+	if hist[0].Trace != nil {
+		t.Log("initial population should not have a trace")
+		t.Fail()
 	}
-	NsLen := len(Ns)
-
-	// cp set
-	var cps []float64
-	for i := 0.50; i <= 0.9; i += 0.05 {
-		cps = append(cps, i)
+	for i := 1; i < len(hist); i++ {
+		tr := hist[i].Trace
+		if tr == nil {
+			t.Log(fmt.Sprintf("epoch %d has no trace", i))
+			t.Fail()
+			continue
+		}
+		if len(tr.Probabilities) != 10 || len(tr.CDFBounds) != 10 || len(tr.CutPoints) != 10 {
+			t.Log(fmt.Sprintf("epoch %d has an incomplete roulette table or cut points", i))
+			t.Fail()
+		}
+		sum := 0.0
+		for _, p := range tr.Probabilities {
+			sum += p
+		}
+		if math.Abs(sum-1) > 1e-9 || math.Abs(tr.CDFBounds[len(tr.CDFBounds)-1]-1) > 1e-9 {
+			t.Log(fmt.Sprintf("epoch %d: roulette probabilities sum to %f and the distribution ends at %f", i, sum,
+				tr.CDFBounds[len(tr.CDFBounds)-1]))
+			t.Fail()
+		}
+		if hist[i].EliteGrade != LabFunction(hist[i].Elite) {
+			t.Log(fmt.Sprintf("epoch %d: elite %f has the grade %f", i, hist[i].Elite, hist[i].EliteGrade))
+			t.Fail()
+		}
+		if len(tr.MutationDraws) != 10 || len(tr.MutationDraws[0]) != len(hist[i].PopulationBytes[0]) {
+			t.Log(fmt.Sprintf("epoch %d does not have a draw for every bit", i))
+			t.Fail()
+		}
 	}
-	cpsLen := len(cps)
 
-	// mp set
-	var mps []float64
-	mps = append(mps, 0.0001)
-	for i := 0.0005; i <= 0.01; i += 0.0005 {
-		mps = append(mps, i)
+	// The same seed has to give the same history
+	again := solve(42)
+	for i := range hist {
+		if hist[i].Elite != again[i].Elite || hist[i].FAVG != again[i].FAVG {
+			t.Log(fmt.Sprintf("epoch %d differs between two runs with the same seed", i))
+			t.Fail()
+		}
 	}
-	mpsLen := len(mps)
+}
 
-	// epoch set
-	var epochSet []int
-	for i := 50; i <= 150; i += 10 {
-		epochSet = append(epochSet, i)
+func TestEncodingRoundTrip(t *testing.T) {
+	gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, LabFunction)
+	if err != nil {
+		t.Fatal(err)
 	}
-	epochsLen := len(epochSet)
-
-	bench.StartTimer()
-	workerRoutineCnt := 16
-	strChan := make(chan string, workerRoutineCnt)
-	// var wg sync.WaitGroup
-	var sb strings.Builder
-	sb.WriteString("N, epochs, cp, mp, fmax, favg, fmin\n")
-	launched := 0
-	for i := 0; i < mpsLen; i++ {
-		for j := 0; j < epochsLen; j++ {
-			for k := 0; k < NsLen; k++ {
-				for l := 0; l < cpsLen; l++ {
-					for len(strChan) > 0 || launched == workerRoutineCnt {
-						select {
-						case foo, ok := <-strChan:
-							if ok {
-								sb.WriteString(foo)
-								launched--
-							} else {
-								panic("string channel closed")
-							}
-						default:
-							time.Sleep(time.Microsecond * 20)
-						}
-					}
 
-					// go run(a, b, cps[l], mps[i], d, Ns[k], epochSet[j], bench, strChan)
-					go runLoop(a, b, cps[l], mps[i], d, Ns[k], epochSet[j], 2, bench, strChan)
-					launched++
-				}
-			}
+	// Every decoded value is encoded back to a genome of the same value, so a reinserted elite keeps
+	// its value
+	for xint := 0; xint < 1<<uint(gas.L()); xint++ {
+		x := gas.XIntToXReal(xint)
+		if got := gas.XIntToXReal(gas.XRealToXInt(x)); got != x {
+			t.Log(fmt.Sprintf("%d was decoded to %f and encoded back as %f", xint, x, got))
+			t.Fail()
+			break
 		}
 	}
-	for len(strChan) > 0 {
-		select {
-		case foo, ok := <-strChan:
-			if ok {
-				sb.WriteString(foo)
-			} else {
-				panic("string channel closed")
-			}
-		default:
-			time.Sleep(time.Microsecond * 20)
-		}
-	}
-	bench.StopTimer()
-
-	ioutil.WriteFile("../static/stats.csv", []byte(sb.String()), 0666)
 }
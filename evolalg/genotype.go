@@ -0,0 +1,61 @@
+package evolalg
+
+import (
+	"errors"
+	"math"
+)
+
+// Lengths of the shortest and the longest genomes supported by the binary encoding of
+// GeneticAlgorithmSolver. The crossover needs a cut point between two bits.
+const (
+	minGenotypeLen = 2
+	maxGenotypeLen = 32
+)
+
+// Genotype is a fitness function defined directly on the bits of the genome instead of on the real
+// value decoded from it.
+type Genotype interface {
+	// Len returns the length of the genome in bits.
+	Len() int
+	// Fitness returns the non-negative fitness of the genome. The first byte of the genome is its
+	// most significant bit.
+	Fitness(genome []byte) float64
+}
+
+// NewGenotypeSolver creates a genetic algorithm solver maximizing the fitness of g. The genomes are
+// decoded to the integers of the <0, 2^l - 1> set (d = 0), so all of the operators of the solver work
+// unchanged and the grade of a decoded x is the fitness of its genome.
+func NewGenotypeSolver(g Genotype) (gas GeneticAlgorithmSolver, err error) {
+	l := g.Len()
+	if l < minGenotypeLen || l > maxGenotypeLen {
+		err = errors.New("genome length has to be between 2 and 32 bits")
+		return
+	}
+
+	gas.a = 0
+	gas.b = math.Pow(2, float64(l)) - 1
+	gas.d = 0
+	gas.l = l
+	gas.popSize = uint(gas.b) + 1
+	gas.fmin = 0 // the fitness is non-negative
+
+	enc := GeneticAlgorithmSolver{l: l}
+	gas.fitness = FitnessFunc(func(x float64) float64 { return g.Fitness(enc.XIntToXBin(uint32(x))) })
+	return
+}
+
+// GenomeFunc adapts a function of the genome to the Genotype interface.
+type GenomeFunc struct {
+	L int                         // length of the genome
+	F func(genome []byte) float64 // fitness of the genome
+}
+
+// Len returns the length of the genome.
+func (g GenomeFunc) Len() int {
+	return g.L
+}
+
+// Fitness returns the fitness of the genome.
+func (g GenomeFunc) Fitness(genome []byte) float64 {
+	return g.F(genome)
+}
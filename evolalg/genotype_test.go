@@ -0,0 +1,92 @@
+package evolalg
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// oneMax counts the ones of a genome of l bits.
+func oneMax(l int) Genotype {
+	return GenomeFunc{L: l, F: func(genome []byte) float64 {
+		sum := 0.0
+		for _, b := range genome {
+			sum += float64(b)
+		}
+		return sum
+	}}
+}
+
+func TestGenotypeSolver(t *testing.T) {
+	if _, err := NewGenotypeSolver(oneMax(0)); err == nil {
+		t.Log("empty genome was accepted")
+		t.Fail()
+	}
+	if _, err := NewGenotypeSolver(oneMax(33)); err == nil {
+		t.Log("genome longer than 32 bits was accepted")
+		t.Fail()
+	}
+	if _, err := NewGenotypeSolver(oneMax(1)); err == nil {
+		t.Log("genome without a cut point was accepted")
+		t.Fail()
+	}
+	short, err := NewGenotypeSolver(oneMax(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	short.SetSeed(1)
+	if _, err := short.Solve(4, 5, 0.9, 0.01); err != nil {
+		t.Fatal(err)
+	}
+
+	// The decoded integers map back to the same genomes
+	gas, err := NewGenotypeSolver(oneMax(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gas.L() != 8 {
+		t.Log(fmt.Sprintf("genome has %d bits instead of 8", gas.L()))
+		t.Fail()
+	}
+	for _, x := range []int{0, 1, 37, 255} {
+		if got := gas.XRealToXInt(gas.XIntToXReal(x)); got != x {
+			t.Log(fmt.Sprintf("%d was decoded to %d", x, got))
+			t.Fail()
+		}
+	}
+	if g := gas.Grade(gas.XIntToXReal(0xF0)); g != 4 {
+		t.Log(fmt.Sprintf("grade of 11110000 is %f instead of 4", g))
+		t.Fail()
+	}
+
+	// The binary solvers of the registry accept the problems defined on genomes while the other
+	// ones reject them
+	p := Problem{Genotype: oneMax(12)}
+	for _, name := range []string{"ga", "sa", "tabu"} {
+		a, _ := AlgorithmByName(name)
+		s, err := a.New(p)
+		if err != nil {
+			t.Log(fmt.Sprintf("%s: %s", name, err.Error()))
+			t.Fail()
+			continue
+		}
+		err = s.Configure(Params{"epoki": "30", "ziarno": "1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		hist, err := s.Run(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		last := hist[len(hist)-1]
+		if last.Elite < 0 || last.Elite > 4095 || last.EliteFit < 6 {
+			t.Log(fmt.Sprintf("%s: elite %f with fit %f is not improved", name, last.Elite, last.EliteFit))
+			t.Fail()
+		}
+	}
+	a, _ := AlgorithmByName("de")
+	if _, err := a.New(p); err == nil {
+		t.Log("differential evolution accepted a problem defined on genomes")
+		t.Fail()
+	}
+}
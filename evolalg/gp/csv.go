@@ -0,0 +1,54 @@
+package gp
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Sample is a single (x, y) data point of a symbolic regression problem.
+type Sample struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// LoadSamples reads the samples from a CSV with the x and y values in the first two columns. Both
+// commas and semicolons are accepted as separators. The first row is skipped if it's a header.
+func LoadSamples(r io.Reader) (samples []Sample, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return
+	}
+	text := string(data)
+	reader := csv.NewReader(strings.NewReader(text))
+	if strings.Count(text, ";") > strings.Count(text, ",") {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return
+	}
+	for i, rec := range records {
+		if len(rec) < 2 {
+			return nil, errors.New("row " + strconv.Itoa(i+1) + " has less than 2 columns")
+		}
+		x, errX := strconv.ParseFloat(strings.TrimSpace(rec[0]), 64)
+		y, errY := strconv.ParseFloat(strings.TrimSpace(rec[1]), 64)
+		if errX != nil || errY != nil {
+			if i == 0 {
+				continue
+			}
+			return nil, errors.New("row " + strconv.Itoa(i+1) + " is not a pair of numbers")
+		}
+		samples = append(samples, Sample{X: x, Y: y})
+	}
+	if len(samples) == 0 {
+		err = errors.New("no samples were found")
+	}
+	return
+}
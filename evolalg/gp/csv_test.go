@@ -0,0 +1,32 @@
+package gp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLoadSamples(t *testing.T) {
+	for _, data := range []string{
+		"x,y\n1,2\n2,4.5\n3,8\n",
+		"1;2\n2;4.5\n3;8",
+	} {
+		samples, err := LoadSamples(strings.NewReader(data))
+		if err != nil {
+			t.Log(err.Error())
+			t.Fail()
+			continue
+		}
+		if len(samples) != 3 || samples[1] != (Sample{X: 2, Y: 4.5}) {
+			t.Log(fmt.Sprintf("wrong samples loaded: %v", samples))
+			t.Fail()
+		}
+	}
+
+	for _, data := range []string{"", "x,y\n", "1,2\n3,a\n", "1\n"} {
+		if _, err := LoadSamples(strings.NewReader(data)); err == nil {
+			t.Log(fmt.Sprintf("invalid CSV %q was accepted", data))
+			t.Fail()
+		}
+	}
+}
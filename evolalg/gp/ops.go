@@ -0,0 +1,87 @@
+package gp
+
+import "math/rand"
+
+// terminal creates a random terminal - the variable x or, if constants are enabled, a constant
+// from the <-constRange, constRange> set with equal probability.
+func terminal(rng *rand.Rand, cfg Config) *Node {
+	if cfg.ConstRange > 0 && rng.Intn(2) == 0 {
+		return &Node{Value: (2*rng.Float64() - 1) * cfg.ConstRange}
+	}
+	return &Node{Var: true}
+}
+
+// grow creates a random tree with a depth of at most depth. If full is true then every branch
+// reaches the depth.
+func grow(rng *rand.Rand, cfg Config, depth int, full bool) *Node {
+	nFns, nTerms := len(cfg.Functions), 1
+	if cfg.ConstRange > 0 {
+		nTerms = 2
+	}
+	if depth == 0 || (!full && rng.Intn(nFns+nTerms) >= nFns) {
+		return terminal(rng, cfg)
+	}
+	fn := &cfg.Functions[rng.Intn(nFns)]
+	n := &Node{Fn: fn, Children: make([]*Node, fn.Arity)}
+	for i := range n.Children {
+		n.Children[i] = grow(rng, cfg, depth-1, full)
+	}
+	return n
+}
+
+// rampedHalfAndHalf creates N trees with the depths spread evenly over the <2, InitDepth> set. Half
+// of the trees of every depth are created with the full method and half with the grow method.
+func rampedHalfAndHalf(rng *rand.Rand, cfg Config, N int) []*Node {
+	pop := make([]*Node, N)
+	depths := cfg.InitDepth - 1
+	for i := range pop {
+		depth := 2 + (i/2)%depths
+		pop[i] = grow(rng, cfg, depth, i%2 == 0)
+	}
+	return pop
+}
+
+// pickNode picks a random node of the tree. Function nodes are picked with 90% probability (if
+// there are any) so that the operators don't swap mostly leaves (Koza, 1992).
+func pickNode(rng *rand.Rand, n *Node) *Node {
+	nodes := n.nodes()
+	var fns, terms []*Node
+	for _, node := range nodes {
+		if node.Fn != nil {
+			fns = append(fns, node)
+		} else {
+			terms = append(terms, node)
+		}
+	}
+	if len(fns) > 0 && rng.Float64() < 0.9 {
+		return fns[rng.Intn(len(fns))]
+	}
+	return terms[rng.Intn(len(terms))]
+}
+
+// crossover swaps random subtrees of copies of the parents. A child deeper than maxDepth is
+// replaced by its parent.
+func crossover(rng *rand.Rand, p1, p2 *Node, maxDepth int) (*Node, *Node) {
+	c1, c2 := p1.Clone(), p2.Clone()
+	n1, n2 := pickNode(rng, c1), pickNode(rng, c2)
+	s1, s2 := n1.Clone(), n2.Clone()
+	c1, c2 = c1.replace(n1, s2), c2.replace(n2, s1)
+	if c1.Depth() > maxDepth {
+		c1 = p1.Clone()
+	}
+	if c2.Depth() > maxDepth {
+		c2 = p2.Clone()
+	}
+	return c1, c2
+}
+
+// mutate replaces a random subtree of a copy of the tree with a new tree grown up to mutationDepth.
+// A mutant deeper than maxDepth is replaced by the original tree.
+func mutate(rng *rand.Rand, cfg Config, n *Node) *Node {
+	m := n.Clone()
+	m = m.replace(pickNode(rng, m), grow(rng, cfg, cfg.MutationDepth, false))
+	if m.Depth() > cfg.MaxDepth {
+		return n.Clone()
+	}
+	return m
+}
@@ -0,0 +1,44 @@
+package gp
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestOperators(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	cfg := Config{Functions: DefaultFunctions, ConstRange: 1, InitDepth: 5, MaxDepth: 8, MutationDepth: 4}
+
+	pop := rampedHalfAndHalf(rng, cfg, 40)
+	depths := make(map[int]int)
+	for i, tree := range pop {
+		d := tree.Depth()
+		depths[d]++
+		if d > cfg.InitDepth {
+			t.Log(fmt.Sprintf("tree %d has depth %d", i, d))
+			t.Fail()
+		}
+	}
+	for d := 2; d <= cfg.InitDepth; d++ {
+		if depths[d] == 0 {
+			t.Log(fmt.Sprintf("no tree of depth %d was created", d))
+			t.Fail()
+		}
+	}
+
+	for k := 0; k < 100; k++ {
+		p1, p2 := pop[rng.Intn(len(pop))], pop[rng.Intn(len(pop))]
+		s1, s2 := p1.String(), p2.String()
+		c1, c2 := crossover(rng, p1, p2, cfg.MaxDepth)
+		m := mutate(rng, cfg, c1)
+		if c1.Depth() > cfg.MaxDepth || c2.Depth() > cfg.MaxDepth || m.Depth() > cfg.MaxDepth {
+			t.Log(fmt.Sprintf("offspring exceeded the depth limit: %d, %d, %d", c1.Depth(), c2.Depth(), m.Depth()))
+			t.Fail()
+		}
+		if p1.String() != s1 || p2.String() != s2 {
+			t.Log("operators modified the parents")
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package gp
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/TheSlipper/isa/evolalg"
+)
+
+// maxError is the error given to the expressions that can't be evaluated on the samples.
+const maxError = 1e12
+
+// Config contains the configuration of the genetic programming.
+type Config struct {
+	Functions      []Function // function set (DefaultFunctions if nil)
+	ConstRange     float64    // ephemeral constants are drawn from <-ConstRange, ConstRange> (no constants if 0)
+	InitDepth      int        // maximal depth of ramped half-and-half (6 if 0)
+	MaxDepth       int        // depth limit of the offspring (17 if 0)
+	MutationDepth  int        // maximal depth of the subtrees grown by the mutation (4 if 0)
+	TournamentSize int        // (7 if 0)
+	CrossoverProb  float64    // probability of creating an offspring with the crossover (0.9 if 0)
+	MutationProb   float64    // probability of mutating an offspring (0.1 if 0)
+	Parsimony      bool       // lexicographic parsimony - the smaller tree wins tournaments between equal errors
+}
+
+// Generation is the history entry of a single generation. The grades of the embedded EpochData
+// are the negated mean squared errors of the trees so that the generations can be charted the
+// same way as the epochs of the other solvers.
+type Generation struct {
+	evolalg.EpochData
+	Best      string  `json:"best"`      // best expression found so far
+	BestError float64 `json:"bestError"` // mean squared error of the best expression
+	BestSize  int     `json:"bestSize"`  // amount of nodes of the best expression
+	MeanSize  float64 `json:"meanSize"`  // mean amount of nodes of the trees of the population
+
+	BestTree *Node `json:"-"`
+}
+
+// SymbolicRegressionSolver is a genetic programming solver that looks for an expression of x that
+// fits the samples with the lowest mean squared error.
+type SymbolicRegressionSolver struct {
+	samples     []Sample
+	cfg         Config
+	termination evolalg.Termination
+	rng         *rand.Rand
+	evals       int
+}
+
+// NewSymbolicRegressionSolver creates a new instance of a symbolic regression solver of the
+// samples.
+func NewSymbolicRegressionSolver(samples []Sample) (s SymbolicRegressionSolver, err error) {
+	if len(samples) == 0 {
+		err = errors.New("symbolic regression needs at least one sample")
+		return
+	}
+	s.samples = samples
+	err = s.SetConfig(Config{})
+	return
+}
+
+// SetConfig sets the configuration of the genetic programming.
+func (s *SymbolicRegressionSolver) SetConfig(cfg Config) error {
+	if cfg.Functions == nil {
+		cfg.Functions = DefaultFunctions
+	}
+	if cfg.InitDepth == 0 {
+		cfg.InitDepth = 6
+	}
+	if cfg.MaxDepth == 0 {
+		cfg.MaxDepth = 17
+	}
+	if cfg.MutationDepth == 0 {
+		cfg.MutationDepth = 4
+	}
+	if cfg.TournamentSize == 0 {
+		cfg.TournamentSize = 7
+	}
+	if cfg.CrossoverProb == 0 {
+		cfg.CrossoverProb = 0.9
+	}
+	if cfg.MutationProb == 0 {
+		cfg.MutationProb = 0.1
+	}
+	if len(cfg.Functions) == 0 {
+		return errors.New("function set is empty")
+	} else if cfg.InitDepth < 2 || cfg.MaxDepth < cfg.InitDepth {
+		return errors.New("initial depth has to be in <2, MaxDepth> set")
+	} else if cfg.TournamentSize < 1 {
+		return errors.New("tournament size has to be greater than zero")
+	} else if cfg.CrossoverProb < 0 || cfg.CrossoverProb > 1 || cfg.MutationProb < 0 || cfg.MutationProb > 1 {
+		return errors.New("probabilities have to be in <0, 1> set")
+	}
+	for _, fn := range cfg.Functions {
+		if fn.Arity != 1 && fn.Arity != 2 {
+			return errors.New("function " + fn.Name + " has an unsupported arity")
+		}
+	}
+	s.cfg = cfg
+	return nil
+}
+
+// SetSeed makes the solver use its own random number source seeded with the given seed so that the
+// runs can be repeated.
+func (s *SymbolicRegressionSolver) SetSeed(seed int64) {
+	s.rng = rand.New(rand.NewSource(seed))
+}
+
+// SetTermination sets the conditions that stop Solve before it runs for all of the epochs. The
+// target is compared with the negated mean squared error.
+func (s *SymbolicRegressionSolver) SetTermination(t evolalg.Termination) {
+	s.termination = t
+}
+
+// random returns the random number source of the solver. If none was seeded then a new one is
+// created based on the current time.
+func (s *SymbolicRegressionSolver) random() *rand.Rand {
+	if s.rng == nil {
+		s.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return s.rng
+}
+
+// Error returns the mean squared error of the expression on the samples.
+func (s *SymbolicRegressionSolver) Error(n *Node) float64 {
+	s.evals++
+	sum := 0.0
+	for _, smp := range s.samples {
+		d := n.Eval(smp.X) - smp.Y
+		sum += d * d
+	}
+	mse := sum / float64(len(s.samples))
+	if math.IsNaN(mse) || mse > maxError {
+		return maxError
+	}
+	return mse
+}
+
+// Solve runs the genetic programming for N trees for a given amount of generations and returns a
+// history of the algorithm's execution. The best tree is always copied to the next generation.
+func (s *SymbolicRegressionSolver) Solve(N, epochs int) (hist []Generation, err error) {
+	if N < 2 {
+		err = errors.New("genetic programming needs a population of at least 2 trees")
+		return
+	}
+
+	s.evals = 0
+	pop := rampedHalfAndHalf(s.random(), s.cfg, N)
+	errs := make([]float64, N)
+	for i := range pop {
+		errs[i] = s.Error(pop[i])
+	}
+	var best *Node
+	bestErr, bestEpoch := math.Inf(1), 0
+	updateBest := func(epoch int) {
+		for i := range pop {
+			if s.better(pop[i], errs[i], best, bestErr) {
+				best, bestErr, bestEpoch = pop[i].Clone(), errs[i], epoch
+			}
+		}
+	}
+	updateBest(0)
+
+	hist = make([]Generation, 0, epochs+1)
+	hist = append(hist, s.generation(pop, errs, best, bestErr, bestEpoch))
+
+	for g := 1; g < epochs+1; g++ {
+		next, nextErrs := []*Node{best.Clone()}, []float64{bestErr}
+		for len(next) < N {
+			p1 := pop[s.tournament(pop, errs)]
+			var children []*Node
+			if s.random().Float64() < s.cfg.CrossoverProb {
+				c1, c2 := crossover(s.random(), p1, pop[s.tournament(pop, errs)], s.cfg.MaxDepth)
+				children = []*Node{c1, c2}
+			} else {
+				children = []*Node{p1.Clone()}
+			}
+			for _, c := range children {
+				if len(next) == N {
+					break
+				}
+				if s.random().Float64() < s.cfg.MutationProb {
+					c = mutate(s.random(), s.cfg, c)
+				}
+				next = append(next, c)
+				nextErrs = append(nextErrs, s.Error(c))
+			}
+		}
+		pop, errs = next, nextErrs
+		updateBest(g)
+		hist = append(hist, s.generation(pop, errs, best, bestErr, bestEpoch))
+
+		// Stop early if any of the termination conditions is met
+		if s.termination.Done(g, s.evals, -bestErr, bestEpoch) {
+			break
+		}
+	}
+
+	return
+}
+
+// better checks if the tree a with the error errA is better than the tree b with the error errB.
+// With parsimony the smaller tree is better if the errors are equal.
+func (s *SymbolicRegressionSolver) better(a *Node, errA float64, b *Node, errB float64) bool {
+	if b == nil || errA < errB {
+		return true
+	}
+	return s.cfg.Parsimony && errA == errB && a.Size() < b.Size()
+}
+
+// tournament returns the index of the winner of a tournament between TournamentSize random trees.
+func (s *SymbolicRegressionSolver) tournament(pop []*Node, errs []float64) int {
+	winner := s.random().Intn(len(pop))
+	for k := 1; k < s.cfg.TournamentSize; k++ {
+		i := s.random().Intn(len(pop))
+		if s.better(pop[i], errs[i], pop[winner], errs[winner]) {
+			winner = i
+		}
+	}
+	return winner
+}
+
+// generation creates the history entry of the population.
+func (s *SymbolicRegressionSolver) generation(pop []*Node, errs []float64, best *Node, bestErr float64, bestEpoch int) Generation {
+	N := len(pop)
+	g := Generation{Best: best.String(), BestError: bestErr, BestSize: best.Size(), BestTree: best}
+	ed := &g.EpochData
+	ed.Grades, ed.Fits = make([]float64, N), make([]float64, N)
+	ed.FMin, ed.FMax = math.Inf(1), math.Inf(-1)
+	unique := make(map[string]struct{}, N)
+	for i := range pop {
+		ed.Grades[i] = -errs[i]
+		ed.Fits[i] = -errs[i]
+		ed.FMin = math.Min(ed.FMin, -errs[i])
+		ed.FMax = math.Max(ed.FMax, -errs[i])
+		ed.FAVG += -errs[i] / float64(N)
+		g.MeanSize += float64(pop[i].Size()) / float64(N)
+		unique[pop[i].String()] = struct{}{}
+	}
+	ed.EliteFit, ed.EliteGrade, ed.EliteEpoch = -bestErr, -bestErr, bestEpoch
+	ed.Evaluations = s.evals
+	ed.Diversity.UniqueGenotypes = len(unique)
+
+	ed.Diversity.FitPercentiles = evolalg.MeasurePercentiles(ed.Fits)
+	return g
+}
@@ -0,0 +1,57 @@
+package gp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TheSlipper/isa/evolalg"
+)
+
+func TestSymbolicRegression(t *testing.T) {
+	var samples []Sample
+	for x := -2.0; x <= 2; x += 0.25 {
+		samples = append(samples, Sample{X: x, Y: x*x*x + x})
+	}
+
+	for _, parsimony := range []bool{false, true} {
+		s, err := NewSymbolicRegressionSolver(samples)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.SetSeed(2)
+		err = s.SetConfig(Config{Parsimony: parsimony})
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.SetTermination(evolalg.Termination{Target: -1e-9, HasTarget: true})
+		hist, err := s.Solve(200, 50)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		last := hist[len(hist)-1]
+		if last.BestError > 1e-9 {
+			t.Log(fmt.Sprintf("parsimony %t: best expression %s has error %f", parsimony, last.Best, last.BestError))
+			t.Fail()
+		}
+		if len(hist) == 51 {
+			t.Log(fmt.Sprintf("parsimony %t: run didn't stop on the target", parsimony))
+			t.Fail()
+		}
+		for i := 1; i < len(hist); i++ {
+			if hist[i].BestError > hist[i-1].BestError {
+				t.Log(fmt.Sprintf("parsimony %t: best error grew in generation %d", parsimony, i))
+				t.Fail()
+			}
+		}
+		if v := last.BestTree.Eval(1.5); v < 4.874 || v > 4.876 {
+			t.Log(fmt.Sprintf("parsimony %t: best expression %s evaluated to %f for x = 1.5", parsimony, last.Best, v))
+			t.Fail()
+		}
+	}
+
+	if _, err := NewSymbolicRegressionSolver(nil); err == nil {
+		t.Log("solver accepted no samples")
+		t.Fail()
+	}
+}
@@ -0,0 +1,172 @@
+// Package gp implements tree-based genetic programming for symbolic regression.
+package gp
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Function is a primitive of the function set.
+type Function struct {
+	Name  string
+	Arity int // 1 or 2
+	Apply func(args []float64) float64
+	Infix bool // print as "(a Name b)" instead of "Name(a, b)"
+}
+
+// DefaultFunctions is the function set used if none was configured: +, -, * and protected
+// division.
+var DefaultFunctions = []Function{Add, Sub, Mul, Div}
+
+// Primitives of the function set. Div, Log and Exp are protected so that they are defined for
+// every argument.
+var (
+	Add = Function{Name: "+", Arity: 2, Infix: true, Apply: func(a []float64) float64 { return a[0] + a[1] }}
+	Sub = Function{Name: "-", Arity: 2, Infix: true, Apply: func(a []float64) float64 { return a[0] - a[1] }}
+	Mul = Function{Name: "*", Arity: 2, Infix: true, Apply: func(a []float64) float64 { return a[0] * a[1] }}
+	Div = Function{Name: "/", Arity: 2, Infix: true, Apply: func(a []float64) float64 {
+		if math.Abs(a[1]) < 1e-9 {
+			return 1
+		}
+		return a[0] / a[1]
+	}}
+	Sin = Function{Name: "sin", Arity: 1, Apply: func(a []float64) float64 { return math.Sin(a[0]) }}
+	Cos = Function{Name: "cos", Arity: 1, Apply: func(a []float64) float64 { return math.Cos(a[0]) }}
+	Exp = Function{Name: "exp", Arity: 1, Apply: func(a []float64) float64 { return math.Exp(math.Min(a[0], 50)) }}
+	Log = Function{Name: "log", Arity: 1, Apply: func(a []float64) float64 {
+		if a[0] == 0 {
+			return 0
+		}
+		return math.Log(math.Abs(a[0]))
+	}}
+)
+
+// FunctionsByName returns the function set based on a comma separated list of the names of the
+// primitives: "add", "sub", "mul", "div", "sin", "cos", "exp" and "log". An empty list returns
+// DefaultFunctions.
+func FunctionsByName(names string) ([]Function, error) {
+	if names == "" {
+		return DefaultFunctions, nil
+	}
+	var fns []Function
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "add":
+			fns = append(fns, Add)
+		case "sub":
+			fns = append(fns, Sub)
+		case "mul":
+			fns = append(fns, Mul)
+		case "div":
+			fns = append(fns, Div)
+		case "sin":
+			fns = append(fns, Sin)
+		case "cos":
+			fns = append(fns, Cos)
+		case "exp":
+			fns = append(fns, Exp)
+		case "log":
+			fns = append(fns, Log)
+		default:
+			return nil, errors.New("unknown function " + name)
+		}
+	}
+	return fns, nil
+}
+
+// Node is a node of an expression tree. Nodes without a function are terminals - either the
+// variable x or a constant.
+type Node struct {
+	Fn       *Function
+	Children []*Node
+	Var      bool    // terminal is the variable x
+	Value    float64 // value of a constant terminal
+}
+
+// Eval evaluates the expression for the given x.
+func (n *Node) Eval(x float64) float64 {
+	if n.Fn == nil {
+		if n.Var {
+			return x
+		}
+		return n.Value
+	}
+	args := make([]float64, len(n.Children))
+	for i, c := range n.Children {
+		args[i] = c.Eval(x)
+	}
+	return n.Fn.Apply(args)
+}
+
+// String returns the expression in infix notation.
+func (n *Node) String() string {
+	if n.Fn == nil {
+		if n.Var {
+			return "x"
+		}
+		return strconv.FormatFloat(n.Value, 'g', 4, 64)
+	}
+	if n.Fn.Infix {
+		return "(" + n.Children[0].String() + " " + n.Fn.Name + " " + n.Children[1].String() + ")"
+	}
+	args := make([]string, len(n.Children))
+	for i, c := range n.Children {
+		args[i] = c.String()
+	}
+	return n.Fn.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// Size returns the amount of nodes of the tree.
+func (n *Node) Size() int {
+	size := 1
+	for _, c := range n.Children {
+		size += c.Size()
+	}
+	return size
+}
+
+// Depth returns the depth of the tree. A single terminal has depth 0.
+func (n *Node) Depth() int {
+	depth := 0
+	for _, c := range n.Children {
+		if d := c.Depth() + 1; d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+// Clone returns a deep copy of the tree.
+func (n *Node) Clone() *Node {
+	clone := &Node{Fn: n.Fn, Var: n.Var, Value: n.Value}
+	if n.Children != nil {
+		clone.Children = make([]*Node, len(n.Children))
+		for i, c := range n.Children {
+			clone.Children[i] = c.Clone()
+		}
+	}
+	return clone
+}
+
+// nodes returns all of the nodes of the tree in pre-order.
+func (n *Node) nodes() []*Node {
+	res := []*Node{n}
+	for _, c := range n.Children {
+		res = append(res, c.nodes()...)
+	}
+	return res
+}
+
+// replace returns the tree with the node old replaced by the tree sub. The tree is modified in
+// place unless old is the root.
+func (n *Node) replace(old, sub *Node) *Node {
+	if n == old {
+		return sub
+	}
+	for i, c := range n.Children {
+		n.Children[i] = c.replace(old, sub)
+	}
+	return n
+}
@@ -0,0 +1,48 @@
+package gp
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	// (x * x) + sin(2)
+	tree := &Node{Fn: &Add, Children: []*Node{
+		{Fn: &Mul, Children: []*Node{{Var: true}, {Var: true}}},
+		{Fn: &Sin, Children: []*Node{{Value: 2}}},
+	}}
+	if s := tree.String(); s != "((x * x) + sin(2))" {
+		t.Log(fmt.Sprintf("expression printed as %s", s))
+		t.Fail()
+	}
+	if v := tree.Eval(3); v < 9.909 || v > 9.910 {
+		t.Log(fmt.Sprintf("expression evaluated to %f for x = 3", v))
+		t.Fail()
+	}
+	if tree.Size() != 6 || tree.Depth() != 2 {
+		t.Log(fmt.Sprintf("size %d and depth %d instead of 6 and 2", tree.Size(), tree.Depth()))
+		t.Fail()
+	}
+
+	clone := tree.Clone()
+	clone.Children[0].Children[0].Value, clone.Children[0].Children[0].Var = 5, false
+	if tree.String() == clone.String() {
+		t.Log("modifying the clone modified the original tree")
+		t.Fail()
+	}
+
+	if v := (&Node{Fn: &Div, Children: []*Node{{Value: 1}, {Value: 0}}}).Eval(0); v != 1 {
+		t.Log(fmt.Sprintf("protected division by zero returned %f", v))
+		t.Fail()
+	}
+
+	fns, err := FunctionsByName("add, mul,log")
+	if err != nil || len(fns) != 3 || fns[2].Name != "log" {
+		t.Log(fmt.Sprintf("wrong function set parsed: %v", err))
+		t.Fail()
+	}
+	if _, err := FunctionsByName("add,pow"); err == nil {
+		t.Log("unknown function was accepted")
+		t.Fail()
+	}
+}
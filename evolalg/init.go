@@ -0,0 +1,146 @@
+package evolalg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Initializer creates the initial population of N values from the <a, b> set of the solver.
+type Initializer func(gas *GeneticAlgorithmSolver, N int) ([]float64, error)
+
+// UniformRealInit draws every individual uniformly from the <a, b> set and rounds it to the
+// solver's accuracy. This is the default initializer.
+func UniformRealInit(gas *GeneticAlgorithmSolver, N int) ([]float64, error) {
+	vals := make([]float64, N)
+	for i := 0; i < N; i++ {
+		vals[i] = gas.round(gas.a + gas.random().Float64()*(gas.b-gas.a))
+	}
+	return vals, nil
+}
+
+// UniformBinaryInit draws every bit of every individual with equal probability and decodes the
+// resulting bitstrings.
+func UniformBinaryInit(gas *GeneticAlgorithmSolver, N int) ([]float64, error) {
+	vals := make([]float64, N)
+	bits := make([]byte, gas.l)
+	for i := 0; i < N; i++ {
+		for j := 0; j < gas.l; j++ {
+			bits[j] = byte(gas.random().Intn(2))
+		}
+		vals[i] = gas.XIntToXReal(gas.XBinToXInt(bits))
+	}
+	return vals, nil
+}
+
+// LatinHypercubeInit splits the <a, b> set into N strata of equal width and draws exactly one
+// individual from each of them. The individuals are shuffled so that the strata aren't paired in
+// order during crossover.
+func LatinHypercubeInit(gas *GeneticAlgorithmSolver, N int) ([]float64, error) {
+	vals := make([]float64, N)
+	width := (gas.b - gas.a) / float64(N)
+	for i := 0; i < N; i++ {
+		vals[i] = gas.round(gas.a + (float64(i)+gas.random().Float64())*width)
+	}
+	gas.random().Shuffle(N, func(i, j int) {
+		vals[i], vals[j] = vals[j], vals[i]
+	})
+	return vals, nil
+}
+
+// OppositionInit draws N individuals uniformly, creates their opposites (a + b - x) and keeps the
+// N best graded ones out of both of the sets.
+func OppositionInit(gas *GeneticAlgorithmSolver, N int) ([]float64, error) {
+	vals, err := UniformRealInit(gas, N)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < N; i++ {
+		vals = append(vals, gas.round(gas.a+gas.b-vals[i]))
+	}
+
+	grades := make([]float64, len(vals))
+	for i := range vals {
+		grades[i] = gas.Grade(vals[i])
+	}
+	idx := make([]int, len(vals))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return grades[idx[i]] > grades[idx[j]]
+	})
+
+	best := make([]float64, N)
+	for i := 0; i < N; i++ {
+		best[i] = vals[idx[i]]
+	}
+	return best, nil
+}
+
+// GridInit places the individuals evenly on the <a, b> set including both of its bounds.
+func GridInit(gas *GeneticAlgorithmSolver, N int) ([]float64, error) {
+	vals := make([]float64, N)
+	if N == 1 {
+		vals[0] = gas.round((gas.a + gas.b) / 2)
+		return vals, nil
+	}
+	for i := 0; i < N; i++ {
+		vals[i] = gas.round(gas.a + float64(i)*(gas.b-gas.a)/float64(N-1))
+	}
+	return vals, nil
+}
+
+// WithSeeds returns an initializer that puts the given seed individuals (e.g. known good solutions)
+// at the beginning of the population and fills the rest of it with the base initializer. If there
+// are more seeds than N then only the first N of them are used.
+func WithSeeds(base Initializer, seeds ...float64) Initializer {
+	return func(gas *GeneticAlgorithmSolver, N int) ([]float64, error) {
+		for _, seed := range seeds {
+			if seed < gas.a || seed > gas.b {
+				return nil, fmt.Errorf("seed individual %f is not contained in <a,b> set", seed)
+			}
+		}
+		if len(seeds) >= N {
+			vals := make([]float64, N)
+			for i := 0; i < N; i++ {
+				vals[i] = gas.round(seeds[i])
+			}
+			return vals, nil
+		}
+
+		rest, err := base(gas, N-len(seeds))
+		if err != nil {
+			return nil, err
+		}
+		vals := make([]float64, 0, N)
+		for _, seed := range seeds {
+			vals = append(vals, gas.round(seed))
+		}
+		return append(vals, rest...), nil
+	}
+}
+
+// InitializerByName returns one of the built-in initializers based on its name: "uniform",
+// "binary", "lhs", "opposition" or "grid". An empty name returns the default one.
+func InitializerByName(name string) (Initializer, error) {
+	switch name {
+	case "", "uniform":
+		return UniformRealInit, nil
+	case "binary":
+		return UniformBinaryInit, nil
+	case "lhs":
+		return LatinHypercubeInit, nil
+	case "opposition":
+		return OppositionInit, nil
+	case "grid":
+		return GridInit, nil
+	}
+	return nil, errors.New("unknown initializer " + name)
+}
+
+// round rounds x to the accuracy of the solver.
+func (gas *GeneticAlgorithmSolver) round(x float64) float64 {
+	return math.Round(x*math.Pow10(int(gas.d))) / math.Pow10(int(gas.d))
+}
@@ -0,0 +1,79 @@
+package evolalg
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestInitializers(t *testing.T) {
+	gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, func(x float64) float64 {
+		return math.Mod(x, 1*(math.Cos(20*math.Pi*x)-math.Sin(x)))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gas.SetSeed(1)
+
+	N := 16
+	for _, name := range []string{"uniform", "binary", "lhs", "opposition", "grid"} {
+		init, err := InitializerByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vals, err := init(&gas, N)
+		if err != nil {
+			t.Log(err.Error())
+			t.Fail()
+			continue
+		}
+		if len(vals) != N {
+			t.Log(fmt.Sprintf("%s initializer created %d individuals instead of %d", name, len(vals), N))
+			t.Fail()
+		}
+		for _, v := range vals {
+			if v < -4 || v > 12 {
+				t.Log(fmt.Sprintf("%s initializer created %f which is out of bounds", name, v))
+				t.Fail()
+			}
+		}
+	}
+
+	// Latin hypercube has to hit every stratum exactly once
+	vals, _ := LatinHypercubeInit(&gas, N)
+	strata := make([]int, N)
+	for _, v := range vals {
+		s := int((v + 4) / 16 * float64(N))
+		if s == N {
+			s--
+		}
+		strata[s]++
+	}
+	for i, cnt := range strata {
+		if cnt != 1 {
+			t.Log(fmt.Sprintf("stratum %d has %d individuals", i, cnt))
+			t.Fail()
+		}
+	}
+
+	// Seeds have to be kept
+	vals, err = WithSeeds(UniformRealInit, 1.5, 2.25)(&gas, N)
+	if err != nil || vals[0] != 1.5 || vals[1] != 2.25 || len(vals) != N {
+		t.Log(fmt.Sprintf("seed individuals were not kept - %v", vals))
+		t.Fail()
+	}
+	if _, err = WithSeeds(UniformRealInit, 20)(&gas, N); err == nil {
+		t.Log("seed out of bounds was accepted")
+		t.Fail()
+	}
+
+	// The grades made by the initializer are counted in the evaluations of the run
+	gas.SetInitializer(OppositionInit)
+	ed, err := gas.Start(N, 5, 0.75, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	} else if ed.Evaluations != 3*N {
+		t.Log(fmt.Sprintf("opposition initializer started with %d evaluations", ed.Evaluations))
+		t.Fail()
+	}
+}
@@ -0,0 +1,92 @@
+package evolalg
+
+import "math"
+
+// identity returns the n×n identity matrix.
+func identity(n int) [][]float64 {
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+		m[i][i] = 1
+	}
+	return m
+}
+
+// matVec returns the product of the matrix m and the vector v.
+func matVec(m [][]float64, v []float64) []float64 {
+	res := make([]float64, len(m))
+	for i := range m {
+		for j := range v {
+			res[i] += m[i][j] * v[j]
+		}
+	}
+	return res
+}
+
+// norm returns the euclidean norm of the vector v.
+func norm(v []float64) float64 {
+	sum := 0.0
+	for _, x := range v {
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
+
+// symmetricEigen returns the eigenvalues of the symmetric matrix a and the matrix whose columns are
+// the corresponding eigenvectors. The cyclic Jacobi method is used which is precise and simple
+// enough for the small matrices of the solvers. The passed matrix is not modified.
+func symmetricEigen(a [][]float64) (vals []float64, vecs [][]float64) {
+	n := len(a)
+	m := make([][]float64, n)
+	for i := range a {
+		m[i] = append([]float64(nil), a[i]...)
+	}
+	vecs = identity(n)
+
+	for sweep := 0; sweep < 100; sweep++ {
+		off := 0.0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				off += m[i][j] * m[i][j]
+			}
+		}
+		if off < 1e-30 {
+			break
+		}
+
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if m[p][q] == 0 {
+					continue
+				}
+
+				// Rotate the p and q rows and columns so that m[p][q] becomes zero
+				theta := (m[q][q] - m[p][p]) / (2 * m[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p], m[k][q] = c*mkp-s*mkq, s*mkp+c*mkq
+				}
+				for k := 0; k < n; k++ {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k], m[q][k] = c*mpk-s*mqk, s*mpk+c*mqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := vecs[k][p], vecs[k][q]
+					vecs[k][p], vecs[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+
+	vals = make([]float64, n)
+	for i := range vals {
+		vals[i] = m[i][i]
+	}
+	return
+}
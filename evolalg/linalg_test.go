@@ -0,0 +1,43 @@
+package evolalg
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestSymmetricEigen(t *testing.T) {
+	a := [][]float64{
+		{4, 1, 2},
+		{1, 3, 0},
+		{2, 0, 5},
+	}
+	vals, vecs := symmetricEigen(a)
+
+	// Every column has to satisfy A v = λ v and have a unit length
+	for k := range vals {
+		v := []float64{vecs[0][k], vecs[1][k], vecs[2][k]}
+		av := matVec(a, v)
+		for i := range av {
+			if math.Abs(av[i]-vals[k]*v[i]) > 1e-9 {
+				t.Log(fmt.Sprintf("eigenpair %d is wrong: A v = %v, λ v = %v", k, av, vals[k]))
+				t.Fail()
+				break
+			}
+		}
+		if math.Abs(norm(v)-1) > 1e-9 {
+			t.Log(fmt.Sprintf("eigenvector %d has length %f", k, norm(v)))
+			t.Fail()
+		}
+	}
+
+	// The trace is equal to the sum of the eigenvalues
+	if sum := vals[0] + vals[1] + vals[2]; math.Abs(sum-12) > 1e-9 {
+		t.Log(fmt.Sprintf("sum of the eigenvalues is %f instead of 12", sum))
+		t.Fail()
+	}
+	if a[0][1] != 1 {
+		t.Log("decomposition modified the passed matrix")
+		t.Fail()
+	}
+}
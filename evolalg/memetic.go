@@ -0,0 +1,187 @@
+package evolalg
+
+import (
+	"errors"
+	"math"
+)
+
+// LocalSearchMethod describes the local search used for refining the individuals.
+type LocalSearchMethod int
+
+const (
+	// NoLocalSearch disables the local search. This is the default.
+	NoLocalSearch LocalSearchMethod = iota
+	// BitFlipHillClimbing flips the bits of the genotype in a random order and accepts the first
+	// flip that improves the grade.
+	BitFlipHillClimbing
+	// SteepestAscent moves to the better of the two neighbouring discretized values (x ± 10^-d)
+	// for as long as it improves the grade.
+	SteepestAscent
+	// GoldenSection runs the golden-section search on the <x - Radius, x + Radius> interval.
+	GoldenSection
+)
+
+// WriteBack describes what happens to an individual improved by the local search.
+type WriteBack int
+
+const (
+	// Lamarckian replaces the individual with the improved one.
+	Lamarckian WriteBack = iota
+	// Baldwinian keeps the genotype of the individual but gives it the improved grade.
+	Baldwinian
+)
+
+// LocalSearch contains the configuration of the local search of a memetic algorithm.
+type LocalSearch struct {
+	Method    LocalSearchMethod
+	WriteBack WriteBack
+	Fraction  float64 // fraction of the population refined in every epoch (only the best individual if 0)
+	MaxSteps  int     // limit of the steps of a single search (10 if 0)
+	Radius    float64 // half-width of the golden-section interval ((b-a)/100 if 0)
+}
+
+// LocalSearchByName returns the local search method based on its name: "none", "bitflip",
+// "steepest" or "golden". An empty name returns NoLocalSearch.
+func LocalSearchByName(name string) (LocalSearchMethod, error) {
+	switch name {
+	case "", "none":
+		return NoLocalSearch, nil
+	case "bitflip":
+		return BitFlipHillClimbing, nil
+	case "steepest":
+		return SteepestAscent, nil
+	case "golden":
+		return GoldenSection, nil
+	}
+	return NoLocalSearch, errors.New("unknown local search method " + name)
+}
+
+// SetLocalSearch sets the local search applied to the population after every epoch of Solve.
+func (gas *GeneticAlgorithmSolver) SetLocalSearch(ls LocalSearch) error {
+	if ls.Fraction < 0 || ls.Fraction > 1 {
+		return errors.New("fraction of the refined population has to be in <0, 1> set")
+	}
+	gas.localSearch = ls
+	return nil
+}
+
+// refine runs the local search on the chosen individuals of the population and writes the results
+// back according to the configuration.
+func (gas *GeneticAlgorithmSolver) refine() {
+	before := gas.evals
+	N := len(gas.popVals)
+
+	var targets []int
+	if gas.localSearch.Fraction <= 0 {
+		targets = gas.bestIndices(1)
+	} else {
+		n := int(math.Round(gas.localSearch.Fraction * float64(N)))
+		targets = gas.random().Perm(N)[:n]
+	}
+
+	for _, i := range targets {
+		x, grade := gas.searchFrom(gas.popArr[i], gas.popVals[i], gas.gradeCache[i])
+		if grade <= gas.gradeCache[i] {
+			continue
+		}
+
+		fit := gas.fitFromGrade(grade)
+		genome := gas.XIntToXBin(uint32(gas.XRealToXInt(x)))
+		id := gas.born(Individual{Parents: []int{gas.id(i)}, Operator: OpLocalSearch, CutPoint: -1}, genome, x, grade)
+		if fit > gas.eliteFit {
+			gas.elite, gas.eliteFit, gas.eliteGrade, gas.eliteEpoch = x, fit, grade, gas.epoch
+			if gas.genealogy != nil {
+				gas.genealogy.EliteID = id
+			}
+		}
+		if gas.localSearch.WriteBack == Lamarckian {
+			gas.popArr[i] = genome
+			gas.popVals[i] = x
+			if gas.popID != nil {
+				gas.popID[i] = id
+			}
+		}
+		gas.gradeCache[i], gas.fitCache[i] = grade, fit
+	}
+	gas.roulette(gas.popVals)
+
+	gas.lsEvals += gas.evals - before
+}
+
+// searchFrom runs the local search starting from the given individual and returns the best point
+// that was found.
+func (gas *GeneticAlgorithmSolver) searchFrom(genome []byte, x, grade float64) (float64, float64) {
+	maxSteps := gas.localSearch.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = 10
+	}
+
+	switch gas.localSearch.Method {
+	case BitFlipHillClimbing:
+		cur := append([]byte(nil), genome...)
+		for step := 0; step < maxSteps; step++ {
+			improved := false
+			for _, k := range gas.random().Perm(len(cur)) {
+				cur[k] ^= 1
+				nx := gas.XIntToXReal(gas.XBinToXInt(cur))
+				if ng := gas.Grade(nx); ng > grade {
+					x, grade, improved = nx, ng, true
+					break
+				}
+				cur[k] ^= 1
+			}
+			if !improved {
+				break
+			}
+		}
+	case SteepestAscent:
+		h := math.Pow10(-int(gas.d))
+		for step := 0; step < maxSteps; step++ {
+			bestX, bestGrade := x, grade
+			for _, nx := range []float64{gas.round(x - h), gas.round(x + h)} {
+				if nx < gas.a || nx > gas.b {
+					continue
+				}
+				if ng := gas.Grade(nx); ng > bestGrade {
+					bestX, bestGrade = nx, ng
+				}
+			}
+			if bestX == x {
+				break
+			}
+			x, grade = bestX, bestGrade
+		}
+	case GoldenSection:
+		r := gas.localSearch.Radius
+		if r <= 0 {
+			r = (gas.b - gas.a) / 100
+		}
+		lo, hi := math.Max(gas.a, x-r), math.Min(gas.b, x+r)
+		invPhi := (math.Sqrt(5) - 1) / 2
+		c, d := hi-invPhi*(hi-lo), lo+invPhi*(hi-lo)
+		fc, fd := gas.Grade(c), gas.Grade(d)
+		for step := 0; step < maxSteps; step++ {
+			if fc > fd {
+				hi, d, fd = d, c, fc
+				c = hi - invPhi*(hi-lo)
+				fc = gas.Grade(c)
+			} else {
+				lo, c, fc = c, d, fd
+				d = lo + invPhi*(hi-lo)
+				fd = gas.Grade(d)
+			}
+		}
+
+		// The result has to be representable with the solver's accuracy
+		best := d
+		if fc > fd {
+			best = c
+		}
+		best = gas.round(best)
+		if bg := gas.Grade(best); bg > grade {
+			x, grade = best, bg
+		}
+	}
+
+	return x, grade
+}
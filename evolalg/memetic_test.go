@@ -0,0 +1,58 @@
+package evolalg
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestLocalSearch(t *testing.T) {
+	for _, name := range []string{"bitflip", "steepest", "golden"} {
+		method, err := LocalSearchByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, wb := range []WriteBack{Lamarckian, Baldwinian} {
+			gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, func(x float64) float64 {
+				return math.Mod(x, 1*(math.Cos(20*math.Pi*x)-math.Sin(x)))
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			gas.SetSeed(11)
+			err = gas.SetLocalSearch(LocalSearch{Method: method, WriteBack: wb, Fraction: 0.5})
+			if err != nil {
+				t.Fatal(err)
+			}
+			hist, err := gas.Solve(10, 10, 0.75, 0.005)
+			if err != nil {
+				t.Log(fmt.Sprintf("%s: %s", name, err.Error()))
+				t.Fail()
+				continue
+			}
+
+			last := hist[len(hist)-1]
+			if last.LSEvaluations == 0 || last.LSEvaluations >= last.Evaluations {
+				t.Log(fmt.Sprintf("%s: incorrect amount of evaluations - %d of %d made by the local search", name, last.LSEvaluations, last.Evaluations))
+				t.Fail()
+			}
+			for i := 1; i < len(hist); i++ {
+				if hist[i].Evaluations < hist[i-1].Evaluations {
+					t.Log(fmt.Sprintf("%s: evaluations decreased in epoch %d", name, i))
+					t.Fail()
+				}
+			}
+		}
+	}
+
+	// Steepest ascent on a parabola has to climb to the top from the start
+	gas, _ := NewGeneticAlgorithmSolver(0, 10, 2, func(x float64) float64 {
+		return -(x - 5) * (x - 5)
+	})
+	gas.SetLocalSearch(LocalSearch{Method: SteepestAscent, MaxSteps: 1000})
+	x, grade := gas.searchFrom(nil, 4.5, gas.Grade(4.5))
+	if math.Abs(x-5) > 1e-9 || grade != 0 {
+		t.Log(fmt.Sprintf("steepest ascent stopped at %f", x))
+		t.Fail()
+	}
+}
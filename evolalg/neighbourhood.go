@@ -0,0 +1,87 @@
+package evolalg
+
+import (
+	"errors"
+	"math"
+)
+
+// Move creates a neighbour of the genome in the encoding of the solver. The passed genome is not
+// modified.
+type Move func(gas *GeneticAlgorithmSolver, genome []byte) []byte
+
+// BitFlipMove flips a single random bit of the genome.
+func BitFlipMove(gas *GeneticAlgorithmSolver, genome []byte) []byte {
+	return MultiBitFlipMove(1)(gas, genome)
+}
+
+// MultiBitFlipMove returns a move that flips k distinct random bits of the genome.
+func MultiBitFlipMove(k int) Move {
+	return func(gas *GeneticAlgorithmSolver, genome []byte) []byte {
+		neighbour := append([]byte(nil), genome...)
+		for _, j := range gas.random().Perm(len(genome))[:k] {
+			neighbour[j] ^= 1
+		}
+		return neighbour
+	}
+}
+
+// StepMove adds or subtracts one from the genome in its integer form which moves x to one of the
+// neighbouring representable values.
+func StepMove(gas *GeneticAlgorithmSolver, genome []byte) []byte {
+	xInt, maxInt := gas.XBinToXInt(genome), int(math.Pow(2, float64(gas.l)))-1
+	if xInt == 0 || (xInt < maxInt && gas.random().Intn(2) == 0) {
+		xInt++
+	} else {
+		xInt--
+	}
+	return gas.XIntToXBin(uint32(xInt))
+}
+
+// MoveByName returns the neighbourhood move based on its name: "bitflip", "twobit" or "step". An
+// empty name returns BitFlipMove.
+func MoveByName(name string) (Move, error) {
+	switch name {
+	case "", "bitflip":
+		return BitFlipMove, nil
+	case "twobit":
+		return MultiBitFlipMove(2), nil
+	case "step":
+		return StepMove, nil
+	}
+	return nil, errors.New("unknown neighbourhood move " + name)
+}
+
+// startSingle resets the state of the solver for a single-solution search and places a random
+// solution in it. The solution is stored as a population of one individual so that the history
+// has the same format as the one of the genetic algorithm.
+func (gas *GeneticAlgorithmSolver) startSingle() error {
+	vals, err := UniformRealInit(gas, 1)
+	if err != nil {
+		return err
+	}
+	gas.evals, gas.lsEvals = 0, 0
+	gas.epoch, gas.eliteEpoch = 0, 0
+	gas.trace = nil
+	gas.resetReference()
+	gas.resetNoise()
+	err = gas.resetSurrogate()
+	if err != nil {
+		return err
+	}
+	gas.failure, gas.failed = nil, 0
+	gas.popArr = [][]byte{gas.XIntToXBin(uint32(gas.XRealToXInt(vals[0])))}
+	gas.popVals, gas.popAge = []float64{vals[0]}, []int{0}
+	grade := gas.Grade(vals[0])
+	gas.gradeCache, gas.fitCache = []float64{grade}, []float64{gas.fitFromGrade(grade)}
+	gas.elite, gas.eliteFit, gas.eliteGrade = vals[0], gas.fitCache[0], grade
+	return gas.takeFailure()
+}
+
+// moveSingle replaces the solution of a single-solution search and updates the elite.
+func (gas *GeneticAlgorithmSolver) moveSingle(genome []byte, x, grade float64) {
+	gas.popArr[0], gas.popVals[0], gas.popAge[0] = genome, x, gas.epoch
+	gas.gradeCache[0], gas.fitCache[0] = grade, gas.fitFromGrade(grade)
+	if gas.fitCache[0] > gas.eliteFit {
+		gas.elite, gas.eliteFit, gas.eliteGrade, gas.eliteEpoch = x, gas.fitCache[0], grade, gas.epoch
+	}
+}
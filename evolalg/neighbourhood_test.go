@@ -0,0 +1,53 @@
+package evolalg
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMoves(t *testing.T) {
+	gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, func(x float64) float64 { return x })
+	if err != nil {
+		t.Fatal(err)
+	}
+	gas.SetSeed(2)
+	genome := gas.XIntToXBin(1000)
+
+	for _, tc := range []struct {
+		name  string
+		flips int
+	}{{"bitflip", 1}, {"twobit", 2}} {
+		move, err := MoveByName(tc.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		neighbour := move(&gas, genome)
+		diff := 0
+		for j := range genome {
+			if genome[j] != neighbour[j] {
+				diff++
+			}
+		}
+		if diff != tc.flips {
+			t.Log(fmt.Sprintf("%s flipped %d bits instead of %d", tc.name, diff, tc.flips))
+			t.Fail()
+		}
+	}
+
+	for _, start := range []int{0, 1000, 1<<uint(gas.L()) - 1} {
+		step := gas.XBinToXInt(StepMove(&gas, gas.XIntToXBin(uint32(start)))) - start
+		if step != 1 && step != -1 {
+			t.Log(fmt.Sprintf("step move changed %d by %d", start, step))
+			t.Fail()
+		}
+		if start+step < 0 || start+step > 1<<uint(gas.L())-1 {
+			t.Log(fmt.Sprintf("step move left the domain from %d", start))
+			t.Fail()
+		}
+	}
+
+	if genome[0] != gas.XIntToXBin(1000)[0] || gas.XBinToXInt(genome) != 1000 {
+		t.Log("move modified the passed genome")
+		t.Fail()
+	}
+}
@@ -0,0 +1,212 @@
+package evolalg
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// NichingMethod describes the method used for keeping several optima in the population.
+type NichingMethod int
+
+const (
+	// NoNiching disables niching. This is the default.
+	NoNiching NichingMethod = iota
+	// FitnessSharing divides the fit of every individual by the amount of individuals sharing its
+	// niche before the roulette is created.
+	FitnessSharing
+	// Clearing leaves the fit of only the Capacity best individuals of every niche and clears the
+	// rest of them before the roulette is created.
+	Clearing
+	// DeterministicCrowding pairs the whole population at random and makes each child compete with
+	// the parent closest to it. The replacement strategy is ignored.
+	DeterministicCrowding
+	// RestrictedTournament makes every offspring compete with the closest of Window randomly picked
+	// individuals of the population. The replacement strategy is ignored.
+	RestrictedTournament
+)
+
+// Niching contains the configuration of a niching method. All of the distances are measured
+// between the individuals decoded to floating point form.
+type Niching struct {
+	Method   NichingMethod
+	Radius   float64 // sharing sigma, clearing radius and the distance between distinct optima
+	Alpha    float64 // shape of the sharing function (1 if 0)
+	Capacity int     // amount of winners of a niche in clearing (1 if 0)
+	Window   int     // window size of the restricted tournament (N if 0)
+}
+
+// Optimum is a distinct optimum found in the population.
+type Optimum struct {
+	X       float64 `json:"x"`
+	Grade   float64 `json:"grade"`
+	Members int     `json:"members"` // amount of individuals in the niche of the optimum
+}
+
+// NichingByName returns the niching method based on its name: "none", "sharing", "clearing",
+// "crowding" or "rts". An empty name returns NoNiching.
+func NichingByName(name string) (NichingMethod, error) {
+	switch name {
+	case "", "none":
+		return NoNiching, nil
+	case "sharing":
+		return FitnessSharing, nil
+	case "clearing":
+		return Clearing, nil
+	case "crowding":
+		return DeterministicCrowding, nil
+	case "rts":
+		return RestrictedTournament, nil
+	}
+	return NoNiching, errors.New("unknown niching method " + name)
+}
+
+// SetNiching sets the niching method used by Solve.
+func (gas *GeneticAlgorithmSolver) SetNiching(n Niching) error {
+	if n.Method != NoNiching && n.Radius <= 0 {
+		return errors.New("niching radius has to be greater than zero")
+	}
+	gas.niching = n
+	return nil
+}
+
+// nichedFits returns the fits modified by fitness sharing or clearing. If neither of them is used
+// then nil is returned.
+func (gas *GeneticAlgorithmSolver) nichedFits(vals []float64, fits []float64) []float64 {
+	N := len(fits)
+	niched := make([]float64, N)
+	copy(niched, fits)
+
+	switch gas.niching.Method {
+	case FitnessSharing:
+		alpha := gas.niching.Alpha
+		if alpha == 0 {
+			alpha = 1
+		}
+		for i := 0; i < N; i++ {
+			m := 0.0
+			for j := 0; j < N; j++ {
+				d := math.Abs(vals[i] - vals[j])
+				if d < gas.niching.Radius {
+					m += 1 - math.Pow(d/gas.niching.Radius, alpha)
+				}
+			}
+			niched[i] = fits[i] / m
+		}
+	case Clearing:
+		capacity := gas.niching.Capacity
+		if capacity <= 0 {
+			capacity = 1
+		}
+		order := make([]int, N)
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return fits[order[i]] > fits[order[j]]
+		})
+		cleared := make([]bool, N)
+		for k, i := range order {
+			if cleared[i] {
+				continue
+			}
+			winners := 1
+			for _, j := range order[k+1:] {
+				if cleared[j] || math.Abs(vals[i]-vals[j]) >= gas.niching.Radius {
+					continue
+				}
+				if winners < capacity {
+					winners++
+				} else {
+					cleared[j] = true
+					niched[j] = 0
+				}
+			}
+		}
+	default:
+		return nil
+	}
+
+	return niched
+}
+
+// crowdingEpoch creates the next population with deterministic crowding or restricted tournament
+// selection.
+func (gas *GeneticAlgorithmSolver) crowdingEpoch(cp, mp float64) error {
+	N := len(gas.popArr)
+	dist := func(i int, off offspring) float64 {
+		return math.Abs(gas.popVals[i] - off.x)
+	}
+
+	if gas.niching.Method == DeterministicCrowding {
+		idx := gas.random().Perm(N)
+		for k := 0; k+1 < N; k += 2 {
+			p1, p2 := idx[k], idx[k+1]
+			children := gas.mate(p1, p2, cp, mp)
+			if dist(p1, children[0])+dist(p2, children[1]) > dist(p1, children[1])+dist(p2, children[0]) {
+				children[0], children[1] = children[1], children[0]
+			}
+			if children[0].fit > gas.fitCache[p1] {
+				gas.putAt(p1, children[0])
+			}
+			if children[1].fit > gas.fitCache[p2] {
+				gas.putAt(p2, children[1])
+			}
+		}
+	} else {
+		lambda, window := gas.lambda, gas.niching.Window
+		if lambda <= 0 {
+			lambda = N
+		}
+		if window <= 0 || window > N {
+			window = N
+		}
+		for _, off := range gas.breed(lambda, cp, mp) {
+			closest := gas.random().Intn(N)
+			for w := 1; w < window; w++ {
+				j := gas.random().Intn(N)
+				if dist(j, off) < dist(closest, off) {
+					closest = j
+				}
+			}
+			if off.fit > gas.fitCache[closest] {
+				gas.putAt(closest, off)
+			}
+		}
+	}
+
+	// The population keeps the cached grades of its members, so only the roulette is rebuilt
+	gas.roulette(gas.popVals)
+	return nil
+}
+
+// Optima returns the distinct optima of the current population. The best individuals are picked
+// greedily - an individual becomes a new optimum if it's at least radius away from all of the
+// better ones. The rest of the population is assigned to the niche of the closest optimum.
+func (gas *GeneticAlgorithmSolver) Optima(radius float64) (optima []Optimum) {
+	N := len(gas.popVals)
+	order := make([]int, N)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return gas.gradeCache[order[i]] > gas.gradeCache[order[j]]
+	})
+
+	for _, i := range order {
+		closest, closestDist := -1, math.MaxFloat64
+		for k := range optima {
+			d := math.Abs(optima[k].X - gas.popVals[i])
+			if d < closestDist {
+				closest, closestDist = k, d
+			}
+		}
+		if closest == -1 || closestDist >= radius {
+			optima = append(optima, Optimum{X: gas.popVals[i], Grade: gas.gradeCache[i], Members: 1})
+		} else {
+			optima[closest].Members++
+		}
+	}
+
+	return
+}
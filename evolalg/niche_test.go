@@ -0,0 +1,77 @@
+package evolalg
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestNiching(t *testing.T) {
+	// Two equally good peaks - niching should keep both of them
+	twoPeaks := func(x float64) float64 {
+		return math.Exp(-math.Pow(x-2, 2)*8) + math.Exp(-math.Pow(x-8, 2)*8)
+	}
+
+	for _, name := range []string{"sharing", "clearing", "crowding", "rts"} {
+		method, err := NichingByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gas, err := NewGeneticAlgorithmSolver(0, 10, 3, twoPeaks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gas.SetSeed(3)
+		gas.SetReplacement(Generational, 0)
+		err = gas.SetNiching(Niching{Method: method, Radius: 1, Window: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		hist, err := gas.Solve(40, 60, 0.8, 0.01)
+		if err != nil {
+			t.Log(fmt.Sprintf("%s: %s", name, err.Error()))
+			t.Fail()
+			continue
+		}
+
+		// Crowding grades only the offspring and the population keeps their grades
+		if method == DeterministicCrowding || method == RestrictedTournament {
+			if want := hist[0].Evaluations + 60*40; hist[len(hist)-1].Evaluations != want {
+				t.Log(fmt.Sprintf("%s: %d evaluations were made instead of %d", name, hist[len(hist)-1].Evaluations, want))
+				t.Fail()
+			}
+			for k, x := range hist[len(hist)-1].PopulationF64 {
+				if hist[len(hist)-1].Grades[k] != twoPeaks(x) {
+					t.Log(fmt.Sprintf("%s: %f is graded %f", name, x, hist[len(hist)-1].Grades[k]))
+					t.Fail()
+				}
+			}
+		}
+
+		optima := hist[len(hist)-1].Optima
+		if len(optima) == 0 {
+			t.Log(fmt.Sprintf("%s: no optima were reported", name))
+			t.Fail()
+			continue
+		}
+		nearTwo, nearEight := false, false
+		for _, o := range optima {
+			if math.Abs(o.X-2) < 0.5 {
+				nearTwo = true
+			}
+			if math.Abs(o.X-8) < 0.5 {
+				nearEight = true
+			}
+		}
+		if !nearTwo || !nearEight {
+			t.Log(fmt.Sprintf("%s: both of the peaks should be found - %v", name, optima))
+			t.Fail()
+		}
+	}
+
+	gas, _ := NewGeneticAlgorithmSolver(0, 10, 3, twoPeaks)
+	if gas.SetNiching(Niching{Method: FitnessSharing}) == nil {
+		t.Log("niching without a radius was accepted")
+		t.Fail()
+	}
+}
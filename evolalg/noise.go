@@ -0,0 +1,86 @@
+package evolalg
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// Objective is the fitness function of a noisy or dynamic problem. It's called with the epoch in
+// which the individual is graded (so that the landscape can change in time) and with the random
+// number source of the solver (so that the noise is reproduced by the seed of the solver).
+type Objective func(x float64, epoch int, rng *rand.Rand) float64
+
+// SetObjective makes the solver grade the individuals with the objective instead of the grading
+// function passed to NewGeneticAlgorithmSolver. That function is still used for finding the lowest
+// grade the fits are based on, so it should be the noise-free objective of the first epoch. A nil
+// objective restores the grading function.
+func (gas *GeneticAlgorithmSolver) SetObjective(obj Objective) {
+	gas.objective = obj
+}
+
+// SetResampling makes every grade the mean of the given amount of samples of the grading function.
+// Every sample is counted as a fitness evaluation and the mean variance of the samples is reported
+// in the Variance of the history entries. 0 or 1 disables the resampling.
+func (gas *GeneticAlgorithmSolver) SetResampling(samples int) error {
+	if samples < 0 {
+		return errors.New("amount of samples can't be negative")
+	}
+	gas.samples = samples
+	return nil
+}
+
+// sampledGrade grades x with the objective (or the grading function) as many times as set by
+// SetResampling and returns the mean of the samples.
+func (gas *GeneticAlgorithmSolver) sampledGrade(x float64) float64 {
+	n := gas.samples
+	if n < 1 {
+		n = 1
+	}
+
+	// The samples of the fitness are graded in a single batch
+	var samples []float64
+	if gas.objective != nil {
+		samples = make([]float64, n)
+		for k := range samples {
+			samples[k] = gas.objective(x, gas.epoch, gas.random())
+		}
+		gas.evals += n
+	} else {
+		xs := make([]float64, n)
+		for k := range xs {
+			xs[k] = x
+		}
+		samples = gas.evaluate(xs)
+	}
+
+	// Welford's algorithm is used for the mean and the variance of the samples
+	mean, m2 := 0.0, 0.0
+	for k := 1; k <= n; k++ {
+		v := samples[k-1]
+		delta := v - mean
+		mean += delta / float64(k)
+		m2 += delta * (v - mean)
+	}
+
+	if n > 1 {
+		if gas.noiseEpoch != gas.epoch {
+			gas.noiseEpoch, gas.varSum, gas.varCount = gas.epoch, 0, 0
+		}
+		gas.varSum += m2 / float64(n-1)
+		gas.varCount++
+	}
+	return mean
+}
+
+// resetNoise forgets the variances of the samples of the previous run.
+func (gas *GeneticAlgorithmSolver) resetNoise() {
+	gas.noiseEpoch, gas.varSum, gas.varCount = 0, 0, 0
+}
+
+// variance returns the mean variance of the samples of the grades made in the current epoch.
+func (gas GeneticAlgorithmSolver) variance() float64 {
+	if gas.noiseEpoch != gas.epoch || gas.varCount == 0 {
+		return 0
+	}
+	return gas.varSum / float64(gas.varCount)
+}
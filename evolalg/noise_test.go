@@ -0,0 +1,107 @@
+package evolalg
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestResampling(t *testing.T) {
+	gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, LabFunction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gas.SetResampling(-1); err == nil {
+		t.Log("negative amount of samples was accepted")
+		t.Fail()
+	}
+
+	// Every sample is counted and a noise-free function has no variance
+	gas.SetSeed(1)
+	gas.SetResampling(4)
+	ed, err := gas.Start(30, 10, 0.75, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	} else if ed.Evaluations != 4*30 || ed.Variance != 0 {
+		t.Log(fmt.Sprintf("initial population made %d evaluations with the variance %f", ed.Evaluations, ed.Variance))
+		t.Fail()
+	}
+
+	// The variance of the noise is reported and the grades are the means of the samples
+	gas.SetObjective(func(x float64, _ int, rng *rand.Rand) float64 {
+		return LabFunction(x) + rng.NormFloat64()
+	})
+	hist, err := gas.Solve(30, 10, 0.75, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for e, ed := range hist {
+		if ed.Variance < 0.5 || ed.Variance > 1.5 {
+			t.Log(fmt.Sprintf("epoch %d reported the variance %f of a noise with the variance 1", e, ed.Variance))
+			t.Fail()
+		}
+	}
+	for i, x := range hist[0].PopulationF64 {
+		if math.Abs(hist[0].Grades[i]-LabFunction(x)) > 2 {
+			t.Log(fmt.Sprintf("%f was graded %f instead of about %f", x, hist[0].Grades[i], LabFunction(x)))
+			t.Fail()
+		}
+	}
+
+	// The same seed reproduces the noise
+	gas.SetSeed(1)
+	first, _ := gas.Solve(30, 5, 0.75, 0.01)
+	gas.SetSeed(1)
+	second, _ := gas.Solve(30, 5, 0.75, 0.01)
+	if first[5].FAVG != second[5].FAVG {
+		t.Log(fmt.Sprintf("runs with the same seed ended with averages %f and %f", first[5].FAVG, second[5].FAVG))
+		t.Fail()
+	}
+
+	// Without the resampling nothing is reported
+	gas.SetResampling(1)
+	ed, _ = gas.Start(30, 10, 0.75, 0.01)
+	if ed.Evaluations != 30 || ed.Variance != 0 {
+		t.Log(fmt.Sprintf("run without resampling made %d evaluations with the variance %f", ed.Evaluations, ed.Variance))
+		t.Fail()
+	}
+}
+
+func TestNoisyProblem(t *testing.T) {
+	p := Problem{
+		Lower: []float64{-4},
+		Upper: []float64{12},
+		F:     func(x []float64) float64 { return LabFunction(x[0]) },
+		Objective: func(x []float64, _ int, rng *rand.Rand) float64 {
+			return LabFunction(x[0]) + 0.1*rng.NormFloat64()
+		},
+	}
+	if _, err := newDifferentialEvolution(p); err == nil {
+		t.Log("differential evolution accepted a noisy problem")
+		t.Fail()
+	}
+
+	for _, name := range []string{"ga", "sa", "tabu"} {
+		a, err := AlgorithmByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := a.New(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = s.Configure(Params{"probki": "3", "epoki": "5"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		hist, err := s.Run(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		} else if last := hist[len(hist)-1]; last.Variance == 0 {
+			t.Log(fmt.Sprintf("%s reported no variance of the noise", name))
+			t.Fail()
+		}
+	}
+}
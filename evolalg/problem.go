@@ -0,0 +1,153 @@
+package evolalg
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// LabFunction is the grading function of the laboratory assignment:
+// F(x)= x MOD1 *(COS(20*π *x)–SIN(x))
+func LabFunction(x float64) float64 {
+	return math.Mod(x, 1*(math.Cos(20*math.Pi*x)-math.Sin(x)))
+}
+
+// Problem is a continuous optimization problem of maximizing F on a box bounded by Lower and Upper
+// (inclusive) in every dimension. If Genotype is set then the problem is defined on raw genomes
+// instead and only the solvers using the binary encoding can solve it. The same goes for the noisy
+// or dynamic problems whose Objective is used instead of F and for the one-dimensional problems
+// graded in batches by Fitness (F isn't needed then). Name identifies the function (or the
+// genotype) for caching the results computed once per problem, such as the exact optimum.
+type Problem struct {
+	Lower     []float64
+	Upper     []float64
+	F         func(x []float64) float64
+	Genotype  Genotype
+	Objective func(x []float64, epoch int, rng *rand.Rand) float64
+	Fitness   Fitness
+	Name      string
+}
+
+// Dim returns the dimensionality of the problem.
+func (p Problem) Dim() int {
+	return len(p.Lower)
+}
+
+// check returns an error if the problem is not well defined.
+func (p Problem) check() error {
+	if p.Genotype != nil {
+		return errors.New("problem defined on genomes needs a solver using the binary encoding")
+	} else if p.Objective != nil {
+		return errors.New("noisy or dynamic problem needs a solver using the binary encoding")
+	} else if p.Fitness != nil {
+		return errors.New("problem graded in batches needs a solver using the binary encoding")
+	} else if p.F == nil {
+		return errors.New("problem has no function")
+	}
+	return p.checkBounds()
+}
+
+// checkBounds returns an error if the bounds of the problem are not well defined.
+func (p Problem) checkBounds() error {
+	if len(p.Lower) == 0 || len(p.Lower) != len(p.Upper) {
+		return errors.New("problem bounds have to be of equal, non-zero length")
+	}
+	for i := range p.Lower {
+		if p.Lower[i] > p.Upper[i] {
+			return errors.New("provided lower bound greater than higher bound")
+		}
+	}
+	return nil
+}
+
+// Termination contains the conditions that stop a solver before it runs for all of the epochs
+// passed to Solve. Each of the conditions is disabled if set to 0 (or HasTarget is false).
+type Termination struct {
+	MaxEvaluations   int     // stop after this many fitness evaluations
+	StagnationEpochs int     // stop if the best grade did not improve for this many epochs
+	Target           float64 // stop when the best grade reaches the target
+	HasTarget        bool
+}
+
+// Done checks if the run should be stopped based on the amount of evaluations, the best grade found
+// so far and the epoch in which it was found.
+func (t Termination) Done(epoch, evals int, best float64, bestEpoch int) bool {
+	if t.MaxEvaluations > 0 && evals >= t.MaxEvaluations {
+		return true
+	} else if t.StagnationEpochs > 0 && epoch-bestEpoch >= t.StagnationEpochs {
+		return true
+	} else if t.HasTarget && best >= t.Target {
+		return true
+	}
+	return false
+}
+
+// vecEpochData creates the history entry of a population of real vectors and their grades. In
+// one-dimensional problems the population is also saved in PopulationF64 so that it can be
+// presented the same way as the one of GeneticAlgorithmSolver. Fits are equal to the grades.
+func vecEpochData(pop [][]float64, grades []float64, elite []float64, eliteGrade float64) (ed EpochData) {
+	N := len(pop)
+	ed.PopulationVec = make([][]float64, N)
+	for i := range pop {
+		ed.PopulationVec[i] = append([]float64(nil), pop[i]...)
+	}
+	ed.Grades = append([]float64(nil), grades...)
+	ed.Fits = append([]float64(nil), grades...)
+	ed.EliteVec = append([]float64(nil), elite...)
+	ed.EliteFit, ed.EliteGrade = eliteGrade, eliteGrade
+	if len(elite) == 1 {
+		ed.Elite = elite[0]
+		ed.PopulationF64 = make([]float64, N)
+		for i := range pop {
+			ed.PopulationF64[i] = pop[i][0]
+		}
+	}
+
+	ed.FMin, ed.FAVG, ed.FMax = math.Inf(1), 0, math.Inf(-1)
+	for _, g := range grades {
+		ed.FMin = math.Min(ed.FMin, g)
+		ed.FMax = math.Max(ed.FMax, g)
+		ed.FAVG += g
+	}
+	ed.FAVG /= float64(N)
+
+	// Diversity of real vectors - mean of the standard deviations of every dimension and the
+	// amount of distinct vectors
+	unique := make(map[string]struct{}, N)
+	if N > 0 {
+		col := make([]float64, N)
+		for d := range pop[0] {
+			for i := range pop {
+				col[i] = pop[i][d]
+			}
+			ed.Diversity.XStdDev += stdDev(col)
+		}
+		ed.Diversity.XStdDev /= float64(len(pop[0]))
+	}
+	for i := range pop {
+		key := make([]byte, 0, 8*len(pop[i]))
+		for _, v := range pop[i] {
+			bits := math.Float64bits(v)
+			for k := 0; k < 8; k++ {
+				key = append(key, byte(bits>>(8*k)))
+			}
+		}
+		unique[string(key)] = struct{}{}
+	}
+	ed.Diversity.UniqueGenotypes = len(unique)
+	ed.Diversity.FitPercentiles = MeasurePercentiles(grades)
+
+	return
+}
+
+// clampToBounds puts x back into the <lo, hi> set. A coordinate that went out of bounds is placed
+// halfway between the bound and the coordinate of the parent so that the search doesn't pile up on
+// the bounds.
+func clampToBounds(x, parent, lo, hi float64) float64 {
+	if x < lo {
+		return (lo + parent) / 2
+	} else if x > hi {
+		return (hi + parent) / 2
+	}
+	return x
+}
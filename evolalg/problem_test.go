@@ -0,0 +1,71 @@
+package evolalg
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestTermination(t *testing.T) {
+	p := Problem{Lower: []float64{-5, -5}, Upper: []float64{5, 5}, F: sphere}
+	de, err := NewDifferentialEvolutionSolver(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	de.SetSeed(1)
+	de.SetTermination(Termination{Target: -0.01, HasTarget: true})
+	hist, err := de.Solve(20, 500, 0.5, 0.9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := hist[len(hist)-1]
+	if len(hist) == 501 || last.EliteFit < -0.01 {
+		t.Log(fmt.Sprintf("DE didn't stop on the target: %d epochs, elite graded %f", len(hist)-1, last.EliteFit))
+		t.Fail()
+	}
+
+	de.SetTermination(Termination{MaxEvaluations: 200})
+	hist, err = de.Solve(20, 500, 0.5, 0.9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evals := hist[len(hist)-1].Evaluations; evals != 200 {
+		t.Log(fmt.Sprintf("DE stopped after %d evaluations instead of 200", evals))
+		t.Fail()
+	}
+
+	gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, func(x float64) float64 {
+		return math.Mod(x, 1*(math.Cos(20*math.Pi*x)-math.Sin(x)))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gas.SetSeed(1)
+	gas.SetTermination(Termination{StagnationEpochs: 5})
+	hist, err = gas.Solve(10, 500, 0.75, 0.005)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last = hist[len(hist)-1]
+	if len(hist) == 501 || len(hist)-1-last.EliteEpoch != 5 {
+		t.Log(fmt.Sprintf("GA didn't stop on stagnation: %d epochs, elite found in epoch %d", len(hist)-1, last.EliteEpoch))
+		t.Fail()
+	}
+}
+
+func TestVecEpochData(t *testing.T) {
+	pop := [][]float64{{1}, {2}, {2}, {5}}
+	ed := vecEpochData(pop, []float64{-1, -4, -4, -25}, []float64{1}, -1)
+	if ed.FMax != -1 || ed.FMin != -25 || ed.FAVG != -8.5 {
+		t.Log(fmt.Sprintf("wrong fmax/favg/fmin: %f %f %f", ed.FMax, ed.FAVG, ed.FMin))
+		t.Fail()
+	}
+	if ed.Diversity.UniqueGenotypes != 3 {
+		t.Log(fmt.Sprintf("%d unique vectors instead of 3", ed.Diversity.UniqueGenotypes))
+		t.Fail()
+	}
+	if len(ed.PopulationF64) != 4 || ed.PopulationF64[3] != 5 || ed.Elite != 1 {
+		t.Log("one-dimensional population wasn't saved in PopulationF64")
+		t.Fail()
+	}
+}
@@ -0,0 +1,329 @@
+package evolalg
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Topology describes which particles share their best positions in particle swarm optimization.
+type Topology int
+
+const (
+	// GlobalBest makes every particle follow the best position found by the whole swarm. This is
+	// the default.
+	GlobalBest Topology = iota
+	// RingBest makes every particle follow the best position found by its Neighbours closest
+	// particles on each side of a ring.
+	RingBest
+)
+
+// VelocityRule describes the way the velocities of the particles are updated.
+type VelocityRule int
+
+const (
+	// InertiaWeight multiplies the previous velocity by the inertia weight. This is the default.
+	InertiaWeight VelocityRule = iota
+	// Constriction multiplies the whole velocity by the constriction factor calculated from C1 and
+	// C2 (Clerc & Kennedy, 2002). The inertia weight is ignored and C1 + C2 has to exceed 4.
+	Constriction
+)
+
+// TopologyByName returns the swarm topology based on its name: "gbest" or "ring". An empty name
+// returns GlobalBest.
+func TopologyByName(name string) (Topology, error) {
+	switch name {
+	case "", "gbest":
+		return GlobalBest, nil
+	case "ring":
+		return RingBest, nil
+	}
+	return GlobalBest, errors.New("unknown swarm topology " + name)
+}
+
+// VelocityRuleByName returns the velocity update rule based on its name: "inertia" or
+// "constriction". An empty name returns InertiaWeight.
+func VelocityRuleByName(name string) (VelocityRule, error) {
+	switch name {
+	case "", "inertia":
+		return InertiaWeight, nil
+	case "constriction":
+		return Constriction, nil
+	}
+	return InertiaWeight, errors.New("unknown velocity update rule " + name)
+}
+
+// Swarm contains the configuration of particle swarm optimization.
+type Swarm struct {
+	Topology   Topology
+	Neighbours int // amount of neighbours on each side of a particle in RingBest (1 if 0)
+	Rule       VelocityRule
+	Inertia    float64 // inertia weight (0.7298 if 0)
+	C1         float64 // cognitive coefficient (1.49618 if 0, 2.05 with Constriction)
+	C2         float64 // social coefficient (1.49618 if 0, 2.05 with Constriction)
+	VMax       float64 // velocity limit as a fraction of the range of every dimension (no limit if 0)
+	Snapshots  bool    // save the velocities and personal bests of the particles in the history
+}
+
+// SwarmSnapshot is the state of the swarm saved in the history in addition to the positions of the
+// particles (which are saved in PopulationVec).
+type SwarmSnapshot struct {
+	Velocities    [][]float64 `json:"velocities"`
+	PersonalBests [][]float64 `json:"personalBests"`
+}
+
+// ParticleSwarmSolver is a particle swarm optimization solver of continuous multi-dimensional
+// problems. The history it returns has the same format as the one of GeneticAlgorithmSolver.
+type ParticleSwarmSolver struct {
+	problem     Problem     // maximized function and its bounds
+	swarm       Swarm       // configuration of the swarm
+	termination Termination // conditions that stop Solve before all of the epochs
+	rng         *rand.Rand  // source of all of the random numbers used by the solver
+
+	pos        [][]float64 // positions of the particles
+	vel        [][]float64 // velocities of the particles
+	grades     []float64   // grades of the current positions
+	pBest      [][]float64 // best positions found by each particle
+	pBestGrade []float64   // grades of the best positions of each particle
+	elite      []float64   // best position found by the swarm
+	eliteGrade float64     // grade of the elite
+	eliteEpoch int         // epoch in which the current elite was found for the first time
+	evals      int         // amount of fitness evaluations since the start of Solve
+	epoch      int         // currently processed epoch (0 is the initial swarm)
+	epochs     int         // amount of epochs passed to Start
+}
+
+// NewParticleSwarmSolver creates a new instance of a particle swarm optimization solver of the
+// given problem.
+func NewParticleSwarmSolver(p Problem) (ps ParticleSwarmSolver, err error) {
+	err = p.check()
+	if err != nil {
+		return
+	}
+	ps.problem = p
+	err = ps.SetSwarm(Swarm{})
+	return
+}
+
+// SetSwarm sets the configuration of the swarm.
+func (ps *ParticleSwarmSolver) SetSwarm(s Swarm) error {
+	if s.Inertia < 0 || s.C1 < 0 || s.C2 < 0 || s.VMax < 0 || s.Neighbours < 0 {
+		return errors.New("swarm coefficients can't be negative")
+	}
+	if s.Neighbours == 0 {
+		s.Neighbours = 1
+	}
+	if s.Inertia == 0 {
+		s.Inertia = 0.7298
+	}
+	if s.C1 == 0 {
+		s.C1 = 1.49618
+		if s.Rule == Constriction {
+			s.C1 = 2.05
+		}
+	}
+	if s.C2 == 0 {
+		s.C2 = 1.49618
+		if s.Rule == Constriction {
+			s.C2 = 2.05
+		}
+	}
+	if s.Rule == Constriction && s.C1+s.C2 <= 4 {
+		return errors.New("constriction needs the sum of the coefficients to be greater than 4")
+	}
+	ps.swarm = s
+	return nil
+}
+
+// SetSeed makes the solver use its own random number source seeded with the given seed so that the
+// runs can be repeated.
+func (ps *ParticleSwarmSolver) SetSeed(seed int64) {
+	ps.rng = rand.New(rand.NewSource(seed))
+}
+
+// SetTermination sets the conditions that stop Solve before it runs for all of the epochs. The
+// history returned by Solve ends with the epoch in which the run was stopped.
+func (ps *ParticleSwarmSolver) SetTermination(t Termination) {
+	ps.termination = t
+}
+
+// Evaluations returns the amount of fitness evaluations made since the start of the last Solve.
+func (ps ParticleSwarmSolver) Evaluations() int {
+	return ps.evals
+}
+
+// random returns the random number source of the solver. If none was seeded then a new one is
+// created based on the current time.
+func (ps *ParticleSwarmSolver) random() *rand.Rand {
+	if ps.rng == nil {
+		ps.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return ps.rng
+}
+
+// Grade calculates the grade of the passed position.
+func (ps *ParticleSwarmSolver) Grade(x []float64) float64 {
+	ps.evals++
+	return ps.problem.F(x)
+}
+
+// Solve runs the particle swarm optimization for N random particles for a given amount of epochs
+// and returns a history of the algorithm's execution.
+func (ps *ParticleSwarmSolver) Solve(N, epochs int) (hist []EpochData, err error) {
+	ed, err := ps.Start(N, epochs)
+	if err != nil {
+		return
+	}
+	hist = append(make([]EpochData, 0, epochs+1), ed)
+	for done := epochs == 0; !done; {
+		ed, done, err = ps.Step()
+		if err != nil {
+			return
+		}
+		hist = append(hist, ed)
+	}
+	return
+}
+
+// Start initializes the swarm the same way as Solve and returns the history entry of the initial
+// positions. The following epochs are run with Step.
+func (ps *ParticleSwarmSolver) Start(N, epochs int) (ed EpochData, err error) {
+	if N < 2 {
+		err = errors.New("swarm needs at least 2 particles")
+		return
+	}
+
+	// Initialize the swarm with random positions and velocities
+	p := ps.problem
+	ps.pos, ps.vel = make([][]float64, N), make([][]float64, N)
+	ps.pBest, ps.pBestGrade = make([][]float64, N), make([]float64, N)
+	ps.grades = make([]float64, N)
+	ps.elite, ps.eliteGrade, ps.eliteEpoch = nil, math.Inf(-1), 0
+	ps.evals = 0
+	ps.epoch, ps.epochs = 0, epochs
+	for i := 0; i < N; i++ {
+		ps.pos[i], ps.vel[i] = make([]float64, p.Dim()), make([]float64, p.Dim())
+		for j := range ps.pos[i] {
+			r := p.Upper[j] - p.Lower[j]
+			ps.pos[i][j] = p.Lower[j] + ps.random().Float64()*r
+			ps.vel[i][j] = ps.clampVelocity((ps.random().Float64()-0.5)*r, j)
+		}
+		ps.grades[i] = ps.Grade(ps.pos[i])
+		ps.pBest[i], ps.pBestGrade[i] = append([]float64(nil), ps.pos[i]...), ps.grades[i]
+	}
+	ps.updateElite(0)
+	return ps.epochData(), nil
+}
+
+// Step runs a single epoch of the solver started with Start and returns its history entry. done is
+// set if any of the termination conditions is met or the epoch is the last one passed to Start.
+func (ps *ParticleSwarmSolver) Step() (ed EpochData, done bool, err error) {
+	if ps.pos == nil {
+		err = errors.New("solver has to be started before running an epoch")
+		return
+	}
+	ps.epoch++
+	ps.move()
+	ps.updateElite(ps.epoch)
+	ed = ps.epochData()
+	done = ps.epoch >= ps.epochs || ps.termination.Done(ps.epoch, ps.evals, ps.eliteGrade, ps.eliteEpoch)
+	return
+}
+
+// move updates the velocities and positions of all of the particles and grades the new positions.
+func (ps *ParticleSwarmSolver) move() {
+	s, p := ps.swarm, ps.problem
+	N := len(ps.pos)
+	w, chi := s.Inertia, 1.0
+	if s.Rule == Constriction {
+		phi := s.C1 + s.C2
+		w, chi = 1, 2/math.Abs(2-phi-math.Sqrt(phi*phi-4*phi))
+	}
+
+	// The neighbourhood bests are taken from the state before the move so that the order of the
+	// particles doesn't matter
+	social := make([][]float64, N)
+	for i := 0; i < N; i++ {
+		social[i] = ps.neighbourhoodBest(i)
+	}
+
+	for i := 0; i < N; i++ {
+		for j := range ps.pos[i] {
+			r1, r2 := ps.random().Float64(), ps.random().Float64()
+			v := chi * (w*ps.vel[i][j] +
+				s.C1*r1*(ps.pBest[i][j]-ps.pos[i][j]) +
+				s.C2*r2*(social[i][j]-ps.pos[i][j]))
+			v = ps.clampVelocity(v, j)
+
+			// Particles that leave the domain stop at its bound
+			x := ps.pos[i][j] + v
+			if x < p.Lower[j] {
+				x, v = p.Lower[j], 0
+			} else if x > p.Upper[j] {
+				x, v = p.Upper[j], 0
+			}
+			ps.pos[i][j], ps.vel[i][j] = x, v
+		}
+		ps.grades[i] = ps.Grade(ps.pos[i])
+		if ps.grades[i] > ps.pBestGrade[i] {
+			ps.pBest[i], ps.pBestGrade[i] = append([]float64(nil), ps.pos[i]...), ps.grades[i]
+		}
+	}
+}
+
+// neighbourhoodBest returns the best personal best position in the neighbourhood of the i-th
+// particle.
+func (ps *ParticleSwarmSolver) neighbourhoodBest(i int) []float64 {
+	if ps.swarm.Topology == GlobalBest {
+		return ps.elite
+	}
+	N := len(ps.pos)
+	best := i
+	for k := -ps.swarm.Neighbours; k <= ps.swarm.Neighbours; k++ {
+		j := ((i+k)%N + N) % N
+		if ps.pBestGrade[j] > ps.pBestGrade[best] {
+			best = j
+		}
+	}
+	return ps.pBest[best]
+}
+
+// clampVelocity limits the velocity in the j-th dimension to VMax of its range.
+func (ps *ParticleSwarmSolver) clampVelocity(v float64, j int) float64 {
+	if ps.swarm.VMax <= 0 {
+		return v
+	}
+	vMax := ps.swarm.VMax * (ps.problem.Upper[j] - ps.problem.Lower[j])
+	return math.Max(-vMax, math.Min(vMax, v))
+}
+
+// updateElite saves the best personal best of the swarm as the elite if it's better than the
+// current one.
+func (ps *ParticleSwarmSolver) updateElite(epoch int) {
+	for i := range ps.pBest {
+		if ps.pBestGrade[i] > ps.eliteGrade {
+			ps.elite = append([]float64(nil), ps.pBest[i]...)
+			ps.eliteGrade, ps.eliteEpoch = ps.pBestGrade[i], epoch
+		}
+	}
+}
+
+// epochData creates the history entry of the current state of the solver.
+func (ps *ParticleSwarmSolver) epochData() EpochData {
+	ed := vecEpochData(ps.pos, ps.grades, ps.elite, ps.eliteGrade)
+	ed.EliteEpoch = ps.eliteEpoch
+	ed.Evaluations = ps.evals
+	if ps.swarm.Snapshots {
+		snap := &SwarmSnapshot{
+			Velocities:    make([][]float64, len(ps.vel)),
+			PersonalBests: make([][]float64, len(ps.pBest)),
+		}
+		for i := range ps.vel {
+			snap.Velocities[i] = append([]float64(nil), ps.vel[i]...)
+			snap.PersonalBests[i] = append([]float64(nil), ps.pBest[i]...)
+		}
+		ed.Swarm = snap
+	}
+	return ed
+}
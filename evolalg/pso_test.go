@@ -0,0 +1,66 @@
+package evolalg
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParticleSwarm(t *testing.T) {
+	p := Problem{Lower: []float64{-5, -5, -5}, Upper: []float64{5, 5, 5}, F: sphere}
+	for _, topology := range []string{"gbest", "ring"} {
+		for _, rule := range []string{"inertia", "constriction"} {
+			s := Swarm{VMax: 0.2, Snapshots: true}
+			var err error
+			s.Topology, err = TopologyByName(topology)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.Rule, err = VelocityRuleByName(rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ps, err := NewParticleSwarmSolver(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ps.SetSeed(5)
+			err = ps.SetSwarm(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hist, err := ps.Solve(20, 150)
+			if err != nil {
+				t.Log(fmt.Sprintf("%s/%s: %s", topology, rule, err.Error()))
+				t.Fail()
+				continue
+			}
+
+			last := hist[len(hist)-1]
+			if last.EliteFit < -1e-3 {
+				t.Log(fmt.Sprintf("%s/%s: elite %v graded %f is too far from the optimum", topology, rule, last.EliteVec, last.EliteFit))
+				t.Fail()
+			}
+			for i, ed := range hist {
+				if ed.Swarm == nil || len(ed.Swarm.Velocities) != 20 || len(ed.PopulationVec) != 20 {
+					t.Log(fmt.Sprintf("%s/%s: epoch %d has no swarm snapshot", topology, rule, i))
+					t.Fail()
+					break
+				}
+				for _, v := range ed.Swarm.Velocities {
+					for _, vj := range v {
+						if vj > 2 || vj < -2 {
+							t.Log(fmt.Sprintf("%s/%s: velocity %f exceeds the limit in epoch %d", topology, rule, vj, i))
+							t.Fail()
+						}
+					}
+				}
+			}
+		}
+	}
+
+	ps, _ := NewParticleSwarmSolver(p)
+	if err := ps.SetSwarm(Swarm{Rule: Constriction, C1: 1, C2: 1}); err == nil {
+		t.Log("constriction accepted coefficients summing to 2")
+		t.Fail()
+	}
+}
@@ -0,0 +1,774 @@
+package evolalg
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// Algorithm describes a solver registered in the package: its name, its parameters and the way a
+// new instance of it is created for a given problem.
+type Algorithm struct {
+	Name   string                          `json:"name"`   // key of the algorithm (e.g. "ga")
+	Label  string                          `json:"label"`  // human readable name
+	Params []Param                         `json:"params"` // parameters other than CommonParams
+	New    func(p Problem) (Solver, error) `json:"-"`      // creates an unconfigured solver
+}
+
+// Schema returns all of the parameters accepted by the solvers of the algorithm.
+func (a Algorithm) Schema() []Param {
+	return append(append([]Param(nil), CommonParams...), a.Params...)
+}
+
+// algorithms contains the registered algorithms in the order of registration.
+var algorithms = []Algorithm{
+	{Name: "ga", Label: "algorytm genetyczny", Params: gaParams, New: newGeneticAlgorithm},
+	{Name: "de", Label: "ewolucja różnicowa", Params: deParams, New: newDifferentialEvolution},
+	{Name: "pso", Label: "rój cząstek", Params: psoParams, New: newParticleSwarm},
+	{Name: "cmaes", Label: "CMA-ES", Params: cmaesParams, New: newCMAES},
+	{Name: "sa", Label: "symulowane wyżarzanie", Params: saParams, New: newSimulatedAnnealing},
+	{Name: "tabu", Label: "przeszukiwanie tabu", Params: tabuParams, New: newTabuSearch},
+}
+
+// Register adds an algorithm to the registry. It panics if an algorithm with the same name is
+// already registered.
+func Register(a Algorithm) {
+	if _, err := AlgorithmByName(a.Name); err == nil {
+		panic("evolalg: algorithm " + a.Name + " is already registered")
+	}
+	algorithms = append(algorithms, a)
+}
+
+// Algorithms returns all of the registered algorithms in the order of registration.
+func Algorithms() []Algorithm {
+	return append([]Algorithm(nil), algorithms...)
+}
+
+// AlgorithmByName returns the registered algorithm with the given name.
+func AlgorithmByName(name string) (Algorithm, error) {
+	for _, a := range algorithms {
+		if a.Name == name {
+			return a, nil
+		}
+	}
+	return Algorithm{}, errors.New("unknown algorithm " + name)
+}
+
+// binaryProblem checks if the problem can be solved by the solvers working on the binary encoding
+// of GeneticAlgorithmSolver and returns the function creating their encoding with the accuracy
+// given in the parameter "d". If the parameter "optimum" is set then the encoding is compared with
+// the exact optimum of the problem (cached by the name of the problem), which is refused for the
+// problems graded in batches, e.g. by external programs, as it would grade every point of the
+// search space with their fitness. The grades are averaged over the amount of samples given in the
+// parameter "probki" and the failed evaluations are handled as given in the parameters "awarie" and
+// "ponowienia".
+func binaryProblem(p Problem) (func(params Params) (GeneticAlgorithmSolver, error), error) {
+	var encoding func(d int) (GeneticAlgorithmSolver, error)
+	var objective Objective
+	if p.Fitness != nil && (p.Genotype != nil || p.Objective != nil) {
+		return nil, errors.New("problem graded in batches can't have a genotype or a noisy or dynamic objective")
+	}
+	if p.Objective != nil {
+		// The lowest grade of the fits is found with F or, if there's none, with the objective of the
+		// first epoch
+		q, rng, obj := p, rand.New(rand.NewSource(0)), p.Objective
+		q.Objective = nil
+		if q.F == nil {
+			q.F = func(x []float64) float64 { return obj(x, 0, rng) }
+		}
+		objective = func(x float64, epoch int, rng *rand.Rand) float64 { return obj([]float64{x}, epoch, rng) }
+		p = q
+	}
+	if p.Fitness != nil {
+		if err := p.checkBounds(); err != nil {
+			return nil, err
+		} else if p.Dim() != 1 {
+			return nil, errors.New("binary encoding supports only one-dimensional problems")
+		}
+		a, b, fitness := p.Lower[0], p.Upper[0], p.Fitness
+		encoding = func(d int) (GeneticAlgorithmSolver, error) {
+			return NewBatchGeneticAlgorithmSolver(a, b, byte(d), fitness)
+		}
+	} else if p.Genotype != nil {
+		encoding = func(int) (GeneticAlgorithmSolver, error) { return NewGenotypeSolver(p.Genotype) }
+	} else if err := p.check(); err != nil {
+		return nil, err
+	} else if p.Dim() != 1 {
+		return nil, errors.New("binary encoding supports only one-dimensional problems")
+	} else {
+		a, b := p.Lower[0], p.Upper[0]
+		gFunc := func(x float64) float64 { return p.F([]float64{x}) }
+		encoding = func(d int) (GeneticAlgorithmSolver, error) {
+			return NewGeneticAlgorithmSolver(a, b, byte(d), gFunc)
+		}
+	}
+	return func(params Params) (gas GeneticAlgorithmSolver, err error) {
+		d, err := params.Int("d")
+		if err != nil {
+			return
+		}
+		gas, err = encoding(d)
+		if err != nil {
+			return
+		}
+		gas.SetObjective(objective)
+		samples, err := params.Int("probki")
+		if err != nil {
+			return
+		}
+		err = gas.SetResampling(samples)
+		if err != nil {
+			return
+		}
+		var failures Failures
+		failures.Policy, err = FailurePolicyByName(params["awarie"])
+		if err != nil {
+			return
+		}
+		failures.Retries, err = params.Int("ponowienia")
+		if err != nil {
+			return
+		}
+		err = gas.SetFailures(failures)
+		if err != nil || !params.Bool("optimum") {
+			return
+		} else if p.Fitness != nil {
+			err = errors.New("exact optimum of a problem graded in batches isn't computed")
+			return
+		}
+		opt, err := gas.CachedExactOptimum(p.Name, 0)
+		if err != nil {
+			return
+		}
+		gas.SetReference(opt)
+		return
+	}, nil
+}
+
+// Parameters of the population based solvers and of the binary encoding.
+var (
+	populationParam = Param{Name: "N", Label: "N", Kind: IntParam, Default: "10"}
+	accuracyParam   = Param{Name: "d", Label: "d", Kind: IntParam, Default: "3"}
+	exactParam      = Param{Name: "optimum", Label: "Prawdziwe optimum (przegląd zupełny)", Kind: BoolParam}
+	samplesParam    = Param{Name: "probki", Label: "Próbki oceny", Kind: IntParam, Default: "1"}
+	failureParam    = Param{Name: "awarie", Label: "Nieudane oceny", Kind: ChoiceParam, Default: "abort", Choices: []Choice{
+		{"abort", "przerwanie"},
+		{"retry", "ponowienie"},
+		{"worst", "najgorsza ocena"},
+	}}
+	retriesParam = Param{Name: "ponowienia", Label: "Ponowienia oceny", Kind: IntParam, Default: "3"}
+)
+
+// gaParams are the parameters of GeneticAlgorithmSolver.
+var gaParams = []Param{
+	populationParam,
+	accuracyParam,
+	exactParam,
+	samplesParam,
+	failureParam,
+	retriesParam,
+	{Name: "Pk", Label: "Pk", Kind: FloatParam, Default: "0.75"},
+	{Name: "Pm", Label: "Pm", Kind: FloatParam, Default: "0.005"},
+	{Name: "inicjalizacja", Label: "Inicjalizacja", Kind: ChoiceParam, Default: "uniform", Choices: []Choice{
+		{"uniform", "losowe x (real)"},
+		{"binary", "losowe x (bin)"},
+		{"lhs", "hiperkostka łacińska"},
+		{"opposition", "opozycyjna"},
+		{"grid", "siatka"},
+	}},
+	{Name: "osobniki", Label: "Znane osobniki", Kind: StringParam, Hint: "np. 11.6, 10.65"},
+	{Name: "zastepowanie", Label: "Zastępowanie", Kind: ChoiceParam, Default: "inplace", Choices: []Choice{
+		{"inplace", "w miejscu z elitą"},
+		{"generational", "pokoleniowe"},
+		{"ss-worst", "stacjonarne (najgorszy)"},
+		{"ss-random", "stacjonarne (losowy)"},
+		{"ss-oldest", "stacjonarne (najstarszy)"},
+		{"plus", "(μ+λ)"},
+		{"comma", "(μ,λ)"},
+	}},
+	{Name: "lambda", Label: "λ", Kind: IntParam, Hint: "N"},
+	{Name: "nisze", Label: "Nisze", Kind: ChoiceParam, Default: "none", Choices: []Choice{
+		{"none", "brak"},
+		{"sharing", "współdzielenie przystosowania"},
+		{"clearing", "oczyszczanie"},
+		{"crowding", "deterministyczny ścisk"},
+		{"rts", "ograniczony turniej"},
+	}},
+	{Name: "promien", Label: "σ niszy", Kind: FloatParam, Default: "0.1"},
+	{Name: "alfa", Label: "α", Kind: FloatParam, Default: "1"},
+	{Name: "pojemnosc", Label: "Pojemność niszy", Kind: IntParam, Default: "1"},
+	{Name: "okno", Label: "Okno turnieju", Kind: IntParam, Hint: "N"},
+	{Name: "lokalne", Label: "Przeszukiwanie lokalne", Kind: ChoiceParam, Default: "none", Choices: []Choice{
+		{"none", "brak"},
+		{"bitflip", "wspinaczka (zmiana bitu)"},
+		{"steepest", "najszybszy wzrost"},
+		{"golden", "złoty podział"},
+	}},
+	{Name: "zapis", Label: "Zapis", Kind: ChoiceParam, Default: "lamarck", Choices: []Choice{
+		{"lamarck", "Lamarcka"},
+		{"baldwin", "Baldwina"},
+	}},
+	{Name: "frakcja", Label: "Frakcja", Kind: FloatParam, Hint: "tylko elita"},
+	{Name: "kroki", Label: "Kroki", Kind: IntParam, Default: "10"},
+	{Name: "adaptacja", Label: "Adaptacja Pk/Pm", Kind: ChoiceParam, Default: "fixed", Choices: []Choice{
+		{"fixed", "stałe"},
+		{"linear", "spadek liniowy"},
+		{"exponential", "spadek wykładniczy"},
+		{"diversity", "sprzężenie z różnorodnością"},
+		{"sp", "Srinivas-Patnaik"},
+	}},
+	{Name: "Pk_koniec", Label: "Pk końcowe", Kind: FloatParam, Hint: "Pk"},
+	{Name: "Pm_koniec", Label: "Pm końcowe", Kind: FloatParam, Hint: "Pm"},
+	{Name: "prog", Label: "Próg entropii", Kind: FloatParam, Default: "0.2"},
+	{Name: "wzmocnienie", Label: "Wzmocnienie Pm", Kind: FloatParam, Default: "2"},
+	{Name: "restarty", Label: "Restarty", Kind: ChoiceParam, Default: "none", Choices: []Choice{
+		{"none", "brak"},
+		{"full", "pełny z elitą"},
+		{"partial", "częściowy (najgorsi)"},
+		{"ipop", "IPOP"},
+	}},
+	{Name: "stagnacja", Label: "Stagnacja (epoki)", Kind: IntParam, Default: "10"},
+	{Name: "prog_restartu", Label: "Próg entropii restartu", Kind: FloatParam, Default: "0"},
+	{Name: "frakcja_restartu", Label: "Frakcja restartu", Kind: FloatParam, Default: "0.5"},
+	{Name: "wzrost", Label: "Wzrost populacji IPOP", Kind: FloatParam, Default: "2"},
+	{Name: "maks_populacja", Label: "Maksymalna populacja IPOP", Kind: IntParam, Default: "1000"},
+	{Name: "slad", Label: "Ślad", Kind: ChoiceParam, Default: "0", Choices: []Choice{
+		{"0", "brak"},
+		{"1", "ruletka"},
+		{"2", "operatory"},
+		{"3", "pełny"},
+	}},
+	{Name: "genealogia", Label: "Genealogia", Kind: BoolParam},
+	{Name: "zmiany", Label: "Reakcja na zmiany", Kind: ChoiceParam, Default: "none", Choices: []Choice{
+		{"none", "brak"},
+		{"reevaluate", "ponowna ocena"},
+		{"hypermutation", "hipermutacja"},
+		{"memory", "pamięć elit"},
+	}},
+	{Name: "tolerancja", Label: "Tolerancja zmian", Kind: FloatParam, Default: "0"},
+	{Name: "hipermutacja", Label: "Mnożnik Pm hipermutacji", Kind: FloatParam, Default: "10"},
+	{Name: "epoki_hipermutacji", Label: "Epoki hipermutacji", Kind: IntParam, Default: "3"},
+	{Name: "pamiec", Label: "Rozmiar pamięci", Kind: IntParam, Default: "5"},
+	{Name: "surogat", Label: "Model zastępczy", Kind: ChoiceParam, Default: "none", Choices: []Choice{
+		{"none", "brak"},
+		{"rbf", "radialne funkcje bazowe"},
+		{"knn", "k najbliższych sąsiadów"},
+		{"gp", "proces gaussowski"},
+	}},
+	{Name: "udzial_ocen", Label: "Udział prawdziwych ocen", Kind: FloatParam, Default: "0.2"},
+	{Name: "sasiedzi", Label: "Sąsiedzi kNN", Kind: IntParam, Default: "5"},
+	{Name: "archiwum", Label: "Rozmiar archiwum", Kind: IntParam, Default: "100"},
+	{Name: "szerokosc_jadra", Label: "Szerokość jądra GP", Kind: FloatParam, Default: "0.1"},
+}
+
+// newGeneticAlgorithm creates a Solver running GeneticAlgorithmSolver.
+func newGeneticAlgorithm(p Problem) (Solver, error) {
+	encoding, err := binaryProblem(p)
+	if err != nil {
+		return nil, err
+	}
+	var gas GeneticAlgorithmSolver
+	var N int
+	var cp, mp float64
+	return &stepSolver{
+		schema: append(append([]Param(nil), CommonParams...), gaParams...),
+		configure: func(params Params) (seededSolver, error) {
+			gas, err = encoding(params)
+			if err != nil {
+				return nil, err
+			}
+			N, cp, mp, err = configureGA(&gas, params)
+			return &gas, err
+		},
+		start: func(epochs int) (EpochData, error) { return gas.Start(N, epochs, cp, mp) },
+		step:  func() (EpochData, bool, error) { return gas.Step() },
+	}, nil
+}
+
+// configureGA applies the parameters of gaParams to the solver and returns the population size and
+// the probabilities passed to Start.
+func configureGA(gas *GeneticAlgorithmSolver, params Params) (N int, cp, mp float64, err error) {
+	N, err = params.Int("N")
+	if err != nil {
+		return
+	}
+	cp, err = params.Float("Pk")
+	if err != nil {
+		return
+	}
+	mp, err = params.Float("Pm")
+	if err != nil {
+		return
+	}
+	trace, err := params.Int("slad")
+	if err != nil {
+		return
+	}
+	gas.SetTraceLevel(TraceLevel(trace))
+	gas.SetGenealogy(params.Bool("genealogia"))
+
+	dyn := Dynamics{}
+	dyn.Response, err = ChangeResponseByName(params["zmiany"])
+	if err != nil {
+		return
+	}
+	dyn.Tolerance, err = params.Float("tolerancja")
+	if err != nil {
+		return
+	}
+	dyn.Rate, err = params.Float("hipermutacja")
+	if err != nil {
+		return
+	}
+	dyn.Epochs, err = params.Int("epoki_hipermutacji")
+	if err != nil {
+		return
+	}
+	dyn.MemorySize, err = params.Int("pamiec")
+	if err != nil {
+		return
+	}
+	err = gas.SetDynamics(dyn)
+	if err != nil {
+		return
+	}
+
+	sur := Surrogate{}
+	sur.Model, err = SurrogateModelByName(params["surogat"])
+	if err != nil {
+		return
+	}
+	sur.Fraction, err = params.Float("udzial_ocen")
+	if err != nil {
+		return
+	}
+	sur.Neighbours, err = params.Int("sasiedzi")
+	if err != nil {
+		return
+	}
+	sur.Archive, err = params.Int("archiwum")
+	if err != nil {
+		return
+	}
+	sur.Width, err = params.Float("szerokosc_jadra")
+	if err != nil {
+		return
+	}
+	err = gas.SetSurrogate(sur)
+	if err != nil {
+		return
+	}
+
+	init, err := InitializerByName(params["inicjalizacja"])
+	if err != nil {
+		return
+	}
+	seeds, err := params.Floats("osobniki")
+	if err != nil {
+		return
+	}
+	if len(seeds) > 0 {
+		init = WithSeeds(init, seeds...)
+	}
+	gas.SetInitializer(init)
+
+	repl, err := ReplacementByName(params["zastepowanie"])
+	if err != nil {
+		return
+	}
+	lambda, err := params.Int("lambda")
+	if err != nil {
+		return
+	}
+	gas.SetReplacement(repl, lambda)
+
+	niching := Niching{}
+	niching.Method, err = NichingByName(params["nisze"])
+	if err != nil {
+		return
+	}
+	if niching.Method != NoNiching {
+		niching.Radius, err = params.Float("promien")
+		if err != nil {
+			return
+		}
+		niching.Alpha, err = params.Float("alfa")
+		if err != nil {
+			return
+		}
+		niching.Capacity, err = params.Int("pojemnosc")
+		if err != nil {
+			return
+		}
+		niching.Window, err = params.Int("okno")
+		if err != nil {
+			return
+		}
+		err = gas.SetNiching(niching)
+		if err != nil {
+			return
+		}
+	}
+
+	ls := LocalSearch{}
+	ls.Method, err = LocalSearchByName(params["lokalne"])
+	if err != nil {
+		return
+	}
+	if ls.Method != NoLocalSearch {
+		if params["zapis"] == "baldwin" {
+			ls.WriteBack = Baldwinian
+		}
+		ls.Fraction, err = params.Float("frakcja")
+		if err != nil {
+			return
+		}
+		ls.MaxSteps, err = params.Int("kroki")
+		if err != nil {
+			return
+		}
+		err = gas.SetLocalSearch(ls)
+		if err != nil {
+			return
+		}
+	}
+
+	ad := Adaptation{FinalCP: cp, FinalMP: mp}
+	ad.Method, err = AdaptationByName(params["adaptacja"])
+	if err != nil {
+		return
+	}
+	if params["Pk_koniec"] != "" {
+		ad.FinalCP, err = params.Float("Pk_koniec")
+		if err != nil {
+			return
+		}
+	}
+	if params["Pm_koniec"] != "" {
+		ad.FinalMP, err = params.Float("Pm_koniec")
+		if err != nil {
+			return
+		}
+	}
+	ad.DiversityThreshold, err = params.Float("prog")
+	if err != nil {
+		return
+	}
+	ad.Boost, err = params.Float("wzmocnienie")
+	if err != nil {
+		return
+	}
+	err = gas.SetAdaptation(ad)
+	if err != nil {
+		return
+	}
+
+	rs := Restart{}
+	rs.Policy, err = RestartByName(params["restarty"])
+	if err != nil {
+		return
+	}
+	if rs.Policy != NoRestart {
+		rs.StagnationEpochs, err = params.Int("stagnacja")
+		if err != nil {
+			return
+		}
+		rs.DiversityThreshold, err = params.Float("prog_restartu")
+		if err != nil {
+			return
+		}
+		rs.Fraction, err = params.Float("frakcja_restartu")
+		if err != nil {
+			return
+		}
+		rs.Growth, err = params.Float("wzrost")
+		if err != nil {
+			return
+		}
+		rs.MaxPopulation, err = params.Int("maks_populacja")
+		if err != nil {
+			return
+		}
+		err = gas.SetRestart(rs)
+	}
+	return
+}
+
+// deParams are the parameters of DifferentialEvolutionSolver.
+var deParams = []Param{
+	populationParam,
+	{Name: "F", Label: "F", Kind: FloatParam, Default: "0.5"},
+	{Name: "CR", Label: "CR", Kind: FloatParam, Default: "0.9"},
+	{Name: "strategia", Label: "Strategia ED", Kind: ChoiceParam, Default: "rand1bin", Choices: []Choice{
+		{"rand1bin", "rand/1/bin"},
+		{"best1bin", "best/1/bin"},
+		{"current-to-best1", "current-to-best/1"},
+	}},
+	{Name: "jade", Label: "Adaptacja JADE", Kind: BoolParam},
+}
+
+// newDifferentialEvolution creates a Solver running DifferentialEvolutionSolver.
+func newDifferentialEvolution(p Problem) (Solver, error) {
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+	var de DifferentialEvolutionSolver
+	var N int
+	var f, cr float64
+	return &stepSolver{
+		schema: append(append([]Param(nil), CommonParams...), deParams...),
+		configure: func(params Params) (seededSolver, error) {
+			var err error
+			de, err = NewDifferentialEvolutionSolver(p)
+			if err != nil {
+				return nil, err
+			}
+			N, err = params.Int("N")
+			if err != nil {
+				return nil, err
+			}
+			f, err = params.Float("F")
+			if err != nil {
+				return nil, err
+			}
+			cr, err = params.Float("CR")
+			if err != nil {
+				return nil, err
+			}
+			strategy, err := DEStrategyByName(params["strategia"])
+			if err != nil {
+				return nil, err
+			}
+			de.SetStrategy(strategy)
+			if params.Bool("jade") {
+				err = de.SetJADE(JADE{})
+			}
+			return &de, err
+		},
+		start: func(epochs int) (EpochData, error) { return de.Start(N, epochs, f, cr) },
+		step:  func() (EpochData, bool, error) { return de.Step() },
+	}, nil
+}
+
+// psoParams are the parameters of ParticleSwarmSolver.
+var psoParams = []Param{
+	populationParam,
+	{Name: "topologia", Label: "Topologia roju", Kind: ChoiceParam, Default: "gbest", Choices: []Choice{
+		{"gbest", "globalna"},
+		{"ring", "pierścień"},
+	}},
+	{Name: "sasiedzi", Label: "Sąsiedzi w pierścieniu", Kind: IntParam, Default: "1"},
+	{Name: "predkosc", Label: "Aktualizacja prędkości", Kind: ChoiceParam, Default: "inertia", Choices: []Choice{
+		{"inertia", "waga bezwładności"},
+		{"constriction", "współczynnik ścisku"},
+	}},
+	{Name: "bezwladnosc", Label: "w", Kind: FloatParam, Default: "0.7298"},
+	{Name: "c1", Label: "c1", Kind: FloatParam, Hint: "1.49618"},
+	{Name: "c2", Label: "c2", Kind: FloatParam, Hint: "1.49618"},
+	{Name: "vmax", Label: "vmax (część przedziału)", Kind: FloatParam, Default: "0.2"},
+	{Name: "migawki", Label: "Animacja roju", Kind: BoolParam},
+}
+
+// newParticleSwarm creates a Solver running ParticleSwarmSolver.
+func newParticleSwarm(p Problem) (Solver, error) {
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+	var ps ParticleSwarmSolver
+	var N int
+	return &stepSolver{
+		schema: append(append([]Param(nil), CommonParams...), psoParams...),
+		configure: func(params Params) (seededSolver, error) {
+			var err error
+			ps, err = NewParticleSwarmSolver(p)
+			if err != nil {
+				return nil, err
+			}
+			N, err = params.Int("N")
+			if err != nil {
+				return nil, err
+			}
+			sw := Swarm{Snapshots: params.Bool("migawki")}
+			sw.Topology, err = TopologyByName(params["topologia"])
+			if err != nil {
+				return nil, err
+			}
+			sw.Rule, err = VelocityRuleByName(params["predkosc"])
+			if err != nil {
+				return nil, err
+			}
+			sw.Neighbours, err = params.Int("sasiedzi")
+			if err != nil {
+				return nil, err
+			}
+			sw.Inertia, err = params.Float("bezwladnosc")
+			if err != nil {
+				return nil, err
+			}
+			sw.C1, err = params.Float("c1")
+			if err != nil {
+				return nil, err
+			}
+			sw.C2, err = params.Float("c2")
+			if err != nil {
+				return nil, err
+			}
+			sw.VMax, err = params.Float("vmax")
+			if err != nil {
+				return nil, err
+			}
+			return &ps, ps.SetSwarm(sw)
+		},
+		start: func(epochs int) (EpochData, error) { return ps.Start(N, epochs) },
+		step:  func() (EpochData, bool, error) { return ps.Step() },
+	}, nil
+}
+
+// cmaesParams are the parameters of CMAESSolver.
+var cmaesParams = []Param{
+	{Name: "N", Label: "λ (próbki w pokoleniu)", Kind: IntParam, Hint: "4+3ln n"},
+	{Name: "sigma0", Label: "σ0", Kind: FloatParam, Hint: "0.3(b-a)"},
+}
+
+// newCMAES creates a Solver running CMAESSolver.
+func newCMAES(p Problem) (Solver, error) {
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+	var cma CMAESSolver
+	var lambda int
+	var sigma0 float64
+	return &stepSolver{
+		schema: append(append([]Param(nil), CommonParams...), cmaesParams...),
+		configure: func(params Params) (seededSolver, error) {
+			var err error
+			cma, err = NewCMAESSolver(p)
+			if err != nil {
+				return nil, err
+			}
+			lambda, err = params.Int("N")
+			if err != nil {
+				return nil, err
+			}
+			sigma0, err = params.Float("sigma0")
+			if err != nil {
+				return nil, err
+			}
+			if sigma0 == 0 {
+				for j := range p.Lower {
+					sigma0 = math.Max(sigma0, 0.3*(p.Upper[j]-p.Lower[j]))
+				}
+			}
+			return &cma, nil
+		},
+		start: func(epochs int) (EpochData, error) { return cma.Start(lambda, epochs, sigma0) },
+		step:  func() (EpochData, bool, error) { return cma.Step() },
+	}, nil
+}
+
+// saParams are the parameters of SimulatedAnnealingSolver.
+var saParams = []Param{
+	accuracyParam,
+	exactParam,
+	samplesParam,
+	failureParam,
+	retriesParam,
+	{Name: "T0", Label: "T0", Kind: FloatParam, Default: "1"},
+	{Name: "chlodzenie", Label: "Chłodzenie", Kind: ChoiceParam, Default: "geometric", Choices: []Choice{
+		{"geometric", "geometryczne (0.95)"},
+		{"linear", "liniowe"},
+		{"log", "logarytmiczne"},
+	}},
+	{Name: "ruch", Label: "Ruch", Kind: ChoiceParam, Default: "bitflip", Choices: []Choice{
+		{"bitflip", "zmiana bitu"},
+		{"twobit", "zmiana dwóch bitów"},
+		{"step", "krok o 10^-d"},
+	}},
+	{Name: "ruchy", Label: "Ruchy na epokę", Kind: IntParam, Default: "1"},
+}
+
+// newSimulatedAnnealing creates a Solver running SimulatedAnnealingSolver.
+func newSimulatedAnnealing(p Problem) (Solver, error) {
+	encoding, err := binaryProblem(p)
+	if err != nil {
+		return nil, err
+	}
+	var sa SimulatedAnnealingSolver
+	var t0 float64
+	return &stepSolver{
+		schema: append(append([]Param(nil), CommonParams...), saParams...),
+		configure: func(params Params) (seededSolver, error) {
+			sa = SimulatedAnnealingSolver{}
+			sa.gas, err = encoding(params)
+			if err != nil {
+				return nil, err
+			}
+			t0, err = params.Float("T0")
+			if err != nil {
+				return nil, err
+			}
+			cooling, err := CoolingByName(params["chlodzenie"])
+			if err != nil {
+				return nil, err
+			}
+			sa.SetCooling(cooling)
+			move, err := MoveByName(params["ruch"])
+			if err != nil {
+				return nil, err
+			}
+			moves, err := params.Int("ruchy")
+			if err != nil {
+				return nil, err
+			}
+			sa.SetMove(move, moves)
+			return &sa, nil
+		},
+		start: func(epochs int) (EpochData, error) { return sa.Start(epochs, t0) },
+		step:  func() (EpochData, bool, error) { return sa.Step() },
+	}, nil
+}
+
+// tabuParams are the parameters of TabuSearchSolver.
+var tabuParams = []Param{
+	accuracyParam,
+	exactParam,
+	samplesParam,
+	failureParam,
+	retriesParam,
+	{Name: "kadencja", Label: "Kadencja tabu", Kind: IntParam, Hint: "l/4"},
+	{Name: "aspiracja", Label: "Aspiracja", Kind: ChoiceParam, Default: "best", Choices: []Choice{
+		{"best", "lepszy od najlepszego"},
+		{"improvement", "poprawa bieżącego"},
+		{"none", "brak"},
+	}},
+}
+
+// newTabuSearch creates a Solver running TabuSearchSolver.
+func newTabuSearch(p Problem) (Solver, error) {
+	encoding, err := binaryProblem(p)
+	if err != nil {
+		return nil, err
+	}
+	var ts TabuSearchSolver
+	return &stepSolver{
+		schema: append(append([]Param(nil), CommonParams...), tabuParams...),
+		configure: func(params Params) (seededSolver, error) {
+			ts = TabuSearchSolver{}
+			ts.gas, err = encoding(params)
+			if err != nil {
+				return nil, err
+			}
+			tenure, err := params.Int("kadencja")
+			if err != nil {
+				return nil, err
+			}
+			aspiration, err := AspirationByName(params["aspiracja"])
+			if err != nil {
+				return nil, err
+			}
+			return &ts, ts.SetTabu(tenure, aspiration)
+		},
+		start: func(epochs int) (EpochData, error) { return ts.Start(epochs) },
+		step:  func() (EpochData, bool, error) { return ts.Step() },
+	}, nil
+}
@@ -0,0 +1,48 @@
+package evolalg
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	for _, name := range []string{"ga", "de", "pso", "cmaes", "sa", "tabu"} {
+		a, err := AlgorithmByName(name)
+		if err != nil {
+			t.Log(err.Error())
+			t.Fail()
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, p := range a.Schema() {
+			if seen[p.Name] {
+				t.Log(fmt.Sprintf("%s: parameter %s is duplicated", name, p.Name))
+				t.Fail()
+			}
+			seen[p.Name] = true
+			if p.Kind == ChoiceParam && len(p.Choices) == 0 {
+				t.Log(fmt.Sprintf("%s: choice parameter %s has no choices", name, p.Name))
+				t.Fail()
+			}
+		}
+	}
+	if _, err := AlgorithmByName("x"); err == nil {
+		t.Log("unknown algorithm was found")
+		t.Fail()
+	}
+
+	// The binary encoding supports only one-dimensional problems
+	a, _ := AlgorithmByName("ga")
+	if _, err := a.New(Problem{Lower: []float64{0, 0}, Upper: []float64{1, 1}, F: sphere}); err == nil {
+		t.Log("genetic algorithm accepted a two-dimensional problem")
+		t.Fail()
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Log("duplicated algorithm was registered")
+			t.Fail()
+		}
+	}()
+	Register(Algorithm{Name: "ga"})
+}
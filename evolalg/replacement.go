@@ -0,0 +1,331 @@
+package evolalg
+
+import (
+	"errors"
+	"sort"
+)
+
+// Replacement describes the way the offspring of an epoch replace the current population.
+type Replacement int
+
+const (
+	// InPlace crosses over and mutates the population in place and puts the elite back in a random
+	// place if it was lost. This is the default.
+	InPlace Replacement = iota
+	// Generational replaces the whole population with the offspring. If there are fewer offspring
+	// than the population size then the best of the parents fill the remaining places.
+	Generational
+	// SteadyStateWorst makes every offspring replace the worst individual of the population.
+	SteadyStateWorst
+	// SteadyStateRandom makes every offspring replace a random individual of the population.
+	SteadyStateRandom
+	// SteadyStateOldest makes every offspring replace the oldest individual of the population.
+	SteadyStateOldest
+	// PlusSelection picks the best individuals out of both the parents and the offspring - (mu+lambda).
+	PlusSelection
+	// CommaSelection picks the best individuals out of the offspring only - (mu,lambda).
+	CommaSelection
+)
+
+// ReplacementByName returns the replacement strategy based on its name: "inplace", "generational",
+// "ss-worst", "ss-random", "ss-oldest", "plus" or "comma". An empty name returns the default one.
+func ReplacementByName(name string) (Replacement, error) {
+	switch name {
+	case "", "inplace":
+		return InPlace, nil
+	case "generational":
+		return Generational, nil
+	case "ss-worst":
+		return SteadyStateWorst, nil
+	case "ss-random":
+		return SteadyStateRandom, nil
+	case "ss-oldest":
+		return SteadyStateOldest, nil
+	case "plus":
+		return PlusSelection, nil
+	case "comma":
+		return CommaSelection, nil
+	}
+	return InPlace, errors.New("unknown replacement strategy " + name)
+}
+
+// OffspringTrace contains the record of how a single offspring was bred.
+type OffspringTrace struct {
+	Parents   [2]int  `json:"parents"`
+	CutPoint  int     `json:"cutPoint"` // -1 if the parents were not crossed over
+	Mutations []int   `json:"mutations"`
+	X         float64 `json:"x"`
+}
+
+// offspring is an individual that was bred but is not yet a part of the population.
+type offspring struct {
+	genome  []byte
+	x       float64
+	grade   float64
+	fit     float64
+	parents [2]int
+	age     int        // epoch in which the individual was born
+	id      int        // genealogy ID (0 if the genealogy is not recorded)
+	birth   Individual // genealogy record of the ungraded offspring
+}
+
+// SetReplacement sets the replacement strategy and the amount of offspring bred in each epoch
+// (lambda). If lambda is 0 then as many offspring as the population size are bred. Lambda is
+// ignored by InPlace.
+func (gas *GeneticAlgorithmSolver) SetReplacement(r Replacement, lambda int) {
+	gas.replacement = r
+	gas.lambda = lambda
+}
+
+// rouletteSelect draws a parent from the population according to the roulette table of the last
+// selection.
+func (gas *GeneticAlgorithmSolver) rouletteSelect() int {
+	N := len(gas.probHBCache)
+	r := gas.random().Float64() * gas.probHBCache[N-1]
+	if gas.trace != nil && gas.traceLevel >= TraceFull {
+		gas.trace.SelectionDraws = append(gas.trace.SelectionDraws, r)
+	}
+	i := sort.SearchFloat64s(gas.probHBCache, r)
+	if i == N {
+		i--
+	}
+	return i
+}
+
+// breed creates lambda new offspring out of the parents drawn with the roulette.
+func (gas *GeneticAlgorithmSolver) breed(lambda int, cp, mp float64) []offspring {
+	offs := make([]offspring, 0, lambda+1)
+	for len(offs) < lambda {
+		children := gas.pair(gas.rouletteSelect(), gas.rouletteSelect(), cp, mp)
+		offs = append(offs, children[0], children[1])
+	}
+
+	// Pairs always produce two children so the last one may be redundant
+	if len(offs) > lambda {
+		offs = offs[:lambda]
+		if gas.trace != nil && len(gas.trace.Offspring) > lambda {
+			gas.trace.Offspring = gas.trace.Offspring[:lambda]
+		}
+	}
+	gas.gradeOffspring(offs)
+	return offs
+}
+
+// mate creates two graded children of the p1 and p2 members of the population (see pair).
+func (gas *GeneticAlgorithmSolver) mate(p1, p2 int, cp, mp float64) (children [2]offspring) {
+	children = gas.pair(p1, p2, cp, mp)
+	gas.gradeOffspring(children[:])
+	return
+}
+
+// pair creates two ungraded children of the p1 and p2 members of the population. The parents are
+// crossed over with the cp probability and every bit of the children is mutated with the mp
+// probability (both can be adapted to the parents, see pairRates).
+func (gas *GeneticAlgorithmSolver) pair(p1, p2 int, cp, mp float64) (children [2]offspring) {
+	cp, mps := gas.pairRates(p1, p2, cp, mp)
+	childA := append([]byte(nil), gas.popArr[p1]...)
+	childB := append([]byte(nil), gas.popArr[p2]...)
+
+	cut := -1
+	if gas.random().Float64() < cp {
+		cut = gas.random().Intn(gas.l - 1)
+		for k := cut; k < gas.l; k++ {
+			childA[k], childB[k] = childB[k], childA[k]
+		}
+	}
+	if gas.trace != nil && gas.traceLevel >= TraceOperators {
+		gas.trace.Pairs = append(gas.trace.Pairs, [2]int{p1, p2})
+	}
+
+	for c, child := range [][]byte{childA, childB} {
+		var mutations []int
+		for k := range child {
+			if gas.random().Float64() <= mps[c] {
+				child[k] ^= 1
+				mutations = append(mutations, k)
+			}
+		}
+
+		off := offspring{genome: child, parents: [2]int{p1, p2}, age: gas.epoch}
+		off.x = gas.XIntToXReal(gas.XBinToXInt(child))
+		if gas.genealogy != nil {
+			// Without crossover each child is a copy of a single parent
+			off.birth = Individual{Parents: []int{gas.popID[p1], gas.popID[p2]}, CutPoint: cut, Mutations: mutations}
+			if cut < 0 {
+				off.birth.Parents = []int{gas.popID[off.parents[c]]}
+			}
+			off.birth.Operator = childOperator(cut, mutations)
+		}
+		children[c] = off
+
+		if gas.trace != nil && gas.traceLevel >= TraceOperators {
+			gas.trace.Offspring = append(gas.trace.Offspring, OffspringTrace{
+				Parents:   off.parents,
+				CutPoint:  cut,
+				Mutations: mutations,
+				X:         off.x,
+			})
+		}
+	}
+	return
+}
+
+// gradeOffspring grades the offspring (all of them are pre-screened together by the surrogate
+// model) and records their births in the genealogy.
+func (gas *GeneticAlgorithmSolver) gradeOffspring(offs []offspring) {
+	xs := make([]float64, len(offs))
+	for i := range offs {
+		xs[i] = offs[i].x
+	}
+	grades := gas.gradeAll(xs)
+	for i := range offs {
+		off := &offs[i]
+		off.grade, off.fit = grades[i], gas.fitFromGrade(grades[i])
+		if gas.genealogy != nil {
+			off.id = gas.born(off.birth, off.genome, off.x, off.grade)
+		}
+	}
+}
+
+// breedEpoch creates the next population by breeding the offspring and replacing the population
+// according to the replacement strategy.
+func (gas *GeneticAlgorithmSolver) breedEpoch(cp, mp float64) error {
+	N := len(gas.popArr)
+	lambda := gas.lambda
+	if lambda <= 0 {
+		lambda = N
+	}
+	offs := gas.breed(lambda, cp, mp)
+
+	switch gas.replacement {
+	case Generational:
+		// The best parents survive only if there are not enough offspring
+		keep := N - len(offs)
+		if keep < 0 {
+			keep = 0
+		}
+		survivors := gas.bestIndices(keep)
+		next := make([]offspring, 0, N)
+		for _, i := range survivors {
+			next = append(next, gas.member(i))
+		}
+		for i := 0; len(next) < N; i++ {
+			next = append(next, offs[i])
+		}
+		gas.setPopulation(next)
+	case SteadyStateWorst, SteadyStateRandom, SteadyStateOldest:
+		for _, off := range offs {
+			var i int
+			switch gas.replacement {
+			case SteadyStateWorst:
+				i = gas.worstIndex()
+			case SteadyStateRandom:
+				i = gas.random().Intn(N)
+			case SteadyStateOldest:
+				i = gas.oldestIndex()
+			}
+			gas.putAt(i, off)
+		}
+	case PlusSelection:
+		pool := make([]offspring, 0, N+len(offs))
+		for i := 0; i < N; i++ {
+			pool = append(pool, gas.member(i))
+		}
+		pool = append(pool, offs...)
+		gas.setPopulation(bestOffspring(pool, N))
+	case CommaSelection:
+		if len(offs) < N {
+			return errors.New("comma selection needs at least as many offspring as the population size")
+		}
+		gas.setPopulation(bestOffspring(offs, N))
+	}
+
+	// The survivors keep their cached grades, so only the roulette is rebuilt
+	gas.roulette(gas.popVals)
+	return nil
+}
+
+// member returns the i-th individual of the population in the form of an offspring so that it can
+// compete with the new ones.
+func (gas *GeneticAlgorithmSolver) member(i int) offspring {
+	return offspring{
+		genome:  gas.popArr[i],
+		x:       gas.popVals[i],
+		grade:   gas.gradeCache[i],
+		fit:     gas.fitCache[i],
+		parents: [2]int{i, i},
+		age:     gas.popAge[i],
+		id:      gas.id(i),
+	}
+}
+
+// id returns the genealogy ID of the i-th individual of the population (0 if the genealogy is not
+// recorded).
+func (gas *GeneticAlgorithmSolver) id(i int) int {
+	if gas.popID == nil {
+		return 0
+	}
+	return gas.popID[i]
+}
+
+// putAt replaces the i-th individual of the population with the offspring.
+func (gas *GeneticAlgorithmSolver) putAt(i int, off offspring) {
+	gas.popArr[i] = off.genome
+	gas.popVals[i] = off.x
+	gas.gradeCache[i] = off.grade
+	gas.fitCache[i] = off.fit
+	gas.popAge[i] = off.age
+	if gas.popID != nil {
+		gas.popID[i] = off.id
+	}
+}
+
+// setPopulation replaces the whole population with the given individuals.
+func (gas *GeneticAlgorithmSolver) setPopulation(next []offspring) {
+	for i, off := range next {
+		gas.putAt(i, off)
+	}
+}
+
+// bestIndices returns the indices of the n best individuals of the population.
+func (gas *GeneticAlgorithmSolver) bestIndices(n int) []int {
+	idx := make([]int, len(gas.fitCache))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return gas.fitCache[idx[i]] > gas.fitCache[idx[j]]
+	})
+	return idx[:n]
+}
+
+// worstIndex returns the index of the individual with the lowest fit.
+func (gas *GeneticAlgorithmSolver) worstIndex() int {
+	worst := 0
+	for i := 1; i < len(gas.fitCache); i++ {
+		if gas.fitCache[i] < gas.fitCache[worst] {
+			worst = i
+		}
+	}
+	return worst
+}
+
+// oldestIndex returns the index of the individual that was born the earliest.
+func (gas *GeneticAlgorithmSolver) oldestIndex() int {
+	oldest := 0
+	for i := 1; i < len(gas.popAge); i++ {
+		if gas.popAge[i] < gas.popAge[oldest] {
+			oldest = i
+		}
+	}
+	return oldest
+}
+
+// bestOffspring returns the n individuals with the highest fit.
+func bestOffspring(offs []offspring, n int) []offspring {
+	sorted := append([]offspring(nil), offs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].fit > sorted[j].fit
+	})
+	return sorted[:n]
+}
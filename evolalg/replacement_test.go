@@ -0,0 +1,75 @@
+package evolalg
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestReplacementStrategies(t *testing.T) {
+	for _, name := range []string{"inplace", "generational", "ss-worst", "ss-random", "ss-oldest", "plus", "comma"} {
+		r, err := ReplacementByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lambda := 0
+		switch r {
+		case SteadyStateWorst, SteadyStateRandom, SteadyStateOldest:
+			lambda = 2
+		case CommaSelection:
+			lambda = 30
+		}
+
+		gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, func(x float64) float64 {
+			return math.Mod(x, 1*(math.Cos(20*math.Pi*x)-math.Sin(x)))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		gas.SetSeed(7)
+		gas.SetReplacement(r, lambda)
+		hist, err := gas.Solve(20, 30, 0.75, 0.005)
+		if err != nil {
+			t.Log(fmt.Sprintf("%s: %s", name, err.Error()))
+			t.Fail()
+			continue
+		}
+
+		// Only the offspring are graded and the survivors (or the reinserted elite) keep their grades
+		offspring := lambda
+		if offspring == 0 {
+			offspring = 20
+		}
+		want := hist[0].Evaluations + 30*offspring
+		if evals := hist[len(hist)-1].Evaluations; evals != want {
+			t.Log(fmt.Sprintf("%s: %d evaluations were made instead of %d", name, evals, want))
+			t.Fail()
+		}
+		for i := range hist {
+			for k, x := range hist[i].PopulationF64 {
+				if hist[i].Grades[k] != LabFunction(x) {
+					t.Log(fmt.Sprintf("%s: %f is graded %f in epoch %d", name, x, hist[i].Grades[k], i))
+					t.Fail()
+					break
+				}
+			}
+			if len(hist[i].PopulationF64) != 20 {
+				t.Log(fmt.Sprintf("%s: population of epoch %d has %d individuals", name, i, len(hist[i].PopulationF64)))
+				t.Fail()
+			}
+			// plus selection and replacing the worst never lose the best individual
+			if (r == PlusSelection || r == SteadyStateWorst) && i > 0 && hist[i].FMax < hist[i-1].FMax {
+				t.Log(fmt.Sprintf("%s: fmax decreased in epoch %d", name, i))
+				t.Fail()
+			}
+		}
+	}
+
+	// comma selection can't work with fewer offspring than the population size
+	gas, _ := NewGeneticAlgorithmSolver(-4, 12, 3, math.Sin)
+	gas.SetReplacement(CommaSelection, 5)
+	if _, err := gas.Solve(10, 5, 0.75, 0.005); err == nil {
+		t.Log("comma selection with lambda < N was accepted")
+		t.Fail()
+	}
+}
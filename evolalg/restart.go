@@ -0,0 +1,220 @@
+package evolalg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// RestartPolicy describes what happens to the population when the run stagnates.
+type RestartPolicy int
+
+const (
+	// NoRestart never restarts the population. This is the default.
+	NoRestart RestartPolicy = iota
+	// FullRestart creates a new population with the initializer and keeps only the elite.
+	FullRestart
+	// PartialRestart replaces the worst Fraction of the population with new individuals.
+	PartialRestart
+	// IPOPRestart creates a new population Growth times bigger than the current one (but not bigger
+	// than MaxPopulation) and keeps only the elite.
+	IPOPRestart
+)
+
+// Restart contains the configuration of the restarts. A restart is triggered when the elite did not
+// improve for StagnationEpochs epochs or when the mean locus entropy of the population drops below
+// DiversityThreshold. Each of the triggers is disabled if set to 0.
+type Restart struct {
+	Policy             RestartPolicy
+	StagnationEpochs   int
+	DiversityThreshold float64
+	Fraction           float64 // fraction of the population replaced by PartialRestart (0.5 if 0)
+	Growth             float64 // population multiplier of IPOPRestart (2 if 0)
+	MaxPopulation      int     // population size IPOPRestart stops growing at (1000 if 0)
+}
+
+// RestartByName returns the restart policy based on its name: "none", "full", "partial" or "ipop".
+// An empty name returns NoRestart.
+func RestartByName(name string) (RestartPolicy, error) {
+	switch name {
+	case "", "none":
+		return NoRestart, nil
+	case "full":
+		return FullRestart, nil
+	case "partial":
+		return PartialRestart, nil
+	case "ipop":
+		return IPOPRestart, nil
+	}
+	return NoRestart, errors.New("unknown restart policy " + name)
+}
+
+// String returns the name of the restart policy.
+func (p RestartPolicy) String() string {
+	switch p {
+	case FullRestart:
+		return "full"
+	case PartialRestart:
+		return "partial"
+	case IPOPRestart:
+		return "ipop"
+	}
+	return "none"
+}
+
+// SetRestart sets the restart policy used by Solve.
+func (gas *GeneticAlgorithmSolver) SetRestart(rs Restart) error {
+	if rs.Policy != NoRestart && rs.StagnationEpochs <= 0 && rs.DiversityThreshold <= 0 {
+		return errors.New("restart policy needs at least one trigger")
+	} else if rs.Fraction < 0 || rs.Fraction > 1 {
+		return errors.New("fraction of the restarted population has to be in <0, 1> set")
+	} else if rs.Growth != 0 && rs.Growth < 1 {
+		return errors.New("population growth of the restarts can't be lower than 1")
+	} else if rs.MaxPopulation < 0 {
+		return errors.New("maximal population size of the restarts can't be negative")
+	}
+	if rs.Fraction == 0 {
+		rs.Fraction = 0.5
+	}
+	if rs.Growth == 0 {
+		rs.Growth = 2
+	}
+	if rs.MaxPopulation == 0 {
+		rs.MaxPopulation = 1000
+	}
+	gas.restart = rs
+	return nil
+}
+
+// restartIfStagnated updates the elite with the current population, so that a restart keeps the
+// best individual of the epoch, and restarts the population if any of the triggers fired.
+func (gas *GeneticAlgorithmSolver) restartIfStagnated() (bool, error) {
+	if gas.restart.Policy == NoRestart {
+		return false, nil
+	}
+	gas.updateElite(gas.popVals)
+	if !gas.shouldRestart() {
+		return false, nil
+	}
+	return true, gas.restartPopulation()
+}
+
+// shouldRestart checks if any of the restart triggers fired in the current epoch.
+func (gas *GeneticAlgorithmSolver) shouldRestart() bool {
+	rs := gas.restart
+	if rs.Policy == NoRestart {
+		return false
+	}
+	if rs.StagnationEpochs > 0 {
+		since := gas.eliteEpoch
+		if gas.lastRestart > since {
+			since = gas.lastRestart
+		}
+		if gas.epoch-since >= rs.StagnationEpochs {
+			return true
+		}
+	}
+	if rs.DiversityThreshold > 0 {
+		div := MeasureDiversity(gas.popArr, gas.popVals, gas.fitCache)
+		if div.MeanEntropy < rs.DiversityThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// restartPopulation replaces the population (or its part) with new individuals according to the
+// restart policy.
+func (gas *GeneticAlgorithmSolver) restartPopulation() error {
+	init := gas.initFunc
+	if init == nil {
+		init = UniformRealInit
+	}
+	N := len(gas.popVals)
+
+	var vals []float64
+	switch gas.restart.Policy {
+	case FullRestart, IPOPRestart:
+		if gas.restart.Policy == IPOPRestart {
+			grown := int(math.Ceil(float64(N) * gas.restart.Growth))
+			if limit := gas.restart.MaxPopulation; grown > limit {
+				// A population started above the limit keeps its size
+				grown = int(math.Max(float64(limit), float64(N)))
+			}
+			N = grown
+		}
+		fresh, err := init(gas, N-1)
+		if err != nil {
+			return err
+		} else if len(fresh) != N-1 {
+			return fmt.Errorf("initializer created %d individuals instead of %d", len(fresh), N-1)
+		}
+		vals = append([]float64{gas.elite}, fresh...)
+	case PartialRestart:
+		n := int(math.Round(gas.restart.Fraction * float64(N)))
+		fresh, err := init(gas, n)
+		if err != nil {
+			return err
+		} else if len(fresh) != n {
+			return fmt.Errorf("initializer created %d individuals instead of %d", len(fresh), n)
+		}
+		vals = append([]float64(nil), gas.popVals...)
+		worst := make([]int, N)
+		for i := range worst {
+			worst[i] = i
+		}
+		sort.SliceStable(worst, func(i, j int) bool {
+			return gas.fitCache[worst[i]] < gas.fitCache[worst[j]]
+		})
+		for k, i := range worst[:len(fresh)] {
+			vals[i] = fresh[k]
+		}
+	}
+
+	// Only the new individuals are graded, the survivors and the elite keep their grades
+	popArr, popAge, popID := make([][]byte, N), make([]int, N), make([]int, N)
+	grades, fits := make([]float64, N), make([]float64, N)
+	var fresh []int
+	for i := 0; i < N; i++ {
+		if i < len(gas.popVals) && vals[i] == gas.popVals[i] {
+			popArr[i], popAge[i], popID[i] = gas.popArr[i], gas.popAge[i], gas.id(i)
+			grades[i], fits[i] = gas.gradeCache[i], gas.fitCache[i]
+			continue
+		}
+		popArr[i] = gas.XIntToXBin(uint32(gas.XRealToXInt(vals[i])))
+		popAge[i] = gas.epoch
+		popID[i] = -1
+		if i == 0 && gas.restart.Policy != PartialRestart {
+			grades[i], fits[i] = gas.eliteGrade, gas.eliteFit
+		} else {
+			fresh = append(fresh, i)
+		}
+	}
+	freshVals := make([]float64, len(fresh))
+	for k, i := range fresh {
+		freshVals[k] = vals[i]
+	}
+	for k, grade := range gas.gradeAll(freshVals) {
+		grades[fresh[k]], fits[fresh[k]] = grade, gas.fit(grade)
+	}
+	gas.popVals, gas.popArr, gas.popAge = vals, popArr, popAge
+	gas.gradeCache, gas.fitCache = grades, fits
+	gas.lastRestart = gas.epoch
+	gas.roulette(vals)
+	if gas.genealogy == nil {
+		return nil
+	}
+	for i := range popID {
+		if popID[i] >= 0 {
+			continue
+		} else if i == 0 && gas.restart.Policy != PartialRestart {
+			gas.popID = popID
+			popID[i] = gas.eliteCopy(i)
+		} else {
+			popID[i] = gas.born(Individual{Operator: OpRestart, CutPoint: -1}, popArr[i], vals[i], gas.gradeCache[i])
+		}
+	}
+	gas.popID = popID
+	return nil
+}
@@ -0,0 +1,141 @@
+package evolalg
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestRestarts(t *testing.T) {
+	for _, name := range []string{"full", "partial", "ipop"} {
+		policy, err := RestartByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, func(x float64) float64 {
+			return math.Mod(x, 1*(math.Cos(20*math.Pi*x)-math.Sin(x)))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		gas.SetSeed(9)
+		err = gas.SetRestart(Restart{Policy: policy, StagnationEpochs: 3, MaxPopulation: 30})
+		if err != nil {
+			t.Fatal(err)
+		}
+		hist, err := gas.Solve(10, 30, 0.75, 0.005)
+		if err != nil {
+			t.Log(fmt.Sprintf("%s: %s", name, err.Error()))
+			t.Fail()
+			continue
+		}
+
+		restarts := 0
+		for i := 1; i < len(hist); i++ {
+			size, prevSize := len(hist[i].PopulationF64), len(hist[i-1].PopulationF64)
+			if hist[i].Restart == "" {
+				if size != prevSize {
+					t.Log(fmt.Sprintf("%s: population size changed without a restart in epoch %d", name, i))
+					t.Fail()
+				}
+				continue
+			}
+			restarts++
+			if hist[i].Restart != name {
+				t.Log(fmt.Sprintf("%s: restart reported as %s", name, hist[i].Restart))
+				t.Fail()
+			}
+			want := 2 * prevSize
+			if want > 30 {
+				want = 30
+			}
+			if policy == IPOPRestart && size != want {
+				t.Log(fmt.Sprintf("%s: population grew from %d to %d", name, prevSize, size))
+				t.Fail()
+			}
+
+			// The elite has to survive the restart
+			found := false
+			for _, x := range hist[i].PopulationF64 {
+				if x == hist[i].Elite {
+					found = true
+				}
+			}
+			if !found {
+				t.Log(fmt.Sprintf("%s: elite lost in the restart of epoch %d", name, i))
+				t.Fail()
+			}
+		}
+		if restarts == 0 {
+			t.Log(fmt.Sprintf("%s: no restarts happened", name))
+			t.Fail()
+		}
+	}
+
+	gas, _ := NewGeneticAlgorithmSolver(-4, 12, 3, math.Sin)
+	if gas.SetRestart(Restart{Policy: FullRestart}) == nil {
+		t.Log("restart without a trigger was accepted")
+		t.Fail()
+	}
+	if gas.SetRestart(Restart{Policy: IPOPRestart, StagnationEpochs: 3, MaxPopulation: -1}) == nil {
+		t.Log("negative maximal population was accepted")
+		t.Fail()
+	}
+}
+
+func TestRestartKeepsEpochBest(t *testing.T) {
+	for _, policy := range []RestartPolicy{FullRestart, PartialRestart, IPOPRestart} {
+		gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, hill)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gas.SetSeed(9)
+		err = gas.SetRestart(Restart{Policy: policy, DiversityThreshold: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = gas.Start(10, 5, 0.75, 0.005)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The optimum appears in the population of the epoch triggering the restart
+		best := gas.XIntToXReal(gas.XRealToXInt(3))
+		gas.popVals[4], gas.popArr[4] = best, gas.XIntToXBin(uint32(gas.XRealToXInt(best)))
+		gas.gradeCache[4] = gas.Grade(best)
+		gas.fitCache[4] = gas.fitFromGrade(gas.gradeCache[4])
+		evals := gas.Evaluations()
+		restarted, err := gas.restartIfStagnated()
+		if err != nil {
+			t.Fatal(err)
+		} else if !restarted || gas.elite != best {
+			t.Log(fmt.Sprintf("restart %s kept the elite %f instead of %f", policy, gas.elite, best))
+			t.Fail()
+		}
+		kept := 0
+		for i, x := range gas.popVals {
+			if x == best {
+				kept++
+			}
+			if gas.gradeCache[i] != hill(x) || gas.fitCache[i] != gas.fitFromGrade(hill(x)) {
+				t.Log(fmt.Sprintf("restart %s left %f graded %f", policy, x, gas.gradeCache[i]))
+				t.Fail()
+			}
+		}
+		if kept == 0 {
+			t.Log(fmt.Sprintf("restart %s lost %f", policy, best))
+			t.Fail()
+		}
+
+		// Only the new individuals are graded
+		fresh := len(gas.popVals) - 1
+		if policy == PartialRestart {
+			fresh = 5
+		}
+		if gas.Evaluations()-evals != fresh {
+			t.Log(fmt.Sprintf("restart %s made %d evaluations for %d new individuals", policy, gas.Evaluations()-evals,
+				fresh))
+			t.Fail()
+		}
+	}
+}
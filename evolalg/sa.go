@@ -0,0 +1,176 @@
+package evolalg
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// Cooling returns the temperature in the given epoch of a run of the given length that started
+// with the temperature t0.
+type Cooling func(t0 float64, epoch, epochs int) float64
+
+// GeometricCooling returns a schedule that multiplies the temperature by alpha in every epoch.
+func GeometricCooling(alpha float64) Cooling {
+	return func(t0 float64, epoch, epochs int) float64 {
+		return t0 * math.Pow(alpha, float64(epoch))
+	}
+}
+
+// LinearCooling lowers the temperature linearly so that it reaches zero after the last epoch.
+func LinearCooling(t0 float64, epoch, epochs int) float64 {
+	return t0 * (1 - float64(epoch)/float64(epochs+1))
+}
+
+// LogarithmicCooling lowers the temperature proportionally to the inverse of the logarithm of the
+// epoch.
+func LogarithmicCooling(t0 float64, epoch, epochs int) float64 {
+	return t0 / math.Log(math.E+float64(epoch))
+}
+
+// CoolingByName returns the cooling schedule based on its name: "geometric" (with alpha 0.95),
+// "linear" or "log". An empty name returns the geometric schedule.
+func CoolingByName(name string) (Cooling, error) {
+	switch name {
+	case "", "geometric":
+		return GeometricCooling(0.95), nil
+	case "linear":
+		return LinearCooling, nil
+	case "log":
+		return LogarithmicCooling, nil
+	}
+	return nil, errors.New("unknown cooling schedule " + name)
+}
+
+// SimulatedAnnealingSolver is a simulated annealing solver working on the same bitstring encoding
+// as GeneticAlgorithmSolver. Its history holds a population of a single (current) solution.
+type SimulatedAnnealingSolver struct {
+	gas     GeneticAlgorithmSolver // encoding, evaluation counting and the state of the search
+	cooling Cooling                // temperature schedule (GeometricCooling(0.95) if nil)
+	move    Move                   // neighbourhood move (BitFlipMove if nil)
+	moves   int                    // amount of moves tried in every epoch (1 if 0)
+	t0      float64                // initial temperature passed to Start
+}
+
+// NewSimulatedAnnealingSolver creates a new instance of a simulated annealing solver. The
+// arguments are the same as the ones of NewGeneticAlgorithmSolver.
+func NewSimulatedAnnealingSolver(a float64, b float64, d byte, gFunc func(x float64) float64) (sa SimulatedAnnealingSolver, err error) {
+	sa.gas, err = NewGeneticAlgorithmSolver(a, b, d, gFunc)
+	return
+}
+
+// SetSeed makes the solver use its own random number source seeded with the given seed so that the
+// runs can be repeated.
+func (sa *SimulatedAnnealingSolver) SetSeed(seed int64) {
+	sa.gas.SetSeed(seed)
+}
+
+// SetFailures sets the handling of failed fitness evaluations.
+func (sa *SimulatedAnnealingSolver) SetFailures(f Failures) error {
+	return sa.gas.SetFailures(f)
+}
+
+// SetContext sets the context passed to the fitness.
+func (sa *SimulatedAnnealingSolver) SetContext(ctx context.Context) {
+	sa.gas.SetContext(ctx)
+}
+
+// SetTermination sets the conditions that stop Solve before it runs for all of the epochs.
+func (sa *SimulatedAnnealingSolver) SetTermination(t Termination) {
+	sa.gas.SetTermination(t)
+}
+
+// SetCooling sets the temperature schedule.
+func (sa *SimulatedAnnealingSolver) SetCooling(c Cooling) {
+	sa.cooling = c
+}
+
+// SetMove sets the neighbourhood move and the amount of moves tried in every epoch.
+func (sa *SimulatedAnnealingSolver) SetMove(m Move, moves int) {
+	sa.move, sa.moves = m, moves
+}
+
+// Solve runs the simulated annealing for a given amount of epochs starting from the temperature t0
+// and returns a history of the algorithm's execution. The temperature of every epoch is saved in
+// the history.
+func (sa *SimulatedAnnealingSolver) Solve(epochs int, t0 float64) (hist []EpochData, err error) {
+	ed, err := sa.Start(epochs, t0)
+	if err != nil {
+		return
+	}
+	hist = append(make([]EpochData, 0, epochs+1), ed)
+	for done := epochs == 0; !done; {
+		ed, done, err = sa.Step()
+		if err != nil {
+			return
+		}
+		hist = append(hist, ed)
+	}
+	return
+}
+
+// Start initializes the search the same way as Solve and returns the history entry of the initial
+// solution. The following epochs are run with Step.
+func (sa *SimulatedAnnealingSolver) Start(epochs int, t0 float64) (ed EpochData, err error) {
+	if t0 <= 0 {
+		err = errors.New("initial temperature has to be greater than zero")
+		return
+	}
+	if sa.cooling == nil {
+		sa.cooling = GeometricCooling(0.95)
+	}
+	if sa.move == nil {
+		sa.move = BitFlipMove
+	}
+	if sa.moves <= 0 {
+		sa.moves = 1
+	}
+
+	gas := &sa.gas
+	err = gas.startSingle()
+	if err != nil {
+		return
+	}
+	sa.t0, gas.runEpochs = t0, epochs
+	err = gas.saveStateToHistory(&ed)
+	ed.Temperature = t0
+	return
+}
+
+// Step runs a single epoch of the search started with Start and returns its history entry. done is
+// set if any of the termination conditions is met or the epoch is the last one passed to Start.
+func (sa *SimulatedAnnealingSolver) Step() (ed EpochData, done bool, err error) {
+	gas := &sa.gas
+	if gas.popArr == nil {
+		err = errors.New("solver has to be started before running an epoch")
+		return
+	}
+	gas.epoch++
+	temp := sa.cooling(sa.t0, gas.epoch, gas.runEpochs)
+	for m := 0; m < sa.moves; m++ {
+		genome := sa.move(gas, gas.popArr[0])
+		x := gas.XIntToXReal(gas.XBinToXInt(genome))
+		grade := gas.Grade(x)
+
+		// Worse solutions are accepted with the Metropolis probability
+		delta := grade - gas.gradeCache[0]
+		if delta >= 0 || (temp > 0 && gas.random().Float64() < math.Exp(delta/temp)) {
+			gas.moveSingle(genome, x, grade)
+		}
+	}
+
+	err = gas.takeFailure()
+	if err != nil {
+		return
+	}
+	err = gas.saveStateToHistory(&ed)
+	if err != nil {
+		return
+	}
+	ed.Temperature = temp
+
+	// Stop early if any of the termination conditions is met
+	done = gas.epoch >= gas.runEpochs ||
+		gas.termination.Done(gas.epoch, gas.evals, gas.eliteGrade, gas.eliteEpoch)
+	return
+}
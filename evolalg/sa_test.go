@@ -0,0 +1,54 @@
+package evolalg
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// parabola has a single maximum at x = 3.
+func parabola(x float64) float64 {
+	return -(x - 3) * (x - 3)
+}
+
+func TestSimulatedAnnealing(t *testing.T) {
+	for _, name := range []string{"geometric", "linear", "log"} {
+		cooling, err := CoolingByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sa, err := NewSimulatedAnnealingSolver(-4, 12, 3, parabola)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sa.SetSeed(4)
+		sa.SetCooling(cooling)
+		sa.SetMove(BitFlipMove, 5)
+		hist, err := sa.Solve(500, 1)
+		if err != nil {
+			t.Log(fmt.Sprintf("%s: %s", name, err.Error()))
+			t.Fail()
+			continue
+		}
+
+		last := hist[len(hist)-1]
+		if math.Abs(last.Elite-3) > 0.01 {
+			t.Log(fmt.Sprintf("%s: elite %f is too far from the optimum", name, last.Elite))
+			t.Fail()
+		}
+		if len(last.PopulationBytes) != 1 || last.Evaluations != 1+500*5 {
+			t.Log(fmt.Sprintf("%s: wrong history format (%d individuals, %d evaluations)", name, len(last.PopulationBytes), last.Evaluations))
+			t.Fail()
+		}
+		if hist[1].Temperature <= last.Temperature {
+			t.Log(fmt.Sprintf("%s: temperature didn't drop", name))
+			t.Fail()
+		}
+	}
+
+	sa, _ := NewSimulatedAnnealingSolver(-4, 12, 3, parabola)
+	if _, err := sa.Solve(10, 0); err == nil {
+		t.Log("solver accepted zero initial temperature")
+		t.Fail()
+	}
+}
@@ -0,0 +1,274 @@
+package evolalg
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Solver is the common interface of the solvers registered in the package. A solver is configured
+// with named parameters described by the schema of its Algorithm and then either run as a whole
+// with Run or epoch by epoch with Step.
+type Solver interface {
+	// Configure sets the parameters of the solver and resets its history. Parameters missing from
+	// params take the defaults of the schema.
+	Configure(params Params) error
+	// Run runs the remaining epochs of the solver until it's done or ctx is cancelled and returns
+	// the whole history. A cancelled run returns the history so far and the error of ctx.
+	Run(ctx context.Context) ([]EpochData, error)
+	// Step runs a single epoch (the initialization on the first call) and returns its history
+	// entry. done is set after the last epoch.
+	Step() (ed EpochData, done bool, err error)
+	// History returns the history of the epochs run so far.
+	History() []EpochData
+}
+
+// ParamKind describes the type of the value of a parameter.
+type ParamKind int
+
+const (
+	// IntParam is an integer.
+	IntParam ParamKind = iota
+	// FloatParam is a floating point number.
+	FloatParam
+	// StringParam is a free text (e.g. a list of numbers).
+	StringParam
+	// ChoiceParam is one of the values of Choices.
+	ChoiceParam
+	// BoolParam is a flag. Any value other than "", "0" and "false" sets it.
+	BoolParam
+)
+
+// String returns the name of the kind: "int", "float", "string", "choice" or "bool".
+func (k ParamKind) String() string {
+	switch k {
+	case IntParam:
+		return "int"
+	case FloatParam:
+		return "float"
+	case ChoiceParam:
+		return "choice"
+	case BoolParam:
+		return "bool"
+	}
+	return "string"
+}
+
+// MarshalText encodes the kind as its name.
+func (k ParamKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Choice is a single value of a ChoiceParam.
+type Choice struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+// Param describes a single parameter of an algorithm. The labels are meant to be shown in forms.
+type Param struct {
+	Name    string    `json:"name"`              // key of the parameter in Params
+	Label   string    `json:"label"`             // human readable name
+	Kind    ParamKind `json:"kind"`              // type of the value
+	Default string    `json:"default,omitempty"` // value used if the parameter is missing
+	Hint    string    `json:"hint,omitempty"`    // meaning of the empty value (e.g. "l/4")
+	Choices []Choice  `json:"choices,omitempty"` // allowed values of a ChoiceParam
+}
+
+// Params contains the values of the parameters of a solver by their names.
+type Params map[string]string
+
+// resolve checks params against the schema and returns a copy with the defaults of the missing
+// parameters filled in.
+func (params Params) resolve(schema []Param) (Params, error) {
+	known := make(map[string]Param, len(schema))
+	res := make(Params, len(schema))
+	for _, p := range schema {
+		known[p.Name] = p
+		res[p.Name] = p.Default
+	}
+	for name, val := range params {
+		p, ok := known[name]
+		if !ok {
+			return nil, errors.New("unknown parameter " + name)
+		}
+		if p.Kind == ChoiceParam && val != "" {
+			found := false
+			for _, c := range p.Choices {
+				found = found || c.Value == val
+			}
+			if !found {
+				return nil, errors.New("invalid value " + val + " of parameter " + name)
+			}
+		}
+		res[name] = val
+	}
+	return res, nil
+}
+
+// Int returns the parameter as an integer (0 if it's empty).
+func (params Params) Int(name string) (int, error) {
+	if params[name] == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(params[name])
+	if err != nil {
+		return 0, errors.New("parameter " + name + " has to be an integer")
+	}
+	return v, nil
+}
+
+// Float returns the parameter as a floating point number (0 if it's empty).
+func (params Params) Float(name string) (float64, error) {
+	if params[name] == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(params[name], 64)
+	if err != nil {
+		return 0, errors.New("parameter " + name + " has to be a number")
+	}
+	return v, nil
+}
+
+// Floats returns the parameter as a comma (or semicolon) separated list of floating point numbers.
+func (params Params) Floats(name string) (vals []float64, err error) {
+	for _, field := range strings.FieldsFunc(params[name], func(r rune) bool { return r == ',' || r == ';' }) {
+		v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, errors.New("parameter " + name + " has to be a list of numbers")
+		}
+		vals = append(vals, v)
+	}
+	return
+}
+
+// Bool returns the parameter as a flag.
+func (params Params) Bool(name string) bool {
+	v := params[name]
+	return v != "" && v != "0" && v != "false"
+}
+
+// CommonParams are the parameters shared by all of the registered algorithms.
+var CommonParams = []Param{
+	{Name: "epoki", Label: "Epoki", Kind: IntParam, Default: "5"},
+	{Name: "ziarno", Label: "Ziarno", Kind: IntParam, Hint: "losowe"},
+	{Name: "max_obliczen", Label: "Limit obliczeń oceny", Kind: IntParam, Hint: "brak"},
+	{Name: "stop_stagnacja", Label: "Zatrzymanie po stagnacji (epoki)", Kind: IntParam, Hint: "brak"},
+	{Name: "cel", Label: "Docelowa ocena", Kind: FloatParam, Hint: "brak"},
+}
+
+// seededSolver is implemented by all of the solvers of the package.
+type seededSolver interface {
+	SetSeed(seed int64)
+	SetTermination(t Termination)
+}
+
+// contextSolver is implemented by the solvers passing a context to their fitness.
+type contextSolver interface {
+	SetContext(ctx context.Context)
+}
+
+// stepSolver implements Solver on top of the Start and Step methods of a solver of the package.
+type stepSolver struct {
+	schema    []Param                                     // CommonParams followed by the algorithm's own ones
+	configure func(p Params) (seededSolver, error)        // creates the solver from the resolved parameters
+	start     func(epochs int) (EpochData, error)         // starts the configured solver
+	step      func() (ed EpochData, done bool, err error) // runs a single epoch of the started solver
+
+	solver seededSolver // configured solver
+	params Params       // resolved parameters (nil if the solver wasn't configured)
+	hist   []EpochData  // history of the epochs run so far
+	done   bool         // the last epoch was run
+}
+
+// Configure sets the parameters of the solver and resets its history.
+func (s *stepSolver) Configure(params Params) error {
+	p, err := params.resolve(s.schema)
+	if err != nil {
+		return err
+	}
+	epochs, err := p.Int("epoki")
+	if err != nil {
+		return err
+	} else if epochs < 0 {
+		return errors.New("amount of epochs can't be negative")
+	}
+	var term Termination
+	term.MaxEvaluations, err = p.Int("max_obliczen")
+	if err != nil {
+		return err
+	}
+	term.StagnationEpochs, err = p.Int("stop_stagnacja")
+	if err != nil {
+		return err
+	}
+	if p["cel"] != "" {
+		term.Target, err = p.Float("cel")
+		if err != nil {
+			return err
+		}
+		term.HasTarget = true
+	}
+
+	solver, err := s.configure(p)
+	if err != nil {
+		return err
+	}
+	if p["ziarno"] != "" {
+		seed, err := strconv.ParseInt(p["ziarno"], 10, 64)
+		if err != nil {
+			return errors.New("parameter ziarno has to be an integer")
+		}
+		solver.SetSeed(seed)
+	}
+	solver.SetTermination(term)
+	s.solver, s.params, s.hist, s.done = solver, p, nil, false
+	return nil
+}
+
+// Step runs a single epoch of the solver.
+func (s *stepSolver) Step() (ed EpochData, done bool, err error) {
+	if s.params == nil {
+		return ed, false, errors.New("solver has to be configured before running it")
+	} else if s.done {
+		return s.hist[len(s.hist)-1], true, nil
+	}
+	if s.hist == nil {
+		epochs, _ := s.params.Int("epoki")
+		ed, err = s.start(epochs)
+		done = epochs == 0
+	} else {
+		ed, done, err = s.step()
+	}
+	if err != nil {
+		return
+	}
+	s.hist = append(s.hist, ed)
+	s.done = done
+	return
+}
+
+// Run runs the remaining epochs of the solver. The context is also passed to the fitness of the
+// solvers that accept it.
+func (s *stepSolver) Run(ctx context.Context) ([]EpochData, error) {
+	if cs, ok := s.solver.(contextSolver); ok {
+		cs.SetContext(ctx)
+		defer cs.SetContext(nil)
+	}
+	for !s.done {
+		if err := ctx.Err(); err != nil {
+			return s.hist, err
+		}
+		_, _, err := s.Step()
+		if err != nil {
+			return s.hist, err
+		}
+	}
+	return s.hist, nil
+}
+
+// History returns the history of the epochs run so far.
+func (s *stepSolver) History() []EpochData {
+	return s.hist
+}
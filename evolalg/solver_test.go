@@ -0,0 +1,142 @@
+package evolalg
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParams(t *testing.T) {
+	schema := []Param{
+		{Name: "n", Kind: IntParam, Default: "3"},
+		{Name: "x", Kind: FloatParam},
+		{Name: "c", Kind: ChoiceParam, Default: "a", Choices: []Choice{{"a", "A"}, {"b", "B"}}},
+		{Name: "f", Kind: BoolParam},
+		{Name: "l", Kind: StringParam},
+	}
+	p, err := Params{"x": "0.5", "c": "b", "f": "on", "l": "1, 2;3"}.resolve(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := p.Int("n")
+	if err != nil || n != 3 {
+		t.Log(fmt.Sprintf("default of n is %d (%v) instead of 3", n, err))
+		t.Fail()
+	}
+	x, err := p.Float("x")
+	if err != nil || x != 0.5 {
+		t.Log(fmt.Sprintf("x is %f (%v) instead of 0.5", x, err))
+		t.Fail()
+	}
+	if p["c"] != "b" || !p.Bool("f") {
+		t.Log(fmt.Sprintf("resolved parameters %v don't keep the given values", p))
+		t.Fail()
+	}
+	l, err := p.Floats("l")
+	if err != nil || !reflect.DeepEqual(l, []float64{1, 2, 3}) {
+		t.Log(fmt.Sprintf("l is %v (%v) instead of [1 2 3]", l, err))
+		t.Fail()
+	}
+
+	for _, bad := range []Params{{"unknown": "1"}, {"c": "z"}} {
+		if _, err := bad.resolve(schema); err == nil {
+			t.Log(fmt.Sprintf("%v was accepted", bad))
+			t.Fail()
+		}
+	}
+	if _, err := (Params{"n": "x"}).Int("n"); err == nil {
+		t.Log("non-integer value was accepted")
+		t.Fail()
+	}
+}
+
+func TestSolverStepAndRun(t *testing.T) {
+	p := Problem{Lower: []float64{-4}, Upper: []float64{12}, F: func(x []float64) float64 { return LabFunction(x[0]) }}
+	for _, a := range Algorithms() {
+		params := Params{"epoki": "10", "ziarno": "5"}
+
+		// Run and Step should produce the same history for the same seed
+		s, err := a.New(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := s.Step(); err == nil {
+			t.Log(fmt.Sprintf("%s: unconfigured solver was run", a.Name))
+			t.Fail()
+		}
+		err = s.Configure(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hist, err := s.Run(context.Background())
+		if err != nil {
+			t.Log(fmt.Sprintf("%s: %s", a.Name, err.Error()))
+			t.Fail()
+			continue
+		}
+		if len(hist) != 11 || len(s.History()) != 11 {
+			t.Log(fmt.Sprintf("%s: history has %d entries instead of 11", a.Name, len(hist)))
+			t.Fail()
+		}
+
+		s2, _ := a.New(p)
+		err = s2.Configure(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		steps := 0
+		for done := false; !done; steps++ {
+			var ed EpochData
+			ed, done, err = s2.Step()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ed.EliteFit != hist[steps].EliteFit || ed.Evaluations != hist[steps].Evaluations {
+				t.Log(fmt.Sprintf("%s: epoch %d differs from the one of Run", a.Name, steps))
+				t.Fail()
+			}
+		}
+		if steps != 11 {
+			t.Log(fmt.Sprintf("%s: %d steps instead of 11", a.Name, steps))
+			t.Fail()
+		}
+	}
+}
+
+func TestSolverTermination(t *testing.T) {
+	p := Problem{Lower: []float64{-5, -5}, Upper: []float64{5, 5}, F: sphere}
+	a, err := AlgorithmByName("de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := a.New(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Configure(Params{"epoki": "100", "max_obliczen": "100", "N": "10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hist, err := s.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hist) != 10 {
+		t.Log(fmt.Sprintf("run stopped after %d epochs instead of 9", len(hist)-1))
+		t.Fail()
+	}
+
+	// A cancelled context stops the run
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = s.Configure(Params{"epoki": "100"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hist, err = s.Run(ctx)
+	if err != context.Canceled || len(hist) != 0 {
+		t.Log(fmt.Sprintf("cancelled run returned %d epochs and error %v", len(hist), err))
+		t.Fail()
+	}
+}
@@ -0,0 +1,209 @@
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// maxExact is the largest sample for which the tests compute the exact distribution of their
+// statistic. Larger samples and samples with ties use the normal approximation.
+const maxExact = 25
+
+// TestResult is the outcome of a two-sided test of the hypothesis that two samples come from the same
+// distribution.
+type TestResult struct {
+	Statistic float64 `json:"statistic"`
+	P         float64 `json:"p"`     // two-sided p-value
+	Exact     bool    `json:"exact"` // the p-value is exact instead of approximated
+}
+
+// MannWhitney runs the Mann-Whitney U test of two independent samples. The statistic is U of the
+// first sample: the amount of the pairs in which its value is higher (ties count as a half).
+func MannWhitney(a, b []float64) (res TestResult, err error) {
+	n1, n2 := len(a), len(b)
+	if n1 == 0 || n2 == 0 {
+		err = errors.New("samples can't be empty")
+		return
+	}
+	r, ties := ranks(append(append([]float64(nil), a...), b...))
+	r1 := 0.0
+	for i := 0; i < n1; i++ {
+		r1 += r[i]
+	}
+	u := r1 - float64(n1*(n1+1))/2
+	res.Statistic = u
+
+	if ties == 0 && n1 <= maxExact && n2 <= maxExact {
+		res.P, res.Exact = exactP(mannWhitneyCounts(n1, n2), u), true
+		return
+	}
+	n := float64(n1 + n2)
+	mean := float64(n1*n2) / 2
+	variance := float64(n1*n2) / 12 * (n + 1 - ties/(n*(n-1)))
+	res.P = normalP(u, mean, variance)
+	return
+}
+
+// mannWhitneyCounts returns the amount of the orderings of n1 and n2 distinct values giving every
+// value of U. The orderings are built by appending the values in the ascending order: appending a
+// value of the first sample after j values of the second one increases U by j.
+func mannWhitneyCounts(n1, n2 int) []float64 {
+	maxU := n1 * n2
+	// dp[i][j][u] for i values of the first sample and j of the second one
+	dp := make([][][]float64, n1+1)
+	for i := range dp {
+		dp[i] = make([][]float64, n2+1)
+		for j := range dp[i] {
+			dp[i][j] = make([]float64, maxU+1)
+		}
+	}
+	dp[0][0][0] = 1
+	for i := 0; i <= n1; i++ {
+		for j := 0; j <= n2; j++ {
+			for u, c := range dp[i][j] {
+				if c == 0 {
+					continue
+				}
+				if i < n1 {
+					dp[i+1][j][u+j] += c
+				}
+				if j < n2 {
+					dp[i][j+1][u] += c
+				}
+			}
+		}
+	}
+	return dp[n1][n2]
+}
+
+// Wilcoxon runs the Wilcoxon signed-rank test of paired samples. The pairs with equal values are
+// dropped. The statistic is the sum of the ranks of the positive differences a[i] - b[i].
+func Wilcoxon(a, b []float64) (res TestResult, err error) {
+	if len(a) != len(b) {
+		err = errors.New("paired samples have to be of equal length")
+		return
+	} else if len(a) == 0 {
+		err = errors.New("samples can't be empty")
+		return
+	}
+	var diffs, abs []float64
+	for i := range a {
+		if d := a[i] - b[i]; d != 0 {
+			diffs = append(diffs, d)
+			abs = append(abs, math.Abs(d))
+		}
+	}
+	n := len(diffs)
+	if n == 0 {
+		res.P, res.Exact = 1, true
+		return
+	}
+	r, ties := ranks(abs)
+	w := 0.0
+	for i, d := range diffs {
+		if d > 0 {
+			w += r[i]
+		}
+	}
+	res.Statistic = w
+
+	if ties == 0 && n <= maxExact {
+		// counts[s] is the amount of the subsets of {1, ..., n} summing to s
+		counts := make([]float64, n*(n+1)/2+1)
+		counts[0] = 1
+		for k := 1; k <= n; k++ {
+			for s := len(counts) - 1; s >= k; s-- {
+				counts[s] += counts[s-k]
+			}
+		}
+		res.P, res.Exact = exactP(counts, w), true
+		return
+	}
+	fn := float64(n)
+	mean := fn * (fn + 1) / 4
+	variance := fn*(fn+1)*(2*fn+1)/24 - ties/48
+	res.P = normalP(w, mean, variance)
+	return
+}
+
+// exactP returns the two-sided p-value of the statistic x given the amount of the outcomes giving
+// every integer value of the statistic.
+func exactP(counts []float64, x float64) float64 {
+	total, lower, upper := 0.0, 0.0, 0.0
+	for v, c := range counts {
+		total += c
+		if float64(v) <= x {
+			lower += c
+		}
+		if float64(v) >= x {
+			upper += c
+		}
+	}
+	return math.Min(1, 2*math.Min(lower, upper)/total)
+}
+
+// normalP returns the two-sided p-value of the statistic x approximated by the normal distribution
+// with the continuity correction.
+func normalP(x, mean, variance float64) float64 {
+	if variance <= 0 {
+		return 1
+	}
+	d := math.Max(math.Abs(x-mean)-0.5, 0)
+	return math.Min(1, 2*(1-NormalCDF(d/math.Sqrt(variance))))
+}
+
+// FriedmanResult is the outcome of the Friedman test.
+type FriedmanResult struct {
+	Ranks     []float64 `json:"ranks"`     // mean rank of every treatment (1 is the best)
+	Statistic float64   `json:"statistic"` // chi-squared statistic corrected for ties
+	P         float64   `json:"p"`
+}
+
+// Friedman runs the Friedman test of k treatments (e.g. algorithms) measured in every block (e.g. a
+// function and a seed). blocks[i][j] is the value of the treatment j in the block i. The treatments
+// are ranked in every block from the highest value down.
+func Friedman(blocks [][]float64) (res FriedmanResult, err error) {
+	if len(blocks) < 2 {
+		err = errors.New("test needs at least two blocks")
+		return
+	}
+	k := len(blocks[0])
+	if k < 2 {
+		err = errors.New("test needs at least two treatments")
+		return
+	}
+	sums := make([]float64, k)
+	ties := 0.0
+	for _, block := range blocks {
+		if len(block) != k {
+			err = errors.New("all blocks have to contain every treatment")
+			return
+		}
+		neg := make([]float64, k)
+		for j, v := range block {
+			neg[j] = -v
+		}
+		r, t := ranks(neg)
+		for j := range r {
+			sums[j] += r[j]
+		}
+		ties += t
+	}
+
+	b, fk := float64(len(blocks)), float64(k)
+	sq := 0.0
+	res.Ranks = make([]float64, k)
+	for j, s := range sums {
+		res.Ranks[j] = s / b
+		sq += s * s
+	}
+	chi := 12/(b*fk*(fk+1))*sq - 3*b*(fk+1)
+	if c := 1 - ties/(b*fk*(fk*fk-1)); c > 0 {
+		chi /= c
+	} else {
+		chi = 0 // all of the treatments are tied in every block
+	}
+	res.Statistic = chi
+	res.P = ChiSquareSF(chi, fk-1)
+	return
+}
@@ -0,0 +1,99 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestMannWhitney(t *testing.T) {
+	res, err := MannWhitney([]float64{1, 2, 3}, []float64{4, 5, 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Statistic != 0 || !res.Exact || math.Abs(res.P-0.1) > 1e-12 {
+		t.Log(fmt.Sprintf("invalid result %+v", res))
+		t.Fail()
+	}
+
+	// Exact p-value of U = 10 for samples of 5 and 6 values
+	res, _ = MannWhitney([]float64{1, 3, 5, 7, 9}, []float64{2, 4, 6, 8, 10, 11})
+	if res.Statistic != 10 || math.Abs(res.P-0.4285714) > 1e-6 {
+		t.Log(fmt.Sprintf("invalid result %+v", res))
+		t.Fail()
+	}
+
+	// Large samples and ties use the normal approximation
+	var a, b []float64
+	for i := 0; i < 30; i++ {
+		a, b = append(a, float64(i)), append(b, float64(i+20))
+	}
+	res, _ = MannWhitney(a, b)
+	if res.Exact || res.P > 1e-3 || res.Statistic != 50 {
+		t.Log(fmt.Sprintf("invalid result %+v", res))
+		t.Fail()
+	}
+	res, _ = MannWhitney([]float64{1, 1, 1}, []float64{1, 1, 1})
+	if res.P != 1 {
+		t.Log(fmt.Sprintf("equal samples differ with p=%f", res.P))
+		t.Fail()
+	}
+	if _, err := MannWhitney(nil, a); err == nil {
+		t.Log("empty sample was accepted")
+		t.Fail()
+	}
+}
+
+func TestWilcoxon(t *testing.T) {
+	res, err := Wilcoxon([]float64{2, 4, 6, 8, 10}, []float64{1, 2, 3, 4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Statistic != 15 || !res.Exact || math.Abs(res.P-0.0625) > 1e-12 {
+		t.Log(fmt.Sprintf("invalid result %+v", res))
+		t.Fail()
+	}
+
+	// Differences -1, 2, 3, -4, 5, 6 with W+ = 16 of 21
+	res, _ = Wilcoxon([]float64{0, 2, 3, 0, 5, 6}, []float64{1, 0, 0, 4, 0, 0})
+	if res.Statistic != 16 || math.Abs(res.P-0.3125) > 1e-12 {
+		t.Log(fmt.Sprintf("invalid result %+v", res))
+		t.Fail()
+	}
+
+	res, _ = Wilcoxon([]float64{1, 2}, []float64{1, 2})
+	if res.P != 1 {
+		t.Log(fmt.Sprintf("equal pairs differ with p=%f", res.P))
+		t.Fail()
+	}
+	if _, err := Wilcoxon([]float64{1}, []float64{1, 2}); err == nil {
+		t.Log("samples of different lengths were accepted")
+		t.Fail()
+	}
+}
+
+func TestFriedman(t *testing.T) {
+	blocks := [][]float64{{3, 2, 1}, {9, 5, 4}, {0.3, 0.2, 0.1}, {7, 6, 5}}
+	res, err := Friedman(blocks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Ranks[0] != 1 || res.Ranks[1] != 2 || res.Ranks[2] != 3 {
+		t.Log(fmt.Sprintf("mean ranks are %v", res.Ranks))
+		t.Fail()
+	}
+	if math.Abs(res.Statistic-8) > 1e-9 || math.Abs(res.P-math.Exp(-4)) > 1e-9 {
+		t.Log(fmt.Sprintf("invalid result %+v", res))
+		t.Fail()
+	}
+
+	res, _ = Friedman([][]float64{{1, 1}, {2, 2}})
+	if res.Statistic != 0 || res.P != 1 {
+		t.Log(fmt.Sprintf("tied treatments differ: %+v", res))
+		t.Fail()
+	}
+	if _, err := Friedman([][]float64{{1, 2}, {1}}); err == nil {
+		t.Log("incomplete block was accepted")
+		t.Fail()
+	}
+}
@@ -0,0 +1,144 @@
+package stats
+
+import "math"
+
+// NormalCDF returns the cumulative distribution function of the standard normal distribution.
+func NormalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+// StudentTCDF returns the cumulative distribution function of Student's t distribution with df
+// degrees of freedom.
+func StudentTCDF(t, df float64) float64 {
+	p := 0.5 * regIncBeta(df/2, 0.5, df/(df+t*t))
+	if t > 0 {
+		return 1 - p
+	}
+	return p
+}
+
+// StudentTQuantile returns the p-quantile of Student's t distribution with df degrees of freedom.
+func StudentTQuantile(p, df float64) float64 {
+	if p == 0.5 {
+		return 0
+	}
+	// The CDF is increasing, so the quantile is found by bisection after bracketing it
+	lo, hi := -1.0, 1.0
+	for StudentTCDF(lo, df) > p {
+		lo *= 2
+	}
+	for StudentTCDF(hi, df) < p {
+		hi *= 2
+	}
+	for i := 0; i < 200 && hi-lo > 1e-12; i++ {
+		mid := (lo + hi) / 2
+		if StudentTCDF(mid, df) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// ChiSquareSF returns the survival function (upper tail probability) of the chi-squared distribution
+// with k degrees of freedom.
+func ChiSquareSF(x, k float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return regIncGammaQ(k/2, x/2)
+}
+
+// regIncBeta returns the regularized incomplete beta function I_x(a, b).
+func regIncBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	} else if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+	// The continued fraction converges quickly only below the mean, so the symmetry
+	// I_x(a, b) = 1 - I_(1-x)(b, a) is used above it
+	if x < (a+1)/(a+b+2) {
+		return front * betaFraction(a, b, x) / a
+	}
+	return 1 - front*betaFraction(b, a, 1-x)/b
+}
+
+// betaFraction evaluates the continued fraction of the incomplete beta function by the modified
+// Lentz's method.
+func betaFraction(a, b, x float64) float64 {
+	const tiny = 1e-300
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= 300; m++ {
+		fm := float64(m)
+		for _, num := range []float64{
+			fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm)),
+			-(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1)),
+		} {
+			d = 1 + num*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + num/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			h *= d * c
+		}
+		if math.Abs(d*c-1) < 1e-15 {
+			break
+		}
+	}
+	return h
+}
+
+// regIncGammaQ returns the regularized upper incomplete gamma function Q(a, x).
+func regIncGammaQ(a, x float64) float64 {
+	lga, _ := math.Lgamma(a)
+	if x < a+1 {
+		// series of P(a, x)
+		sum, term := 1/a, 1/a
+		for n := 1; n <= 1000; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return 1 - sum*math.Exp(-x+a*math.Log(x)-lga)
+	}
+	// continued fraction of Q(a, x) (modified Lentz's method)
+	const tiny = 1e-300
+	b := x + 1 - a
+	c, d := 1/tiny, 1/b
+	h := d
+	for n := 1; n <= 1000; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+		if math.Abs(d*c-1) < 1e-15 {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lga) * h
+}
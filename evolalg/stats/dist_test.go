@@ -0,0 +1,32 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestDistributions(t *testing.T) {
+	cases := []struct {
+		name      string
+		got, want float64
+	}{
+		{"Φ(1.96)", NormalCDF(1.96), 0.9750021},
+		{"Φ(-1)", NormalCDF(-1), 0.1586553},
+		{"t(2.228139; 10)", StudentTCDF(2.228139, 10), 0.975},
+		{"t(-1; 3)", StudentTCDF(-1, 3), 0.1955011},
+		{"t quantile 0.975; 10", StudentTQuantile(0.975, 10), 2.228139},
+		{"t quantile 0.995; 4", StudentTQuantile(0.995, 4), 4.604095},
+		{"t quantile 0.05; 30", StudentTQuantile(0.05, 30), -1.697261},
+		{"χ²(3.841459; 1)", ChiSquareSF(3.841459, 1), 0.05},
+		{"χ²(5.991465; 2)", ChiSquareSF(5.991465, 2), 0.05},
+		{"χ²(18.307038; 10)", ChiSquareSF(18.307038, 10), 0.05},
+		{"χ²(1; 5)", ChiSquareSF(1, 5), 0.9625658},
+	}
+	for _, c := range cases {
+		if math.Abs(c.got-c.want) > 1e-6 {
+			t.Log(fmt.Sprintf("%s = %f instead of %f", c.name, c.got, c.want))
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package stats
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TheSlipper/isa/evolalg/sweep"
+)
+
+// Group contains the runs of a single configuration of a sweep on a single function.
+type Group struct {
+	Label    string            // source of the runs (e.g. the algorithm or the file of the results)
+	Function string            // name of the benchmark function
+	Dim      int               // dimensionality of the function
+	Params   map[string]string // values of the swept parameters
+	Runs     []sweep.Result    // runs in the order of their repetitions
+}
+
+// Name returns the label and the parameters of the group.
+func (g Group) Name() string {
+	var parts []string
+	if g.Label != "" {
+		parts = append(parts, g.Label)
+	}
+	for _, name := range sortedKeys(g.Params) {
+		parts = append(parts, name+"="+g.Params[name])
+	}
+	return strings.Join(parts, " ")
+}
+
+// Best returns the best grades of the runs.
+func (g Group) Best() []float64 {
+	xs := make([]float64, len(g.Runs))
+	for i, r := range g.Runs {
+		xs[i] = r.Best
+	}
+	return xs
+}
+
+// GroupResults splits the results of a sweep labelled with label into the groups of the runs of
+// every configuration. The groups keep the order in which their configurations first appear and
+// their runs are sorted by the repetitions.
+func GroupResults(label string, results []sweep.Result) []Group {
+	var groups []Group
+	index := make(map[string]int)
+	for _, r := range results {
+		k := r.Function + "|" + strconv.Itoa(r.Dim)
+		for _, name := range sortedKeys(r.Params) {
+			k += "|" + name + "=" + r.Params[name]
+		}
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, Group{Label: label, Function: r.Function, Dim: r.Dim, Params: r.Params})
+		}
+		groups[i].Runs = append(groups[i].Runs, r)
+	}
+	for _, g := range groups {
+		runs := g.Runs
+		sort.SliceStable(runs, func(i, j int) bool { return runs[i].Repetition < runs[j].Repetition })
+	}
+	return groups
+}
+
+// Blocks arranges the best grades of the configurations (groups of equal names on different
+// functions) into the blocks of the Friedman test, which are also the pairs of the Wilcoxon test. A
+// block is a function with a repetition and it's included only if every configuration was run in it.
+// names are the names of the configurations in the order of the columns of the blocks.
+func Blocks(groups []Group) (names []string, blocks [][]float64, err error) {
+	type block struct {
+		function   string
+		dim        int
+		repetition int
+	}
+	columns := make(map[string]int)
+	for _, g := range groups {
+		if _, ok := columns[g.Name()]; !ok {
+			columns[g.Name()] = len(names)
+			names = append(names, g.Name())
+		}
+	}
+
+	var order []block
+	values := make(map[block][]float64)
+	missing := make(map[block]int) // amount of the configurations not run in the block
+	for _, g := range groups {
+		j := columns[g.Name()]
+		for _, r := range g.Runs {
+			b := block{g.Function, g.Dim, r.Repetition}
+			if values[b] == nil {
+				order = append(order, b)
+				values[b] = make([]float64, len(names))
+				missing[b] = len(names)
+			}
+			values[b][j] = r.Best
+			missing[b]--
+		}
+	}
+	for _, b := range order {
+		if missing[b] == 0 {
+			blocks = append(blocks, values[b])
+		}
+	}
+	if len(blocks) == 0 {
+		err = errors.New("no function and repetition was run with every configuration")
+	}
+	return
+}
+
+// sortedKeys returns the keys of the map in the alphabetical order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,41 @@
+package stats
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TheSlipper/isa/evolalg/sweep"
+)
+
+func TestGroups(t *testing.T) {
+	var results []sweep.Result
+	for _, fn := range []string{"lab", "deb1"} {
+		for _, n := range []string{"10", "20"} {
+			for rep := 2; rep >= 0; rep-- {
+				if fn == "deb1" && n == "20" && rep == 2 {
+					continue // block missing a configuration
+				}
+				results = append(results, sweep.Result{Function: fn, Dim: 1, Params: map[string]string{"N": n},
+					Repetition: rep, Best: float64(rep)})
+			}
+		}
+	}
+
+	groups := GroupResults("ga", results)
+	if len(groups) != 4 {
+		t.Fatal(fmt.Sprintf("%d groups instead of 4", len(groups)))
+	}
+	if groups[0].Name() != "ga N=10" || groups[0].Runs[0].Repetition != 0 || len(groups[3].Runs) != 2 {
+		t.Log(fmt.Sprintf("invalid first group %s %v", groups[0].Name(), groups[0].Runs))
+		t.Fail()
+	}
+
+	names, blocks, err := Blocks(groups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || len(blocks) != 5 {
+		t.Log(fmt.Sprintf("%d configurations in %d blocks instead of 2 in 5", len(names), len(blocks)))
+		t.Fail()
+	}
+}
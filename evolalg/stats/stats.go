@@ -0,0 +1,110 @@
+// Package stats analyses the results of repeated runs of the solvers: it summarizes the samples of
+// the best grades of every configuration and compares the configurations with the nonparametric
+// tests of Mann-Whitney, Wilcoxon and Friedman. Following evolalg, higher values are better.
+package stats
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Summary contains the descriptive statistics of a sample.
+type Summary struct {
+	N      int     `json:"n"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	SD     float64 `json:"sd"`     // sample standard deviation
+	Best   float64 `json:"best"`   // highest value
+	Worst  float64 `json:"worst"`  // lowest value
+	CILow  float64 `json:"ciLow"`  // lower bound of the confidence interval of the mean
+	CIHigh float64 `json:"ciHigh"` // upper bound of the confidence interval of the mean
+}
+
+// Summarize returns the descriptive statistics of the sample with the confidence interval of the
+// mean at the given level (0.95 if 0) based on Student's t distribution.
+func Summarize(xs []float64, confidence float64) (s Summary, err error) {
+	if len(xs) == 0 {
+		err = errors.New("sample is empty")
+		return
+	} else if confidence < 0 || confidence >= 1 {
+		err = errors.New("confidence level has to be in <0, 1) set")
+		return
+	} else if confidence == 0 {
+		confidence = 0.95
+	}
+
+	s.N = len(xs)
+	s.Best, s.Worst = xs[0], xs[0]
+	for _, x := range xs {
+		s.Mean += x
+		s.Best = math.Max(s.Best, x)
+		s.Worst = math.Min(s.Worst, x)
+	}
+	s.Mean /= float64(s.N)
+	s.Median = Median(xs)
+	s.CILow, s.CIHigh = s.Mean, s.Mean
+	if s.N > 1 {
+		for _, x := range xs {
+			s.SD += (x - s.Mean) * (x - s.Mean)
+		}
+		s.SD = math.Sqrt(s.SD / float64(s.N-1))
+		half := StudentTQuantile(1-(1-confidence)/2, float64(s.N-1)) * s.SD / math.Sqrt(float64(s.N))
+		s.CILow, s.CIHigh = s.Mean-half, s.Mean+half
+	}
+	return
+}
+
+// Median returns the median of the sample (NaN if it's empty).
+func Median(xs []float64) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// SuccessRate returns the fraction of the values that reached the target.
+func SuccessRate(xs []float64, target float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	hits := 0
+	for _, x := range xs {
+		if x >= target {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(xs))
+}
+
+// ranks returns the ranks (starting with 1) of the values in the ascending order with the ties
+// given their average rank, and the sum of t^3 - t over the groups of t tied values used by the
+// tie corrections of the tests.
+func ranks(xs []float64) (r []float64, ties float64) {
+	idx := make([]int, len(xs))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return xs[idx[i]] < xs[idx[j]] })
+	r = make([]float64, len(xs))
+	for i := 0; i < len(idx); {
+		j := i + 1
+		for j < len(idx) && xs[idx[j]] == xs[idx[i]] {
+			j++
+		}
+		avg := float64(i+j+1) / 2 // average of the ranks i+1, ..., j
+		for k := i; k < j; k++ {
+			r[idx[k]] = avg
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+	return
+}
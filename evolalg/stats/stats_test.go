@@ -0,0 +1,55 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	s, err := Summarize([]float64{2, 4, 4, 4, 5, 5, 7, 9}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	half := 2.364624 * math.Sqrt(32.0/7) / math.Sqrt(8)
+	if s.N != 8 || s.Mean != 5 || s.Median != 4.5 || s.Best != 9 || s.Worst != 2 ||
+		math.Abs(s.SD-math.Sqrt(32.0/7)) > 1e-9 || math.Abs(s.CILow-(5-half)) > 1e-5 || math.Abs(s.CIHigh-(5+half)) > 1e-5 {
+		t.Log(fmt.Sprintf("invalid summary %+v", s))
+		t.Fail()
+	}
+
+	s, _ = Summarize([]float64{3}, 0.99)
+	if s.SD != 0 || s.CILow != 3 || s.CIHigh != 3 || s.Median != 3 {
+		t.Log(fmt.Sprintf("invalid summary of a single value %+v", s))
+		t.Fail()
+	}
+	if _, err := Summarize(nil, 0); err == nil {
+		t.Log("empty sample was summarized")
+		t.Fail()
+	}
+	if _, err := Summarize([]float64{1}, 1); err == nil {
+		t.Log("confidence level of 1 was accepted")
+		t.Fail()
+	}
+
+	if r := SuccessRate([]float64{0.5, 1, 1.5, 2}, 1); r != 0.75 {
+		t.Log(fmt.Sprintf("success rate is %f instead of 0.75", r))
+		t.Fail()
+	}
+}
+
+func TestRanks(t *testing.T) {
+	r, ties := ranks([]float64{3, 1, 2, 2})
+	want := []float64{4, 1, 2.5, 2.5}
+	for i := range want {
+		if r[i] != want[i] {
+			t.Log(fmt.Sprintf("ranks are %v instead of %v", r, want))
+			t.Fail()
+			break
+		}
+	}
+	if ties != 6 {
+		t.Log(fmt.Sprintf("tie sum is %f instead of 6", ties))
+		t.Fail()
+	}
+}
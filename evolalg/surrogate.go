@@ -0,0 +1,442 @@
+package evolalg
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// SurrogateModel describes the model that predicts the grades of the individuals so that only the
+// most promising ones are graded with the real (expensive) grading function.
+type SurrogateModel int
+
+const (
+	// NoSurrogate grades every individual with the grading function. This is the default.
+	NoSurrogate SurrogateModel = iota
+	// RadialBasis interpolates the archived grades with cubic radial basis functions and a linear
+	// polynomial.
+	RadialBasis
+	// NearestNeighbours predicts the inverse distance weighted mean of the grades of the nearest
+	// archived individuals.
+	NearestNeighbours
+	// GaussianProcess predicts the posterior mean of a Gaussian process with a squared exponential
+	// kernel. The individuals are ranked by the mean plus the standard deviation so that the
+	// uncertain ones get a chance as well.
+	GaussianProcess
+)
+
+// SurrogateModelByName returns the surrogate model based on its name: "none", "rbf", "knn" or "gp".
+// An empty name returns the default one.
+func SurrogateModelByName(name string) (SurrogateModel, error) {
+	switch name {
+	case "", "none":
+		return NoSurrogate, nil
+	case "rbf":
+		return RadialBasis, nil
+	case "knn":
+		return NearestNeighbours, nil
+	case "gp":
+		return GaussianProcess, nil
+	}
+	return NoSurrogate, errors.New("unknown surrogate model " + name)
+}
+
+// Surrogate configures the pre-screening of the individuals with a surrogate model. The model is
+// fitted to the archive of the real grades. Individuals that were already graded or predicted reuse
+// their grade and out of the others only the Fraction with the best predictions is graded, the rest
+// keep the predicted grades. After every epoch the Fraction of the predicted individuals of the
+// population with the highest grades is graded as well, so that overestimated individuals don't
+// stay in the population. Only the individuals with real grades can become the elite.
+type Surrogate struct {
+	Model      SurrogateModel
+	Fraction   float64 // share of the new individuals graded with the grading function (0.2 if 0)
+	Neighbours int     // amount of neighbours of NearestNeighbours (5 if 0)
+	Archive    int     // amount of the latest real grades the model is fitted to (100 if 0)
+	Width      float64 // width of the kernel of GaussianProcess as a share of the <a,b> set (0.1 if 0)
+}
+
+// minArchive is the amount of real grades needed before the model makes any predictions.
+const minArchive = 10
+
+// SetSurrogate sets the pre-screening of the individuals with a surrogate model. The model needs a
+// deterministic grading function, so it can't be used together with SetObjective or SetResampling.
+func (gas *GeneticAlgorithmSolver) SetSurrogate(s Surrogate) error {
+	if s.Fraction < 0 || s.Fraction > 1 {
+		return errors.New("fraction of the real grades has to be in <0,1> set")
+	} else if s.Neighbours < 0 || s.Archive < 0 || s.Width < 0 {
+		return errors.New("parameters of the surrogate model can't be negative")
+	}
+	if s.Fraction == 0 {
+		s.Fraction = 0.2
+	}
+	if s.Neighbours == 0 {
+		s.Neighbours = 5
+	}
+	if s.Archive == 0 {
+		s.Archive = 100
+	}
+	if s.Width == 0 {
+		s.Width = 0.1
+	}
+	gas.surrogate = s
+	return nil
+}
+
+// SavedEvaluations returns the number of individuals whose grade was predicted by the surrogate model
+// instead of being evaluated since the start of the last Solve.
+func (gas GeneticAlgorithmSolver) SavedEvaluations() int {
+	return gas.saved
+}
+
+// resetSurrogate forgets the archive of the previous run and checks if the surrogate model can be
+// used with the grading function.
+func (gas *GeneticAlgorithmSolver) resetSurrogate() error {
+	gas.archive, gas.archiveX, gas.predicted, gas.saved = nil, nil, nil, 0
+	if gas.surrogate.Model == NoSurrogate {
+		return nil
+	} else if gas.objective != nil || gas.samples > 1 {
+		return errors.New("surrogate model needs a deterministic grading function")
+	}
+	gas.archive = make(map[float64]float64)
+	gas.predicted = make(map[float64]float64)
+	return nil
+}
+
+// archiveGrade saves the real grade of x in the archive of the surrogate model.
+func (gas *GeneticAlgorithmSolver) archiveGrade(x, grade float64) {
+	if gas.archive == nil {
+		return
+	}
+	if _, ok := gas.archive[x]; !ok {
+		gas.archiveX = append(gas.archiveX, x)
+	}
+	gas.archive[x] = grade
+}
+
+// realGrade checks if x was graded with the grading function. Without the surrogate model every
+// grade is a real one.
+func (gas GeneticAlgorithmSolver) realGrade(x float64) bool {
+	if gas.archive == nil {
+		return true
+	}
+	_, ok := gas.archive[x]
+	return ok
+}
+
+// gradeAll grades the given values. With the surrogate model only the most promising of the values
+// that were neither graded nor predicted before are graded with the grading function.
+func (gas *GeneticAlgorithmSolver) gradeAll(vals []float64) []float64 {
+	if gas.archive == nil || len(gas.archiveX) < minArchive {
+		return gas.gradeReal(vals)
+	}
+	grades := make([]float64, len(vals))
+
+	// The values graded or predicted before (also earlier in this batch) reuse their grades
+	var unknown []float64
+	seen := make(map[float64]bool)
+	for _, x := range vals {
+		_, graded := gas.archive[x]
+		_, predicted := gas.predicted[x]
+		if !graded && !predicted && !seen[x] {
+			seen[x] = true
+			unknown = append(unknown, x)
+		}
+	}
+
+	// Every value left with the predicted grade saves an evaluation. The failed evaluations aren't
+	// archived, so their values keep the grades given to them by the failure policy only in this batch
+	batch := make(map[float64]float64)
+	if len(unknown) > 0 {
+		model := gas.fitSurrogate()
+		predicted := make(map[float64]float64, len(unknown))
+		scores := make(map[float64]float64, len(unknown))
+		for _, x := range unknown {
+			predicted[x], scores[x] = model(x)
+		}
+		sort.SliceStable(unknown, func(i, j int) bool { return scores[unknown[i]] > scores[unknown[j]] })
+		real := int(math.Ceil(gas.surrogate.Fraction * float64(len(unknown))))
+		for i, grade := range gas.gradeReal(unknown[:real]) {
+			batch[unknown[i]] = grade
+		}
+		for _, x := range unknown[real:] {
+			gas.predicted[x] = predicted[x]
+			gas.saved++
+		}
+	}
+
+	for i, x := range vals {
+		if grade, ok := gas.archive[x]; ok {
+			grades[i] = grade
+		} else if grade, ok := gas.predicted[x]; ok {
+			grades[i] = grade
+		} else {
+			grades[i] = batch[x]
+		}
+	}
+	return grades
+}
+
+// verifyPredictions grades the Fraction (at least one) of the individuals of the population with the
+// highest predicted grades, which no longer count as saved evaluations.
+func (gas *GeneticAlgorithmSolver) verifyPredictions() {
+	if len(gas.predicted) == 0 {
+		return
+	}
+	var xs []float64
+	seen := make(map[float64]bool)
+	for _, x := range gas.popVals {
+		if _, ok := gas.predicted[x]; ok && !seen[x] {
+			seen[x] = true
+			xs = append(xs, x)
+		}
+	}
+	if len(xs) == 0 {
+		return
+	}
+	sort.SliceStable(xs, func(i, j int) bool { return gas.predicted[xs[i]] > gas.predicted[xs[j]] })
+	xs = xs[:int(math.Ceil(gas.surrogate.Fraction*float64(len(xs))))]
+
+	graded := make(map[float64]float64, len(xs))
+	for k, grade := range gas.gradeReal(xs) {
+		graded[xs[k]] = grade
+		delete(gas.predicted, xs[k])
+		gas.saved--
+	}
+	for i, x := range gas.popVals {
+		if grade, ok := graded[x]; ok {
+			gas.gradeCache[i], gas.fitCache[i] = grade, gas.fit(grade)
+		}
+	}
+	gas.roulette(gas.popVals)
+}
+
+// fitSurrogate fits the surrogate model to the latest archived grades and returns the function
+// predicting the grade of x and the score it's ranked by.
+func (gas *GeneticAlgorithmSolver) fitSurrogate() func(x float64) (grade, score float64) {
+	xs := gas.archiveX
+	if len(xs) > gas.surrogate.Archive {
+		xs = xs[len(xs)-gas.surrogate.Archive:]
+	}
+	ys := make([]float64, len(xs))
+	for i, x := range xs {
+		ys[i] = gas.archive[x]
+	}
+	width := gas.surrogate.Width * (gas.b - gas.a)
+	if width == 0 {
+		width = 1
+	}
+
+	switch gas.surrogate.Model {
+	case RadialBasis:
+		return fitRadialBasis(xs, ys)
+	case GaussianProcess:
+		return fitGaussianProcess(xs, ys, width)
+	}
+	return fitNearestNeighbours(xs, ys, gas.surrogate.Neighbours)
+}
+
+// fitNearestNeighbours returns the inverse distance weighted k nearest neighbours regression.
+func fitNearestNeighbours(xs, ys []float64, k int) func(x float64) (float64, float64) {
+	if k > len(xs) {
+		k = len(xs)
+	}
+	return func(x float64) (float64, float64) {
+		idx := make([]int, len(xs))
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.Slice(idx, func(i, j int) bool { return math.Abs(xs[idx[i]]-x) < math.Abs(xs[idx[j]]-x) })
+		sum, weights := 0.0, 0.0
+		for _, i := range idx[:k] {
+			d := math.Abs(xs[i] - x)
+			if d == 0 {
+				return ys[i], ys[i]
+			}
+			sum += ys[i] / d
+			weights += 1 / d
+		}
+		return sum / weights, sum / weights
+	}
+}
+
+// kernel is the squared exponential kernel of the given width.
+func kernel(a, b, width float64) float64 {
+	d := (a - b) / width
+	return math.Exp(-d * d / 2)
+}
+
+// standardize returns the grades shifted by their mean and scaled by their standard deviation
+// together with the mean and the deviation.
+func standardize(ys []float64) (zs []float64, mean, std float64) {
+	for _, y := range ys {
+		mean += y
+	}
+	mean /= float64(len(ys))
+	for _, y := range ys {
+		std += (y - mean) * (y - mean)
+	}
+	std = math.Sqrt(std / float64(len(ys)))
+	if std == 0 {
+		std = 1
+	}
+	zs = make([]float64, len(ys))
+	for i, y := range ys {
+		zs[i] = (y - mean) / std
+	}
+	return
+}
+
+// kernelCholesky returns the Cholesky factor of the kernel matrix of the points with the smallest
+// nugget (added to the diagonal) that makes it positive definite.
+func kernelCholesky(xs []float64, width float64) [][]float64 {
+	n := len(xs)
+	for nugget := 1e-8; ; nugget *= 10 {
+		k := make([][]float64, n)
+		for i := range k {
+			k[i] = make([]float64, n)
+			for j := range k[i] {
+				k[i][j] = kernel(xs[i], xs[j], width)
+			}
+			k[i][i] += nugget
+		}
+		if l, ok := cholesky(k); ok {
+			return l
+		}
+	}
+}
+
+// cholesky returns the lower triangular L such that L*L^T = a. ok is false if a is not positive
+// definite.
+func cholesky(a [][]float64) (l [][]float64, ok bool) {
+	n := len(a)
+	l = make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, n)
+		for j := 0; j <= i; j++ {
+			sum := a[i][j]
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if sum <= 0 {
+					return nil, false
+				}
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+	return l, true
+}
+
+// forward solves L*y = b for the lower triangular L.
+func forward(l [][]float64, b []float64) []float64 {
+	y := make([]float64, len(b))
+	for i := range b {
+		sum := b[i]
+		for k := 0; k < i; k++ {
+			sum -= l[i][k] * y[k]
+		}
+		y[i] = sum / l[i][i]
+	}
+	return y
+}
+
+// backward solves L^T*x = y for the lower triangular L.
+func backward(l [][]float64, y []float64) []float64 {
+	x := make([]float64, len(y))
+	for i := len(y) - 1; i >= 0; i-- {
+		sum := y[i]
+		for k := i + 1; k < len(y); k++ {
+			sum -= l[k][i] * x[k]
+		}
+		x[i] = sum / l[i][i]
+	}
+	return x
+}
+
+// fitRadialBasis returns the interpolation of the grades with cubic radial basis functions and a
+// linear polynomial. The weights w and the coefficients c of the polynomial solve the system
+// [Φ P; P^T 0] [w; c] = [y; 0] where Φ[i][j] = |x_i - x_j|^3 and P[i] = [1 x_i].
+func fitRadialBasis(xs, ys []float64) func(x float64) (float64, float64) {
+	n := len(xs)
+	a := make([][]float64, n+2)
+	for i := range a {
+		a[i] = make([]float64, n+3)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			a[i][j] = math.Pow(math.Abs(xs[i]-xs[j]), 3)
+		}
+		a[i][n], a[i][n+1] = 1, xs[i]
+		a[n][i], a[n+1][i] = 1, xs[i]
+		a[i][n+2] = ys[i]
+	}
+	w := solve(a)
+	return func(x float64) (float64, float64) {
+		y := w[n] + w[n+1]*x
+		for i := 0; i < n; i++ {
+			y += w[i] * math.Pow(math.Abs(x-xs[i]), 3)
+		}
+		return y, y
+	}
+}
+
+// solve solves the linear system given as the augmented matrix with Gaussian elimination with
+// partial pivoting. The matrix is modified in place and the unknowns of singular columns are 0.
+func solve(a [][]float64) []float64 {
+	n := len(a)
+	for c := 0; c < n; c++ {
+		pivot := c
+		for r := c + 1; r < n; r++ {
+			if math.Abs(a[r][c]) > math.Abs(a[pivot][c]) {
+				pivot = r
+			}
+		}
+		a[c], a[pivot] = a[pivot], a[c]
+		if a[c][c] == 0 {
+			continue
+		}
+		for r := c + 1; r < n; r++ {
+			f := a[r][c] / a[c][c]
+			for k := c; k <= n; k++ {
+				a[r][k] -= f * a[c][k]
+			}
+		}
+	}
+	x := make([]float64, n)
+	for r := n - 1; r >= 0; r-- {
+		if a[r][r] == 0 {
+			continue
+		}
+		sum := a[r][n]
+		for k := r + 1; k < n; k++ {
+			sum -= a[r][k] * x[k]
+		}
+		x[r] = sum / a[r][r]
+	}
+	return x
+}
+
+// fitGaussianProcess returns the posterior mean of a Gaussian process fitted to the grades and the
+// mean plus the posterior standard deviation as the score.
+func fitGaussianProcess(xs, ys []float64, width float64) func(x float64) (float64, float64) {
+	zs, mean, std := standardize(ys)
+	l := kernelCholesky(xs, width)
+	alpha := backward(l, forward(l, zs))
+	return func(x float64) (float64, float64) {
+		k := make([]float64, len(xs))
+		z := 0.0
+		for i := range xs {
+			k[i] = kernel(x, xs[i], width)
+			z += alpha[i] * k[i]
+		}
+		v := forward(l, k)
+		variance := 1.0
+		for _, vi := range v {
+			variance -= vi * vi
+		}
+		return mean + std*z, mean + std*(z+math.Sqrt(math.Max(0, variance)))
+	}
+}
@@ -0,0 +1,175 @@
+package evolalg
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// hill is a smooth function with the maximum 5 at x = 3.
+func hill(x float64) float64 {
+	return 5 - (x-3)*(x-3)/10
+}
+
+func TestSurrogateModelByName(t *testing.T) {
+	for name, want := range map[string]SurrogateModel{"": NoSurrogate, "none": NoSurrogate, "rbf": RadialBasis,
+		"knn": NearestNeighbours, "gp": GaussianProcess} {
+		if m, err := SurrogateModelByName(name); err != nil || m != want {
+			t.Log(fmt.Sprintf("%q was parsed as %d (%v)", name, m, err))
+			t.Fail()
+		}
+	}
+	if _, err := SurrogateModelByName("x"); err == nil {
+		t.Log("unknown model was parsed")
+		t.Fail()
+	}
+}
+
+func TestSurrogateModels(t *testing.T) {
+	var xs, ys []float64
+	for x := -4.0; x <= 12; x += 0.8 {
+		xs, ys = append(xs, x), append(ys, hill(x))
+	}
+	models := map[string]func(x float64) (float64, float64){
+		"rbf": fitRadialBasis(xs, ys),
+		"knn": fitNearestNeighbours(xs, ys, 3),
+		"gp":  fitGaussianProcess(xs, ys, 1.6),
+	}
+	for name, model := range models {
+		// The archived points are reproduced and the points between them are approximated
+		for i, x := range xs {
+			if grade, _ := model(x); math.Abs(grade-ys[i]) > 1e-3 {
+				t.Log(fmt.Sprintf("%s predicted %f instead of the archived %f at %f", name, grade, ys[i], x))
+				t.Fail()
+			}
+		}
+		tolerance := 0.01
+		if name == "knn" {
+			tolerance = 0.3
+		}
+		for x := xs[0] + 0.4; x < xs[len(xs)-1]; x += 0.8 {
+			if grade, _ := model(x); math.Abs(grade-hill(x)) > tolerance {
+				t.Log(fmt.Sprintf("%s predicted %f instead of %f at %f", name, grade, hill(x), x))
+				t.Fail()
+			}
+		}
+	}
+
+	// The Gaussian process is optimistic far from the archived points
+	grade, score := models["gp"](20)
+	if score-grade < 0.5 {
+		t.Log(fmt.Sprintf("gp scored the unknown point with %f for the prediction %f", score, grade))
+		t.Fail()
+	}
+}
+
+func TestSurrogate(t *testing.T) {
+	gas, err := NewGeneticAlgorithmSolver(-4, 12, 3, hill)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gas.SetSurrogate(Surrogate{Model: RadialBasis, Fraction: 2}); err == nil {
+		t.Log("fraction greater than 1 was accepted")
+		t.Fail()
+	}
+	gas.SetSeed(1)
+	baseline, err := gas.Solve(30, 40, 0.75, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := baseline[len(baseline)-1].Evaluations
+
+	for _, model := range []SurrogateModel{RadialBasis, NearestNeighbours, GaussianProcess} {
+		for _, r := range []Replacement{InPlace, PlusSelection} {
+			err = gas.SetSurrogate(Surrogate{Model: model})
+			if err != nil {
+				t.Fatal(err)
+			}
+			gas.SetReplacement(r, 0)
+			gas.SetGenealogy(true)
+			gas.SetSeed(1)
+			hist, err := gas.Solve(30, 40, 0.75, 0.01)
+			if err != nil {
+				t.Fatal(err)
+			}
+			last := hist[len(hist)-1]
+			if last.Saved == 0 || last.Evaluations >= want/2 || last.Evaluations != gas.Evaluations() ||
+				last.Saved != gas.SavedEvaluations() {
+				t.Log(fmt.Sprintf("model %d, replacement %d made %d evaluations and saved %d (%d without the model)",
+					model, r, last.Evaluations, last.Saved, want))
+				t.Fail()
+			}
+
+			// After the initial population every value is either evaluated or predicted once
+			initial := make(map[float64]bool)
+			for _, x := range hist[0].PopulationF64 {
+				initial[x] = true
+			}
+			evaluated := len(gas.archiveX) - len(initial)
+			if last.Evaluations-hist[0].Evaluations != evaluated || last.Saved != len(gas.predicted) {
+				t.Log(fmt.Sprintf("model %d, replacement %d evaluated %d values %d times and predicted %d values %d times",
+					model, r, evaluated, last.Evaluations-hist[0].Evaluations, len(gas.predicted), last.Saved))
+				t.Fail()
+			}
+			for x := range gas.predicted {
+				if gas.realGrade(x) {
+					t.Log(fmt.Sprintf("model %d, replacement %d evaluated the predicted %f", model, r, x))
+					t.Fail()
+				}
+			}
+
+			// The elite has a real grade
+			if math.Abs(hill(last.Elite)-gas.eliteGrade) > 1e-9 || hill(last.Elite) < 4.9 {
+				t.Log(fmt.Sprintf("model %d, replacement %d ended with the elite %f of the grade %f", model, r, last.Elite,
+					gas.eliteGrade))
+				t.Fail()
+			}
+		}
+	}
+	gas.SetReplacement(InPlace, 0)
+	gas.SetGenealogy(false)
+
+	// An overestimated individual kept by the plus selection is graded in the next epoch
+	gas.SetSurrogate(Surrogate{Model: RadialBasis})
+	gas.SetReplacement(PlusSelection, 0)
+	gas.SetSeed(1)
+	if _, err = gas.Start(30, 40, 0.75, 0.01); err != nil {
+		t.Fatal(err)
+	}
+	x := gas.XIntToXReal(gas.XRealToXInt(-3))
+	gas.popVals[0], gas.popArr[0] = x, gas.XIntToXBin(uint32(gas.XRealToXInt(x)))
+	gas.predicted[x], gas.gradeCache[0], gas.fitCache[0] = 100, 100, gas.fitFromGrade(100)
+	gas.saved++
+	gas.roulette(gas.popVals)
+	if _, _, err = gas.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := gas.predicted[x]; ok || !gas.realGrade(x) {
+		t.Log(fmt.Sprintf("overestimated %f wasn't graded", x))
+		t.Fail()
+	}
+	for i, y := range gas.popVals {
+		if y == x && gas.gradeCache[i] != hill(x) {
+			t.Log(fmt.Sprintf("overestimated %f stayed in the population graded %f", x, gas.gradeCache[i]))
+			t.Fail()
+		}
+	}
+	gas.SetReplacement(InPlace, 0)
+
+	// The model can't learn a noisy objective
+	gas.SetObjective(func(x float64, _ int, rng *rand.Rand) float64 { return hill(x) + rng.NormFloat64() })
+	if _, err := gas.Solve(30, 5, 0.75, 0.01); err == nil {
+		t.Log("surrogate model accepted a noisy objective")
+		t.Fail()
+	}
+	gas.SetObjective(nil)
+	gas.SetSurrogate(Surrogate{})
+	hist, err := gas.Solve(30, 5, 0.75, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	} else if hist[len(hist)-1].Saved != 0 {
+		t.Log("run without the model saved evaluations")
+		t.Fail()
+	}
+}
@@ -0,0 +1,189 @@
+package sweep
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// Format is the format of the results of a sweep.
+type Format int
+
+const (
+	// CSV writes a row per run with a column per swept parameter.
+	CSV Format = iota
+	// JSON writes a JSON object per run in every line (JSON lines).
+	JSON
+)
+
+// FormatByName returns the format with the given name ("csv" or "json").
+func FormatByName(name string) (Format, error) {
+	switch name {
+	case "csv", "":
+		return CSV, nil
+	case "json":
+		return JSON, nil
+	}
+	return CSV, errors.New("unknown output format " + name)
+}
+
+// resultColumns are the columns of the CSV results following the ones of the swept parameters.
+var resultColumns = []string{"repetition", "seed", "best", "best_epoch", "fmax", "favg", "fmin",
+	"evaluations", "epochs", "seconds"}
+
+// Writer streams the results of a sweep to an io.Writer. Every result is flushed as soon as it's
+// written so that an interrupted sweep loses at most the runs that were in progress.
+type Writer struct {
+	format Format
+	names  []string // names of the swept parameters (CSV columns)
+	csv    *csv.Writer
+	json   *json.Encoder
+}
+
+// NewWriter creates a writer of the results of the spec in the given format. The CSV header is
+// written only if header is set, which allows appending to the results of a resumed sweep.
+func NewWriter(w io.Writer, format Format, spec Spec, header bool) (*Writer, error) {
+	res := &Writer{format: format, names: spec.Names()}
+	if format == JSON {
+		res.json = json.NewEncoder(w)
+		return res, nil
+	}
+	res.csv = csv.NewWriter(w)
+	if header {
+		row := append([]string{"function", "dim"}, res.names...)
+		row = append(row, resultColumns...)
+		if err := res.csv.Write(row); err != nil {
+			return nil, err
+		}
+		res.csv.Flush()
+		return res, res.csv.Error()
+	}
+	return res, nil
+}
+
+// Write writes a single result.
+func (w *Writer) Write(r Result) error {
+	if w.format == JSON {
+		return w.json.Encode(r)
+	}
+	row := []string{r.Function, strconv.Itoa(r.Dim)}
+	for _, name := range w.names {
+		row = append(row, r.Params[name])
+	}
+	row = append(row,
+		strconv.Itoa(r.Repetition),
+		strconv.FormatInt(r.Seed, 10),
+		formatFloat(r.Best),
+		strconv.Itoa(r.BestEpoch),
+		formatFloat(r.FMax),
+		formatFloat(r.FAVG),
+		formatFloat(r.FMin),
+		strconv.Itoa(r.Evaluations),
+		strconv.Itoa(r.Epochs),
+		formatFloat(r.Seconds),
+	)
+	if err := w.csv.Write(row); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// formatFloat formats a number with the shortest representation that parses back to it.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// ReadResults reads the results written by a Writer in the given format. A truncated last line
+// (left by an interrupted sweep) is skipped.
+func ReadResults(r io.Reader, format Format) (results []Result, err error) {
+	if format == JSON {
+		sc := bufio.NewScanner(r)
+		sc.Buffer(nil, 1<<20)
+		for sc.Scan() {
+			var res Result
+			if len(sc.Bytes()) == 0 {
+				continue
+			} else if json.Unmarshal(sc.Bytes(), &res) != nil {
+				break
+			}
+			results = append(results, res)
+		}
+		return results, sc.Err()
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	nParams := len(header) - 2 - len(resultColumns)
+	if nParams < 0 || header[0] != "function" || header[1] != "dim" {
+		return nil, errors.New("results have an unknown header")
+	}
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return results, nil
+		} else if err != nil {
+			return nil, err
+		} else if len(row) != len(header) {
+			continue // truncated row
+		}
+		res, err := parseRow(header, row, nParams)
+		if err != nil {
+			continue
+		}
+		results = append(results, res)
+	}
+}
+
+// parseRow converts a CSV row to a result.
+func parseRow(header, row []string, nParams int) (res Result, err error) {
+	res.Function = row[0]
+	res.Params = make(map[string]string, nParams)
+	for i := 0; i < nParams; i++ {
+		res.Params[header[2+i]] = row[2+i]
+	}
+	ints := []*int{&res.Dim, &res.Repetition, &res.BestEpoch, &res.Evaluations, &res.Epochs}
+	cols := []string{row[1], row[2+nParams], row[5+nParams], row[9+nParams], row[10+nParams]}
+	for i := range ints {
+		*ints[i], err = strconv.Atoi(cols[i])
+		if err != nil {
+			return
+		}
+	}
+	res.Seed, err = strconv.ParseInt(row[3+nParams], 10, 64)
+	if err != nil {
+		return
+	}
+	floats := []*float64{&res.Best, &res.FMax, &res.FAVG, &res.FMin, &res.Seconds}
+	cols = []string{row[4+nParams], row[6+nParams], row[7+nParams], row[8+nParams], row[11+nParams]}
+	for i := range floats {
+		*floats[i], err = strconv.ParseFloat(cols[i], 64)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// ReadDone returns the keys of the runs whose results were already written, to be passed to Run
+// when resuming a sweep.
+func ReadDone(r io.Reader, format Format) (map[string]bool, error) {
+	results, err := ReadResults(r, format)
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[string]bool, len(results))
+	for _, res := range results {
+		done[res.Key()] = true
+	}
+	return done, nil
+}
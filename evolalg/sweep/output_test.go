@@ -0,0 +1,50 @@
+package sweep
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestWriterRoundTrip(t *testing.T) {
+	spec := Spec{Params: map[string]string{"N": "10", "Pk": "0.8"}}
+	results := []Result{
+		{Function: "lab", Dim: 1, Params: map[string]string{"N": "10", "Pk": "0.8"}, Repetition: 0, Seed: 3,
+			Best: 1.5, BestEpoch: 2, FMax: 1.5, FAVG: 0.25, FMin: -1, Evaluations: 40, Epochs: 3, Seconds: 0.01},
+		{Function: "lab", Dim: 1, Params: map[string]string{"N": "10", "Pk": "0.8"}, Repetition: 1, Seed: 4,
+			Best: 2, BestEpoch: 0, FMax: 1, FAVG: 0.5, FMin: 0, Evaluations: 40, Epochs: 3, Seconds: 0.02},
+	}
+	for _, format := range []Format{CSV, JSON} {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, format, spec, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range results {
+			if err := w.Write(r); err != nil {
+				t.Fatal(err)
+			}
+		}
+		buf.WriteString("lab,1,10") // truncated line of an interrupted sweep
+
+		read, err := ReadResults(bytes.NewReader(buf.Bytes()), format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(read) != len(results) {
+			t.Fatal(fmt.Sprintf("format %d: %d results were read instead of %d", format, len(read), len(results)))
+		}
+		for i := range read {
+			if read[i].Key() != results[i].Key() || read[i].Best != results[i].Best ||
+				read[i].Seed != results[i].Seed || read[i].Seconds != results[i].Seconds {
+				t.Log(fmt.Sprintf("format %d: %v was read as %v", format, results[i], read[i]))
+				t.Fail()
+			}
+		}
+		done, _ := ReadDone(bytes.NewReader(buf.Bytes()), format)
+		if !done[results[1].Key()] || len(done) != 2 {
+			t.Log(fmt.Sprintf("format %d: finished runs are %v", format, done))
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Package sweep runs grid searches over the parameters of the solvers of the evolalg registry. A
+// sweep is described by a Spec listing the values of the parameters and the benchmark functions;
+// every combination of them is run Repetitions times on a pool of workers and the results of the
+// runs are streamed to CSV or JSON lines, so that an interrupted sweep can be resumed.
+package sweep
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TheSlipper/isa/evolalg"
+	"github.com/TheSlipper/isa/evolalg/benchmarks"
+)
+
+// Spec describes a sweep. Every value of Params is a comma separated list of items, each of which is
+// either a single value or a from:to:step range (inclusive), e.g. "0.0001,0.0005:0.01:0.0005".
+type Spec struct {
+	Algorithm   string            `json:"algorithm"`   // name of the algorithm in the registry ("ga" if empty)
+	Functions   []string          `json:"functions"`   // names of the benchmark functions (["lab"] if empty)
+	Dim         int               `json:"dim"`         // dimensionality of the functions (the default one of each function if 0)
+	Params      map[string]string `json:"params"`      // values of the swept parameters by their names
+	Repetitions int               `json:"repetitions"` // amount of runs of every configuration (1 if 0)
+	Seed        int64             `json:"seed"`        // seed of the first run; the following runs use the next seeds
+}
+
+// Config is a single combination of the values of a sweep.
+type Config struct {
+	Function string         // name of the benchmark function
+	Dim      int            // dimensionality of the function
+	Params   evolalg.Params // values of the swept parameters
+	Index    int            // position of the configuration in the sweep
+}
+
+// Key returns the string identifying the configuration in the results of a sweep.
+func (c Config) Key() string {
+	return key(c.Function, c.Dim, c.Params)
+}
+
+// key joins the function, its dimensionality and the parameters sorted by their names.
+func key(function string, dim int, params map[string]string) string {
+	parts := []string{function, strconv.Itoa(dim)}
+	for _, name := range sortedNames(params) {
+		parts = append(parts, name+"="+params[name])
+	}
+	return strings.Join(parts, "|")
+}
+
+// sortedNames returns the names of the parameters in the alphabetical order.
+func sortedNames(params map[string]string) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseValues expands a comma separated list of values and from:to:step ranges. The values of a
+// range are formatted as integers if all of its bounds and its step are integers.
+func ParseValues(s string) (vals []string, err error) {
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		bounds := strings.Split(item, ":")
+		if len(bounds) == 1 {
+			vals = append(vals, item)
+			continue
+		} else if len(bounds) != 3 {
+			return nil, errors.New("range " + item + " has to be given as from:to:step")
+		}
+		var r [3]float64
+		integer := true
+		for i, b := range bounds {
+			r[i], err = strconv.ParseFloat(strings.TrimSpace(b), 64)
+			if err != nil {
+				return nil, errors.New("range " + item + " has to consist of numbers")
+			}
+			integer = integer && r[i] == math.Trunc(r[i])
+		}
+		from, to, step := r[0], r[1], r[2]
+		if step <= 0 || from > to {
+			return nil, errors.New("range " + item + " has to have a positive step and from not greater than to")
+		}
+		// The values are computed from their index to avoid accumulating the rounding errors
+		for i := 0; ; i++ {
+			v := from + float64(i)*step
+			if v > to+step*1e-9 {
+				break
+			}
+			if integer {
+				vals = append(vals, strconv.Itoa(int(v)))
+			} else {
+				vals = append(vals, strconv.FormatFloat(v, 'g', 12, 64))
+			}
+		}
+	}
+	if len(vals) == 0 {
+		err = errors.New("no values were given")
+	}
+	return
+}
+
+// algorithm returns the algorithm of the sweep.
+func (s Spec) algorithm() (evolalg.Algorithm, error) {
+	name := s.Algorithm
+	if name == "" {
+		name = "ga"
+	}
+	return evolalg.AlgorithmByName(name)
+}
+
+// repetitions returns the amount of runs of every configuration.
+func (s Spec) repetitions() int {
+	if s.Repetitions <= 0 {
+		return 1
+	}
+	return s.Repetitions
+}
+
+// Names returns the names of the swept parameters in the alphabetical order.
+func (s Spec) Names() []string {
+	return sortedNames(s.Params)
+}
+
+// Configs checks the spec and returns all of its configurations. The functions vary the slowest and
+// the parameters in the alphabetical order of their names.
+func (s Spec) Configs() ([]Config, error) {
+	alg, err := s.algorithm()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool)
+	for _, p := range alg.Schema() {
+		known[p.Name] = true
+	}
+	if s.Params["ziarno"] != "" {
+		return nil, errors.New("seeds of the runs are set by the sweep")
+	}
+
+	functions := s.Functions
+	if len(functions) == 0 {
+		functions = []string{"lab"}
+	}
+	names := s.Names()
+	values := make([][]string, len(names))
+	for i, name := range names {
+		if !known[name] {
+			return nil, fmt.Errorf("algorithm %s has no parameter %s", alg.Name, name)
+		}
+		values[i], err = ParseValues(s.Params[name])
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %s", name, err.Error())
+		}
+	}
+
+	var configs []Config
+	for _, fnName := range functions {
+		fn, err := benchmarks.ByName(fnName)
+		if err != nil {
+			return nil, err
+		}
+		dim := s.Dim
+		if dim == 0 {
+			dim = fn.DefaultDim
+		}
+		if err := fn.CheckDim(dim); err != nil {
+			return nil, err
+		}
+		// idx is a mixed radix counter over the values of the parameters
+		idx := make([]int, len(names))
+		for {
+			params := make(evolalg.Params, len(names))
+			for i, name := range names {
+				params[name] = values[i][idx[i]]
+			}
+			configs = append(configs, Config{Function: fnName, Dim: dim, Params: params, Index: len(configs)})
+
+			i := len(idx) - 1
+			for ; i >= 0; i-- {
+				idx[i]++
+				if idx[i] < len(values[i]) {
+					break
+				}
+				idx[i] = 0
+			}
+			if i < 0 {
+				break
+			}
+		}
+	}
+	return configs, nil
+}
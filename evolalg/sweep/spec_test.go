@@ -0,0 +1,80 @@
+package sweep
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseValues(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"30:50:10", "30,40,50"},
+		{"0.5:0.65:0.05", "0.5,0.55,0.6,0.65"},
+		{"0.0001, 0.0005:0.001:0.0005", "0.0001,0.0005,0.001"},
+		{"roulette,tournament", "roulette,tournament"},
+	}
+	for _, c := range cases {
+		vals, err := ParseValues(c.in)
+		if err != nil {
+			t.Log(fmt.Sprintf("%s: %s", c.in, err.Error()))
+			t.Fail()
+		} else if got := strings.Join(vals, ","); got != c.want {
+			t.Log(fmt.Sprintf("%s was expanded to %s instead of %s", c.in, got, c.want))
+			t.Fail()
+		}
+	}
+	for _, in := range []string{"", "1:2", "5:1:1", "1:2:0", "a:b:c"} {
+		if _, err := ParseValues(in); err == nil {
+			t.Log(fmt.Sprintf("invalid values %q were accepted", in))
+			t.Fail()
+		}
+	}
+}
+
+func TestConfigs(t *testing.T) {
+	spec := Spec{
+		Functions: []string{"lab", "deb1"},
+		Params:    map[string]string{"N": "10,20", "Pk": "0.75:0.85:0.05"},
+	}
+	configs, err := spec.Configs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(configs) != 12 {
+		t.Fatal(fmt.Sprintf("%d configurations instead of 12", len(configs)))
+	}
+	first, last := configs[0], configs[11]
+	if first.Function != "lab" || first.Params["N"] != "10" || first.Params["Pk"] != "0.75" {
+		t.Log(fmt.Sprintf("first configuration is %v", first))
+		t.Fail()
+	}
+	if last.Function != "deb1" || last.Params["N"] != "20" || last.Params["Pk"] != "0.85" || last.Index != 11 {
+		t.Log(fmt.Sprintf("last configuration is %v", last))
+		t.Fail()
+	}
+	seen := make(map[string]bool)
+	for _, c := range configs {
+		if seen[c.Key()] {
+			t.Log("duplicated configuration " + c.Key())
+			t.Fail()
+		}
+		seen[c.Key()] = true
+	}
+
+	for _, spec := range []Spec{
+		{Algorithm: "x"},
+		{Params: map[string]string{"x": "1"}},
+		{Params: map[string]string{"ziarno": "1"}},
+		{Params: map[string]string{"N": "1:2"}},
+		{Functions: []string{"x"}},
+		{Functions: []string{"himmelblau"}, Dim: 3},
+	} {
+		if _, err := spec.Configs(); err == nil {
+			t.Log(fmt.Sprintf("invalid spec %v was accepted", spec))
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/TheSlipper/isa/evolalg"
+	"github.com/TheSlipper/isa/evolalg/benchmarks"
+)
+
+// Result is the outcome of a single run of a configuration.
+type Result struct {
+	Function    string            `json:"function"`
+	Dim         int               `json:"dim"`
+	Params      map[string]string `json:"params"`      // values of the swept parameters
+	Repetition  int               `json:"repetition"`  // index of the run of the configuration
+	Seed        int64             `json:"seed"`        // seed of the run
+	Best        float64           `json:"best"`        // best grade found during the run
+	BestEpoch   int               `json:"bestEpoch"`   // first epoch in which the best grade was found
+	FMax        float64           `json:"fMax"`        // highest grade of the last epoch
+	FAVG        float64           `json:"fAVG"`        // average grade of the last epoch
+	FMin        float64           `json:"fMin"`        // lowest grade of the last epoch
+	Evaluations int               `json:"evaluations"` // fitness evaluations made during the run
+	Epochs      int               `json:"epochs"`      // epochs run (less than requested if terminated)
+	Seconds     float64           `json:"seconds"`     // duration of the run
+}
+
+// Key returns the string identifying the run in the results of a sweep.
+func (r Result) Key() string {
+	return key(r.Function, r.Dim, r.Params) + "#" + strconv.Itoa(r.Repetition)
+}
+
+// job is a single run of a configuration.
+type job struct {
+	config     Config
+	repetition int
+}
+
+// key returns the string identifying the run.
+func (j job) key() string {
+	return j.config.Key() + "#" + strconv.Itoa(j.repetition)
+}
+
+// Run runs every configuration of the spec Repetitions times on the given amount of workers
+// (runtime.NumCPU() if 0) and passes the results to out in the order in which the runs finish.
+// out is never called concurrently. The runs whose keys are in done are skipped, which allows
+// resuming a sweep from its results (see ReadDone). Run stops at the first error of a run or of out,
+// or when ctx is cancelled.
+func Run(ctx context.Context, spec Spec, workers int, done map[string]bool, out func(Result) error) error {
+	configs, err := spec.Configs()
+	if err != nil {
+		return err
+	}
+	alg, err := spec.algorithm()
+	if err != nil {
+		return err
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	reps := spec.repetitions()
+	jobs := make(chan job)
+	go func() {
+		defer close(jobs)
+		for _, c := range configs {
+			for rep := 0; rep < reps; rep++ {
+				j := job{c, rep}
+				if done[j.key()] {
+					continue
+				}
+				select {
+				case jobs <- j:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	type outcome struct {
+		res Result
+		err error
+	}
+	results := make(chan outcome)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				seed := spec.Seed + int64(j.config.Index*reps+j.repetition)
+				res, err := runJob(ctx, alg, j, seed)
+				select {
+				case results <- outcome{res, err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for o := range results {
+		if err == nil {
+			err = o.err
+		}
+		if err == nil {
+			err = out(o.res)
+		}
+		if err != nil {
+			cancel() // the workers stop and results gets closed
+		}
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	return err
+}
+
+// runJob runs the job with the given seed.
+func runJob(ctx context.Context, alg evolalg.Algorithm, j job, seed int64) (res Result, err error) {
+	c := j.config
+	fn, err := benchmarks.ByName(c.Function)
+	if err != nil {
+		return
+	}
+	problem, err := fn.Problem(c.Dim)
+	if err != nil {
+		return
+	}
+	s, err := alg.New(problem)
+	if err != nil {
+		return res, fmt.Errorf("%s: %s", c.Key(), err.Error())
+	}
+	params := evolalg.Params{"ziarno": strconv.FormatInt(seed, 10)}
+	for name, val := range c.Params {
+		params[name] = val
+	}
+	err = s.Configure(params)
+	if err != nil {
+		return res, fmt.Errorf("%s: %s", c.Key(), err.Error())
+	}
+
+	start := time.Now()
+	hist, err := s.Run(ctx)
+	if err != nil {
+		return
+	}
+	res = summarize(hist)
+	res.Seconds = time.Since(start).Seconds()
+	res.Function, res.Dim, res.Params = c.Function, c.Dim, c.Params
+	res.Repetition, res.Seed = j.repetition, seed
+	return
+}
+
+// summarize returns the result of the history of a run without the description of the run.
+func summarize(hist []evolalg.EpochData) (res Result) {
+	res.Best = hist[0].FMax
+	for i, ed := range hist {
+		if ed.FMax > res.Best {
+			res.Best, res.BestEpoch = ed.FMax, i
+		}
+	}
+	last := hist[len(hist)-1]
+	res.FMax, res.FAVG, res.FMin = last.FMax, last.FAVG, last.FMin
+	res.Evaluations = last.Evaluations
+	res.Epochs = len(hist) - 1
+	return
+}
@@ -0,0 +1,76 @@
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	spec := Spec{
+		Params:      map[string]string{"N": "4,6", "epoki": "3"},
+		Repetitions: 3,
+		Seed:        10,
+	}
+	collect := func(workers int, done map[string]bool) map[string]Result {
+		res := make(map[string]Result)
+		err := Run(context.Background(), spec, workers, done, func(r Result) error {
+			res[r.Key()] = r
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	all := collect(4, nil)
+	if len(all) != 6 {
+		t.Fatal(fmt.Sprintf("%d results instead of 6", len(all)))
+	}
+	seeds := make(map[int64]bool)
+	for k, r := range all {
+		if r.Epochs != 3 || r.Best < r.FMax || r.Evaluations == 0 {
+			t.Log(fmt.Sprintf("%s: invalid result %v", k, r))
+			t.Fail()
+		}
+		seeds[r.Seed] = true
+	}
+	if len(seeds) != 6 {
+		t.Log("runs share their seeds")
+		t.Fail()
+	}
+
+	// The results don't depend on the amount of workers and the finished runs are skipped
+	done := make(map[string]bool)
+	for k := range all {
+		if len(done) < 4 {
+			done[k] = true
+		}
+	}
+	rest := collect(1, done)
+	if len(rest) != 2 {
+		t.Fatal(fmt.Sprintf("%d runs were resumed instead of 2", len(rest)))
+	}
+	for k, r := range rest {
+		if done[k] {
+			t.Log("finished run " + k + " was repeated")
+			t.Fail()
+		} else if r.Best != all[k].Best || r.FAVG != all[k].FAVG || r.Seed != all[k].Seed {
+			t.Log(fmt.Sprintf("%s: resumed run differs from the original one", k))
+			t.Fail()
+		}
+	}
+
+	// The errors of out and of the runs stop the sweep
+	err := Run(context.Background(), spec, 2, nil, func(r Result) error { return fmt.Errorf("x") })
+	if err == nil || err.Error() != "x" {
+		t.Log(fmt.Sprintf("error of out was returned as %v", err))
+		t.Fail()
+	}
+	spec.Params["Pk"] = "2"
+	if err := Run(context.Background(), spec, 2, nil, func(r Result) error { return nil }); err == nil {
+		t.Log("invalid configuration didn't stop the sweep")
+		t.Fail()
+	}
+}
@@ -0,0 +1,183 @@
+package evolalg
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// Aspiration describes when a tabu move can be made anyway.
+type Aspiration int
+
+const (
+	// BestAspiration allows a tabu move that leads to a solution better than the best one found so
+	// far. This is the default.
+	BestAspiration Aspiration = iota
+	// ImprovementAspiration allows a tabu move that improves the current solution.
+	ImprovementAspiration
+	// NoAspiration never allows tabu moves.
+	NoAspiration
+)
+
+// AspirationByName returns the aspiration criterion based on its name: "best", "improvement" or
+// "none". An empty name returns BestAspiration.
+func AspirationByName(name string) (Aspiration, error) {
+	switch name {
+	case "", "best":
+		return BestAspiration, nil
+	case "improvement":
+		return ImprovementAspiration, nil
+	case "none":
+		return NoAspiration, nil
+	}
+	return BestAspiration, errors.New("unknown aspiration criterion " + name)
+}
+
+// TabuSearchSolver is a tabu search solver working on the same bitstring encoding as
+// GeneticAlgorithmSolver. In every epoch it moves to the best neighbour that differs in a single
+// bit which is not tabu. A flipped bit stays tabu for the tenure of epochs. If every move is tabu
+// and none satisfies the aspiration criterion, the move that was made tabu the earliest is taken.
+// Its history holds a population of a single (current) solution.
+type TabuSearchSolver struct {
+	gas        GeneticAlgorithmSolver // encoding, evaluation counting and the state of the search
+	tenure     int                    // amount of epochs in which a flipped bit is tabu (l/4 if 0)
+	aspiration Aspiration             // when a tabu move can be made anyway
+	tabuUntil  []int                  // last epoch in which flipping each of the bits is tabu
+}
+
+// NewTabuSearchSolver creates a new instance of a tabu search solver. The arguments are the same as
+// the ones of NewGeneticAlgorithmSolver.
+func NewTabuSearchSolver(a float64, b float64, d byte, gFunc func(x float64) float64) (ts TabuSearchSolver, err error) {
+	ts.gas, err = NewGeneticAlgorithmSolver(a, b, d, gFunc)
+	return
+}
+
+// SetSeed makes the solver use its own random number source seeded with the given seed so that the
+// runs can be repeated.
+func (ts *TabuSearchSolver) SetSeed(seed int64) {
+	ts.gas.SetSeed(seed)
+}
+
+// SetFailures sets the handling of failed fitness evaluations.
+func (ts *TabuSearchSolver) SetFailures(f Failures) error {
+	return ts.gas.SetFailures(f)
+}
+
+// SetContext sets the context passed to the fitness.
+func (ts *TabuSearchSolver) SetContext(ctx context.Context) {
+	ts.gas.SetContext(ctx)
+}
+
+// SetTermination sets the conditions that stop Solve before it runs for all of the epochs.
+func (ts *TabuSearchSolver) SetTermination(t Termination) {
+	ts.gas.SetTermination(t)
+}
+
+// SetTabu sets the tenure of the tabu moves and the aspiration criterion.
+func (ts *TabuSearchSolver) SetTabu(tenure int, aspiration Aspiration) error {
+	if tenure < 0 || tenure >= ts.gas.l {
+		return errors.New("tabu tenure has to be lower than the length of the genome")
+	}
+	ts.tenure, ts.aspiration = tenure, aspiration
+	return nil
+}
+
+// Solve runs the tabu search for a given amount of epochs and returns a history of the algorithm's
+// execution.
+func (ts *TabuSearchSolver) Solve(epochs int) (hist []EpochData, err error) {
+	ed, err := ts.Start(epochs)
+	if err != nil {
+		return
+	}
+	hist = append(make([]EpochData, 0, epochs+1), ed)
+	for done := epochs == 0; !done; {
+		ed, done, err = ts.Step()
+		if err != nil {
+			return
+		}
+		hist = append(hist, ed)
+	}
+	return
+}
+
+// Start initializes the search the same way as Solve and returns the history entry of the initial
+// solution. The following epochs are run with Step.
+func (ts *TabuSearchSolver) Start(epochs int) (ed EpochData, err error) {
+	gas := &ts.gas
+	err = gas.startSingle()
+	if err != nil {
+		return
+	}
+	gas.runEpochs = epochs
+	ts.tabuUntil = make([]int, gas.l)
+	err = gas.saveStateToHistory(&ed)
+	return
+}
+
+// Step runs a single epoch of the search started with Start and returns its history entry. done is
+// set if any of the termination conditions is met or the epoch is the last one passed to Start.
+func (ts *TabuSearchSolver) Step() (ed EpochData, done bool, err error) {
+	gas := &ts.gas
+	if ts.tabuUntil == nil {
+		err = errors.New("solver has to be started before running an epoch")
+		return
+	}
+	tenure := ts.tenure
+	if tenure == 0 {
+		tenure = int(math.Max(1, float64(gas.l/4)))
+	}
+
+	gas.epoch++
+	i := gas.epoch
+	cur := gas.gradeCache[0]
+	best, bestX, bestGrade := -1, 0.0, math.Inf(-1)
+	oldest, oldestX, oldestGrade := -1, 0.0, 0.0
+
+	// The whole neighbourhood is graded in a single batch
+	xs := make([]float64, gas.l)
+	for j := range xs {
+		gas.popArr[0][j] ^= 1
+		xs[j] = gas.XIntToXReal(gas.XBinToXInt(gas.popArr[0]))
+		gas.popArr[0][j] ^= 1
+	}
+	grades := gas.gradeReal(xs)
+	for j := 0; j < gas.l; j++ {
+		x, grade := xs[j], grades[j]
+
+		allowed := ts.tabuUntil[j] < i
+		switch {
+		case allowed:
+		case ts.aspiration == BestAspiration:
+			allowed = gas.fitFromGrade(grade) > gas.eliteFit
+		case ts.aspiration == ImprovementAspiration:
+			allowed = grade > cur
+		}
+		if allowed && grade > bestGrade {
+			best, bestX, bestGrade = j, x, grade
+		}
+		if oldest == -1 || ts.tabuUntil[j] < ts.tabuUntil[oldest] {
+			oldest, oldestX, oldestGrade = j, x, grade
+		}
+	}
+	if best == -1 {
+		best, bestX, bestGrade = oldest, oldestX, oldestGrade
+	}
+
+	genome := append([]byte(nil), gas.popArr[0]...)
+	genome[best] ^= 1
+	gas.moveSingle(genome, bestX, bestGrade)
+	ts.tabuUntil[best] = i + tenure
+
+	err = gas.takeFailure()
+	if err != nil {
+		return
+	}
+	err = gas.saveStateToHistory(&ed)
+	if err != nil {
+		return
+	}
+
+	// Stop early if any of the termination conditions is met
+	done = i >= gas.runEpochs || gas.termination.Done(i, gas.evals, gas.eliteGrade, gas.eliteEpoch)
+	return
+}
@@ -0,0 +1,54 @@
+package evolalg
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestTabuSearch(t *testing.T) {
+	for _, name := range []string{"best", "improvement", "none"} {
+		aspiration, err := AspirationByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts, err := NewTabuSearchSolver(-4, 12, 3, parabola)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts.SetSeed(6)
+		err = ts.SetTabu(3, aspiration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hist, err := ts.Solve(100)
+		if err != nil {
+			t.Log(fmt.Sprintf("%s: %s", name, err.Error()))
+			t.Fail()
+			continue
+		}
+
+		last := hist[len(hist)-1]
+		if math.Abs(last.Elite-3) > 0.01 {
+			t.Log(fmt.Sprintf("%s: elite %f is too far from the optimum", name, last.Elite))
+			t.Fail()
+		}
+
+		// A bit can't be flipped back within the tenure unless the aspiration allows it
+		if aspiration == NoAspiration {
+			for i := 4; i < len(hist); i++ {
+				if string(hist[i].PopulationBytes[0]) == string(hist[i-2].PopulationBytes[0]) {
+					t.Log(fmt.Sprintf("%s: search went back to the solution of epoch %d in epoch %d", name, i-2, i))
+					t.Fail()
+					break
+				}
+			}
+		}
+	}
+
+	ts, _ := NewTabuSearchSolver(-4, 12, 3, parabola)
+	if err := ts.SetTabu(ts.gas.L(), BestAspiration); err == nil {
+		t.Log("solver accepted a tenure as long as the genome")
+		t.Fail()
+	}
+}
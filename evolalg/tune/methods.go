@@ -0,0 +1,170 @@
+package tune
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/TheSlipper/isa/evolalg/stats"
+)
+
+// amount returns the amount of the candidates given in the spec or the one fitted to the budget if
+// each of them costs about cost runs.
+func (t *tuner) amount(cost int) int {
+	if t.spec.Candidates > 0 {
+		return t.spec.Candidates
+	}
+	n := t.spec.Budget / cost
+	if n < 2 {
+		n = 2
+	}
+	return n
+}
+
+// random evaluates random candidates on Repetitions instances each.
+func (t *tuner) random(ctx context.Context) error {
+	r := t.spec.Repetitions
+	n := t.amount(r)
+	if n*r > t.spec.Budget {
+		n = t.spec.Budget / r
+	}
+	return t.evaluate(ctx, t.sample(n), r)
+}
+
+// halving runs successive halving: the candidates are evaluated on Repetitions instances, the better
+// half of them (by the mean ranks) survives and is evaluated on twice as many instances, and so on
+// until a single candidate is left or the budget runs out.
+func (t *tuner) halving(ctx context.Context) error {
+	// every round costs about half of the initial one, which is repeated log2(n) times
+	r := t.spec.Repetitions
+	survivors := t.sample(t.amount(3 * r))
+	for instances := r; ; instances *= 2 {
+		err := t.evaluate(ctx, survivors, instances)
+		if err != nil {
+			return err
+		} else if len(survivors) == 1 {
+			return nil
+		}
+		survivors = best(survivors, (len(survivors)+1)/2)
+	}
+}
+
+// best returns the n candidates with the lowest mean ranks.
+func best(cands []*Candidate, n int) []*Candidate {
+	ranks, _ := meanRanks(cands)
+	idx := make([]int, len(cands))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return ranks[idx[i]] < ranks[idx[j]] })
+	res := make([]*Candidate, n)
+	for i := range res {
+		res[i] = cands[idx[i]]
+	}
+	return res
+}
+
+// race runs an F-race: the candidates are evaluated instance by instance and, starting from
+// Repetitions instances, whenever the Friedman test finds a difference between them the ones
+// significantly worse than the best by the Wilcoxon test are dropped. The race ends with a single
+// candidate left or when the budget runs out.
+func (t *tuner) race(ctx context.Context) error {
+	r := t.spec.Repetitions
+	survivors := t.sample(t.amount(3 * r))
+	for instances := 1; len(survivors) > 1; instances++ {
+		err := t.evaluate(ctx, survivors, instances)
+		if err != nil {
+			return err
+		} else if instances < r {
+			continue
+		}
+		ranks, p := meanRanks(survivors)
+		if p >= t.spec.Alpha {
+			continue
+		}
+		top := 0
+		for j := range ranks {
+			if ranks[j] < ranks[top] {
+				top = j
+			}
+		}
+		var next []*Candidate
+		for j, c := range survivors {
+			w, err := stats.Wilcoxon(c.Scores, survivors[top].Scores)
+			if j == top || err != nil || w.P >= t.spec.Alpha {
+				next = append(next, c)
+			}
+		}
+		survivors = next
+	}
+	return nil
+}
+
+// metaGA evolves the points of the unit cube of the space with a generational genetic algorithm:
+// binary tournaments, uniform crossover, Gaussian mutation of every coordinate with the probability
+// 1/dimensions and a single elite. The fitness of a point is the mean score of its candidate on
+// Repetitions instances, so the points decoded to already evaluated parameters cost no runs. The
+// evolution ends when the budget runs out or when maxStale generations in a row bring no new
+// candidates.
+func (t *tuner) metaGA(ctx context.Context) error {
+	r := t.spec.Repetitions
+	size := t.spec.Candidates
+	if size == 0 {
+		size = 10
+		if size*r > t.spec.Budget {
+			size = t.spec.Budget / r
+		}
+	}
+	if size < 2 {
+		size = 2
+	}
+	pop := t.sample(size)
+	for len(pop) < size {
+		pop = append(pop, t.candidate(t.space.sample(t.rng)))
+	}
+	dims := float64(len(t.space))
+	for stale := 0; stale < maxStale; {
+		runs := t.runs
+		err := t.evaluate(ctx, pop, r)
+		if err != nil {
+			return err
+		} else if t.runs == runs {
+			stale++
+		} else {
+			stale = 0
+		}
+		elite := pop[0]
+		for _, c := range pop {
+			if c.mean() > elite.mean() {
+				elite = c
+			}
+		}
+		tournament := func() *Candidate {
+			a, b := pop[t.rng.Intn(len(pop))], pop[t.rng.Intn(len(pop))]
+			if a.mean() >= b.mean() {
+				return a
+			}
+			return b
+		}
+		next := []*Candidate{elite}
+		for len(next) < size {
+			a, b := tournament(), tournament()
+			point := make([]float64, len(a.point))
+			for i := range point {
+				point[i] = a.point[i]
+				if t.rng.Intn(2) == 0 {
+					point[i] = b.point[i]
+				}
+				if t.rng.Float64() < 1/dims {
+					point[i] = math.Max(0, math.Min(1, point[i]+0.1*t.rng.NormFloat64()))
+				}
+			}
+			next = append(next, t.candidate(point))
+		}
+		pop = next
+	}
+	return nil
+}
+
+// maxStale is the amount of generations without new candidates after which metaGA stops.
+const maxStale = 20
@@ -0,0 +1,104 @@
+package tune
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TheSlipper/isa/evolalg/sweep"
+)
+
+// Domain is the set of the values of a tuned parameter: either the <Min, Max> range (of integers if
+// Int is set) or the list of Choices.
+type Domain struct {
+	Min, Max float64
+	Int      bool
+	Choices  []string
+}
+
+// ParseDomain parses a domain given as a from:to range or as a list accepted by
+// sweep.ParseValues. A range is of integers if both of its bounds are integers.
+func ParseDomain(s string) (d Domain, err error) {
+	bounds := strings.Split(s, ":")
+	if len(bounds) != 2 {
+		d.Choices, err = sweep.ParseValues(s)
+		return
+	}
+	d.Min, err = strconv.ParseFloat(strings.TrimSpace(bounds[0]), 64)
+	if err == nil {
+		d.Max, err = strconv.ParseFloat(strings.TrimSpace(bounds[1]), 64)
+	}
+	if err != nil {
+		return d, errors.New("range " + s + " has to consist of numbers")
+	} else if d.Min > d.Max {
+		return d, errors.New("lower bound of range " + s + " is greater than the upper one")
+	}
+	d.Int = d.Min == math.Trunc(d.Min) && d.Max == math.Trunc(d.Max)
+	return
+}
+
+// value returns the value of the domain at u in <0, 1>.
+func (d Domain) value(u float64) string {
+	u = math.Max(0, math.Min(1, u))
+	if len(d.Choices) > 0 {
+		i := int(u * float64(len(d.Choices)))
+		if i == len(d.Choices) {
+			i--
+		}
+		return d.Choices[i]
+	}
+	if d.Int {
+		// every integer gets an equal share of <0, 1>
+		i := d.Min + math.Floor(u*(d.Max-d.Min+1))
+		return strconv.Itoa(int(math.Min(i, d.Max)))
+	}
+	return strconv.FormatFloat(d.Min+u*(d.Max-d.Min), 'g', 6, 64)
+}
+
+// Space is the search space of the tuner: the domains of the tuned parameters by their names.
+type Space map[string]Domain
+
+// ParseSpace parses the domains of the parameters.
+func ParseSpace(domains map[string]string) (Space, error) {
+	s := make(Space, len(domains))
+	for name, dom := range domains {
+		d, err := ParseDomain(dom)
+		if err != nil {
+			return nil, errors.New("parameter " + name + ": " + err.Error())
+		}
+		s[name] = d
+	}
+	return s, nil
+}
+
+// names returns the names of the parameters in the alphabetical order, which is also the order of
+// the coordinates of the points of the space.
+func (s Space) names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// decode returns the parameters at the point u of the unit cube of the space.
+func (s Space) decode(u []float64) map[string]string {
+	params := make(map[string]string, len(s))
+	for i, name := range s.names() {
+		params[name] = s[name].value(u[i])
+	}
+	return params
+}
+
+// sample returns a random point of the unit cube of the space.
+func (s Space) sample(rng *rand.Rand) []float64 {
+	u := make([]float64, len(s))
+	for i := range u {
+		u[i] = rng.Float64()
+	}
+	return u
+}
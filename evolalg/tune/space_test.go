@@ -0,0 +1,38 @@
+package tune
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseDomain(t *testing.T) {
+	d, err := ParseDomain("10:20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Int || d.value(0) != "10" || d.value(1) != "20" || d.value(0.5) != "15" {
+		t.Log(fmt.Sprintf("invalid integer domain %+v", d))
+		t.Fail()
+	}
+	d, _ = ParseDomain("0.5:1")
+	if d.Int || d.value(0.5) != "0.75" {
+		t.Log(fmt.Sprintf("invalid real domain %+v", d))
+		t.Fail()
+	}
+	d, _ = ParseDomain("roulette,tournament,rank")
+	if d.value(0) != "roulette" || d.value(0.5) != "tournament" || d.value(1) != "rank" {
+		t.Log(fmt.Sprintf("invalid choices %+v", d))
+		t.Fail()
+	}
+	d, _ = ParseDomain("10:30:10")
+	if len(d.Choices) != 3 {
+		t.Log(fmt.Sprintf("range with a step wasn't expanded: %+v", d))
+		t.Fail()
+	}
+	for _, s := range []string{"2:1", "a:b", ""} {
+		if _, err := ParseDomain(s); err == nil {
+			t.Log(fmt.Sprintf("invalid domain %q was accepted", s))
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,400 @@
+// Package tune searches the parameters of the solvers of the evolalg registry for the configuration
+// giving the best results (meta-optimization). Candidate configurations are compared on common
+// instances: the instance i is the function Functions[i mod len(Functions)] solved with the seed
+// Seed + i, so every candidate meets the same problems and random numbers. The search is limited by a
+// budget of solver runs and ends with the ranked list of the candidates with the confidence intervals
+// of their results and the p-values of their differences from the best one.
+package tune
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/TheSlipper/isa/evolalg"
+	"github.com/TheSlipper/isa/evolalg/benchmarks"
+	"github.com/TheSlipper/isa/evolalg/stats"
+)
+
+// Method is the search method of the tuner.
+type Method int
+
+const (
+	// RandomSearch evaluates random candidates on Repetitions instances each.
+	RandomSearch Method = iota
+	// SuccessiveHalving starts with Repetitions instances and in every round keeps the better half of
+	// the candidates while doubling the instances.
+	SuccessiveHalving
+	// Race evaluates the candidates instance by instance and after Repetitions instances drops the
+	// ones significantly worse than the best (Friedman test followed by Wilcoxon tests, as in F-race).
+	Race
+	// MetaGA evolves the candidates with a real-coded genetic algorithm whose fitness is the mean of
+	// their results on Repetitions instances.
+	MetaGA
+)
+
+// MethodByName returns the method with the given name ("random", "halving", "race" or "metaga").
+func MethodByName(name string) (Method, error) {
+	switch name {
+	case "random", "":
+		return RandomSearch, nil
+	case "halving":
+		return SuccessiveHalving, nil
+	case "race":
+		return Race, nil
+	case "metaga":
+		return MetaGA, nil
+	}
+	return RandomSearch, errors.New("unknown tuning method " + name)
+}
+
+// String returns the name of the method.
+func (m Method) String() string {
+	return [...]string{"random", "halving", "race", "metaga"}[m]
+}
+
+// Spec describes a tuning task.
+type Spec struct {
+	Algorithm   string            `json:"algorithm"`   // name of the algorithm in the registry ("ga" if empty)
+	Functions   []string          `json:"functions"`   // names of the benchmark functions (["lab"] if empty)
+	Dim         int               `json:"dim"`         // dimensionality of the functions (the default one of each function if 0)
+	Space       map[string]string `json:"space"`       // domains of the tuned parameters (see ParseDomain)
+	Fixed       map[string]string `json:"fixed"`       // values of the parameters that aren't tuned
+	Method      string            `json:"method"`      // name of the method ("random" if empty)
+	Budget      int               `json:"budget"`      // maximal amount of solver runs (200 if 0)
+	Repetitions int               `json:"repetitions"` // instances of every candidate (5 if 0)
+	Candidates  int               `json:"candidates"`  // amount of the candidates or the population of metaga (fitted to the budget if 0)
+	Seed        int64             `json:"seed"`        // seed of the first instance and of the sampling of the candidates
+	Alpha       float64           `json:"alpha"`       // significance level of the tests (0.05 if 0)
+	Confidence  float64           `json:"confidence"`  // level of the confidence intervals (0.95 if 0)
+}
+
+// Candidate is a configuration evaluated by the tuner.
+type Candidate struct {
+	Params  map[string]string `json:"params"`  // values of the tuned parameters
+	Scores  []float64         `json:"scores"`  // best grades found on the instances 0, 1, ...
+	Summary stats.Summary     `json:"summary"` // statistics of the scores
+	P       float64           `json:"p"`       // p-value of the difference from the best candidate (Wilcoxon test on the common instances)
+
+	point []float64 // point of the unit cube of the space
+}
+
+// key returns the string identifying the parameters of the candidate.
+func (c *Candidate) key() string {
+	var parts []string
+	for name, val := range c.Params {
+		parts = append(parts, name+"="+val)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "|")
+}
+
+// mean returns the mean score of the candidate.
+func (c *Candidate) mean() float64 {
+	sum := 0.0
+	for _, s := range c.Scores {
+		sum += s
+	}
+	return sum / float64(len(c.Scores))
+}
+
+// Result is the outcome of tuning.
+type Result struct {
+	Method     string      `json:"method"`
+	Runs       int         `json:"runs"`       // solver runs used out of the budget
+	Candidates []Candidate `json:"candidates"` // ranked from the best
+}
+
+// errBudget is returned by the evaluator if the runs don't fit into the budget.
+var errBudget = errors.New("budget exhausted")
+
+// tuner contains the state of a tuning task.
+type tuner struct {
+	spec     Spec
+	space    Space
+	alg      evolalg.Algorithm
+	problems []evolalg.Problem // problems of the functions
+	workers  int
+	rng      *rand.Rand
+
+	runs       int                   // runs made so far
+	candidates map[string]*Candidate // candidates evaluated so far by their keys
+	order      []*Candidate          // candidates in the order of their creation
+}
+
+// Run tunes the parameters of the spec with the given amount of parallel runs (1 if 0).
+func Run(ctx context.Context, spec Spec, workers int) (res Result, err error) {
+	t, err := newTuner(spec, workers)
+	if err != nil {
+		return
+	}
+	method, err := MethodByName(spec.Method)
+	if err != nil {
+		return
+	}
+	switch method {
+	case RandomSearch:
+		err = t.random(ctx)
+	case SuccessiveHalving:
+		err = t.halving(ctx)
+	case Race:
+		err = t.race(ctx)
+	case MetaGA:
+		err = t.metaGA(ctx)
+	}
+	if err != nil && err != errBudget {
+		return
+	} else if len(t.order) == 0 || len(t.order[0].Scores) == 0 {
+		return res, errors.New("budget is too low to evaluate any candidate")
+	}
+	return t.result(method), nil
+}
+
+// newTuner checks the spec and fills in its defaults.
+func newTuner(spec Spec, workers int) (t *tuner, err error) {
+	t = &tuner{spec: spec, workers: workers, candidates: make(map[string]*Candidate)}
+	if t.spec.Algorithm == "" {
+		t.spec.Algorithm = "ga"
+	}
+	if len(t.spec.Functions) == 0 {
+		t.spec.Functions = []string{"lab"}
+	}
+	if t.spec.Budget == 0 {
+		t.spec.Budget = 200
+	}
+	if t.spec.Repetitions == 0 {
+		t.spec.Repetitions = 5
+	}
+	if t.spec.Alpha == 0 {
+		t.spec.Alpha = 0.05
+	}
+	if t.workers <= 0 {
+		t.workers = 1
+	}
+	if t.spec.Budget < 0 || t.spec.Repetitions < 0 || t.spec.Candidates < 0 {
+		return nil, errors.New("budget, repetitions and candidates can't be negative")
+	} else if t.spec.Alpha < 0 || t.spec.Alpha >= 1 || t.spec.Confidence < 0 || t.spec.Confidence >= 1 {
+		return nil, errors.New("significance and confidence levels have to be in <0, 1) set")
+	} else if len(t.spec.Space) == 0 {
+		return nil, errors.New("no parameter is tuned")
+	}
+
+	t.alg, err = evolalg.AlgorithmByName(t.spec.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool)
+	for _, p := range t.alg.Schema() {
+		known[p.Name] = true
+	}
+	for name := range t.spec.Space {
+		if !known[name] || t.spec.Fixed[name] != "" || name == "ziarno" {
+			return nil, errors.New("parameter " + name + " can't be tuned")
+		}
+	}
+	for name := range t.spec.Fixed {
+		if !known[name] || name == "ziarno" {
+			return nil, errors.New("parameter " + name + " can't be fixed")
+		}
+	}
+	t.space, err = ParseSpace(t.spec.Space)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range t.spec.Functions {
+		fn, err := benchmarks.ByName(name)
+		if err != nil {
+			return nil, err
+		}
+		dim := t.spec.Dim
+		if dim == 0 {
+			dim = fn.DefaultDim
+		}
+		p, err := fn.Problem(dim)
+		if err != nil {
+			return nil, err
+		}
+		t.problems = append(t.problems, p)
+	}
+	t.rng = rand.New(rand.NewSource(t.spec.Seed))
+	return t, nil
+}
+
+// candidate returns the candidate of the point, reusing the one with equal parameters if it was
+// already created.
+func (t *tuner) candidate(point []float64) *Candidate {
+	c := &Candidate{Params: t.space.decode(point), point: point}
+	if old, ok := t.candidates[c.key()]; ok {
+		return old
+	}
+	t.candidates[c.key()] = c
+	t.order = append(t.order, c)
+	return c
+}
+
+// sample returns n random candidates (fewer if some of them are equal).
+func (t *tuner) sample(n int) []*Candidate {
+	var res []*Candidate
+	seen := make(map[*Candidate]bool)
+	for i := 0; i < n; i++ {
+		c := t.candidate(t.space.sample(t.rng))
+		if !seen[c] {
+			seen[c] = true
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// evaluate runs the candidates on the instances they weren't run on yet, up to the given amount of
+// instances. Nothing is run and errBudget is returned if the runs don't fit into the budget.
+func (t *tuner) evaluate(ctx context.Context, cands []*Candidate, instances int) error {
+	type job struct {
+		c        *Candidate
+		instance int
+	}
+	var jobs []job
+	seen := make(map[*Candidate]bool)
+	for _, c := range cands {
+		if seen[c] {
+			continue // populations of metaGA can contain a candidate more than once
+		}
+		seen[c] = true
+		for i := len(c.Scores); i < instances; i++ {
+			jobs = append(jobs, job{c, i})
+		}
+	}
+	if t.runs+len(jobs) > t.spec.Budget {
+		return errBudget
+	}
+	for _, c := range cands {
+		for len(c.Scores) < instances {
+			c.Scores = append(c.Scores, 0)
+		}
+	}
+
+	ch := make(chan job)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	for w := 0; w < t.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range ch {
+				score, err := t.runInstance(ctx, j.c.Params, j.instance)
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				j.c.Scores[j.instance] = score
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, j := range jobs {
+		ch <- j
+	}
+	close(ch)
+	wg.Wait()
+	t.runs += len(jobs)
+	return firstErr
+}
+
+// runInstance runs the solver with the parameters on the instance and returns the best grade found.
+func (t *tuner) runInstance(ctx context.Context, tuned map[string]string, instance int) (float64, error) {
+	s, err := t.alg.New(t.problems[instance%len(t.problems)])
+	if err != nil {
+		return 0, err
+	}
+	params := evolalg.Params{"ziarno": strconv.FormatInt(t.spec.Seed+int64(instance), 10)}
+	for name, val := range t.spec.Fixed {
+		params[name] = val
+	}
+	for name, val := range tuned {
+		params[name] = val
+	}
+	err = s.Configure(params)
+	if err != nil {
+		return 0, err
+	}
+	hist, err := s.Run(ctx)
+	if err != nil {
+		return 0, err
+	}
+	best := hist[0].FMax
+	for _, ed := range hist {
+		if ed.FMax > best {
+			best = ed.FMax
+		}
+	}
+	return best, nil
+}
+
+// meanRanks returns the mean ranks (1 is the best) of the candidates on the instances which all of
+// them were run on, together with the p-value of the Friedman test of their differences.
+func meanRanks(cands []*Candidate) (ranks []float64, p float64) {
+	n := len(cands[0].Scores)
+	for _, c := range cands {
+		if len(c.Scores) < n {
+			n = len(c.Scores)
+		}
+	}
+	blocks := make([][]float64, n)
+	for i := range blocks {
+		blocks[i] = make([]float64, len(cands))
+		for j, c := range cands {
+			blocks[i][j] = c.Scores[i]
+		}
+	}
+	res, err := stats.Friedman(blocks)
+	if err != nil {
+		// a single candidate or a single instance can't be tested, so the ranks are based on the means
+		ranks = make([]float64, len(cands))
+		for j, c := range cands {
+			for _, other := range cands {
+				if other.mean() > c.mean() {
+					ranks[j]++
+				}
+			}
+		}
+		return ranks, 1
+	}
+	return res.Ranks, res.P
+}
+
+// result ranks the evaluated candidates: the ones run on more instances (survivors of halving and
+// racing) come first and the ties are broken by the mean score.
+func (t *tuner) result(m Method) Result {
+	var cands []*Candidate
+	for _, c := range t.order {
+		if len(c.Scores) > 0 {
+			cands = append(cands, c)
+		}
+	}
+	sort.SliceStable(cands, func(i, j int) bool {
+		if len(cands[i].Scores) != len(cands[j].Scores) {
+			return len(cands[i].Scores) > len(cands[j].Scores)
+		}
+		return cands[i].mean() > cands[j].mean()
+	})
+
+	res := Result{Method: m.String(), Runs: t.runs}
+	best := cands[0]
+	for _, c := range cands {
+		c.Summary, _ = stats.Summarize(c.Scores, t.spec.Confidence)
+		c.P = 1
+		if c != best {
+			w, err := stats.Wilcoxon(c.Scores, best.Scores[:len(c.Scores)])
+			if err == nil {
+				c.P = w.P
+			}
+		}
+		res.Candidates = append(res.Candidates, *c)
+	}
+	return res
+}
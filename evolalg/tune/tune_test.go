@@ -0,0 +1,66 @@
+package tune
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	for _, method := range []string{"random", "halving", "race", "metaga"} {
+		spec := Spec{
+			Space:  map[string]string{"N": "4:30", "Pk": "0.75:1"},
+			Fixed:  map[string]string{"epoki": "5"},
+			Method: method,
+			Budget: 60,
+			Seed:   3,
+		}
+		res, err := Run(context.Background(), spec, 4)
+		if err != nil {
+			t.Log(fmt.Sprintf("%s: %s", method, err.Error()))
+			t.Fail()
+			continue
+		}
+		if res.Runs == 0 || res.Runs > 60 || res.Method != method || len(res.Candidates) < 2 {
+			t.Log(fmt.Sprintf("%s: %d runs and %d candidates", method, res.Runs, len(res.Candidates)))
+			t.Fail()
+			continue
+		}
+		runs := 0
+		for i, c := range res.Candidates {
+			runs += len(c.Scores)
+			if c.Summary.N != len(c.Scores) || c.Params["N"] == "" {
+				t.Log(fmt.Sprintf("%s: invalid candidate %+v", method, c))
+				t.Fail()
+			}
+			if i > 0 && len(c.Scores) == len(res.Candidates[0].Scores) && c.Summary.Mean > res.Candidates[0].Summary.Mean {
+				t.Log(fmt.Sprintf("%s: candidate %d is better than the first one", method, i))
+				t.Fail()
+			}
+		}
+		if runs != res.Runs || res.Candidates[0].P != 1 {
+			t.Log(fmt.Sprintf("%s: %d scores for %d runs, p of the best %f", method, runs, res.Runs, res.Candidates[0].P))
+			t.Fail()
+		}
+
+		// The results are repeatable
+		again, _ := Run(context.Background(), spec, 1)
+		if again.Runs != res.Runs || again.Candidates[0].Summary.Mean != res.Candidates[0].Summary.Mean {
+			t.Log(fmt.Sprintf("%s: runs with the same seed differ", method))
+			t.Fail()
+		}
+	}
+
+	for _, spec := range []Spec{
+		{},
+		{Space: map[string]string{"x": "1:2"}},
+		{Space: map[string]string{"N": "1:2"}, Fixed: map[string]string{"N": "3"}},
+		{Space: map[string]string{"N": "4:8"}, Method: "x"},
+		{Space: map[string]string{"N": "4:8"}, Budget: 2},
+	} {
+		if _, err := Run(context.Background(), spec, 1); err == nil {
+			t.Log(fmt.Sprintf("invalid spec %+v was accepted", spec))
+			t.Fail()
+		}
+	}
+}
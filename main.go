@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,12 +10,27 @@ import (
 )
 
 func main() {
+	modelsPath := flag.String("modele", "", "plik JSON z zewnętrznymi programami oceny udostępnionymi w formularzu")
+	flag.Parse()
+	if *modelsPath != "" {
+		err := loadModels(*modelsPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer closeModels()
+		log.Printf("Loaded %d external models\n", len(modelNames()))
+	}
+
 	// Start the server on given port
 	port := 8080
 	log.Println("Registring a root endpoint")
 	fs := http.FileServer(http.Dir("./static/"))
 	http.Handle("/static/", http.StripPrefix("/static/", fs))
 	http.HandleFunc("/", root)
+	http.HandleFunc("/gp", gpRoot)
+	http.HandleFunc("/algorytmy", algorithms)
+	http.HandleFunc("/funkcje", functions)
+	http.HandleFunc("/strojenie", tuning)
 	go http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
 	log.Printf("Started a server on :%d\n", port)
 
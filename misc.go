@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 )
 
 func throwErr(w http.ResponseWriter, r *http.Request, err error, code int) {
@@ -20,3 +21,22 @@ func getGETParam(key string, w http.ResponseWriter, r *http.Request) (val string
 	}
 	return
 }
+
+// getGETInt returns the GET parameter converted to an integer or def if the parameter was not given.
+func getGETInt(key string, def int, w http.ResponseWriter, r *http.Request) (int, error) {
+	val := getGETParam(key, w, r)
+	if val == "" {
+		return def, nil
+	}
+	return strconv.Atoi(val)
+}
+
+// getGETFloat returns the GET parameter converted to a floating point number or def if the
+// parameter was not given.
+func getGETFloat(key string, def float64, w http.ResponseWriter, r *http.Request) (float64, error) {
+	val := getGETParam(key, w, r)
+	if val == "" {
+		return def, nil
+	}
+	return strconv.ParseFloat(val, 64)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TheSlipper/isa/evolalg"
+	"github.com/TheSlipper/isa/evolalg/external"
+)
+
+// modelSpec to opis zewnętrznego programu oceny z pliku podanego flagą -modele, np.
+//
+//	{"sinus": {"command": ["python3", "sinus.py"], "processes": 4, "timeout": "5s"}}
+type modelSpec struct {
+	Command   []string `json:"command"`   // program i jego argumenty
+	Dir       string   `json:"dir"`       // katalog roboczy procesów (pusty - bieżący)
+	Processes int      `json:"processes"` // liczba równolegle oceniających procesów (0 - jeden)
+	Timeout   string   `json:"timeout"`   // limit czasu jednej oceny, np. "5s" (pusty - brak)
+}
+
+// models zawiera programy oceny udostępnione w formularzu. Ich procesy są uruchamiane przy pierwszej
+// ocenie i pozostają aktywne między zapytaniami aż do zamknięcia serwera.
+var (
+	models   = map[string]*external.Evaluator{}
+	modelsMu sync.Mutex
+)
+
+// loadModels wczytuje programy oceny z pliku JSON. Nazwy programów pochodzą od użytkownika formularza,
+// a polecenia tylko z pliku, więc serwer nie uruchamia dowolnych poleceń.
+func loadModels(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var specs map[string]modelSpec
+	err = json.NewDecoder(f).Decode(&specs)
+	if err != nil {
+		return err
+	}
+
+	modelsMu.Lock()
+	defer modelsMu.Unlock()
+	for name, spec := range specs {
+		cfg := external.Config{Command: spec.Command, Dir: spec.Dir, Processes: spec.Processes}
+		if spec.Timeout != "" {
+			cfg.Timeout, err = time.ParseDuration(spec.Timeout)
+			if err != nil {
+				return err
+			}
+		}
+		models[name], err = external.NewEvaluator(cfg)
+		if err != nil {
+			return errors.New("model " + name + ": " + err.Error())
+		}
+	}
+	return nil
+}
+
+// closeModels zatrzymuje procesy wszystkich programów oceny.
+func closeModels() {
+	modelsMu.Lock()
+	defer modelsMu.Unlock()
+	for _, ev := range models {
+		ev.Close()
+	}
+}
+
+// modelNames zwraca posortowane nazwy programów oceny.
+func modelNames() (names []string) {
+	modelsMu.Lock()
+	defer modelsMu.Unlock()
+	for name := range models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// modelProblem tworzy problem maksymalizacji oceny zewnętrznego programu na przedziale <a, b>.
+func modelProblem(name string, a, b float64) (evolalg.Problem, error) {
+	modelsMu.Lock()
+	ev, ok := models[name]
+	modelsMu.Unlock()
+	if !ok {
+		return evolalg.Problem{}, errors.New("nieznany model " + name)
+	}
+	return evolalg.Problem{Lower: []float64{a}, Upper: []float64{b}, Fitness: ev, Name: "model/" + name}, nil
+}
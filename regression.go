@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/TheSlipper/isa/evolalg"
+	"github.com/TheSlipper/isa/evolalg/gp"
+)
+
+// gpRoot pobiera plik strony gp.html z dysku i prezentuje go przeglądarce wraz z wynikami regresji
+// symbolicznej próbek podanych w formacie CSV.
+func gpRoot(w http.ResponseWriter, r *http.Request) {
+	data, nStr, epochsStr := getGETParam("dane", w, r), getGETParam("N", w, r), getGETParam("epoki", w, r)
+	jsonFormat := getGETParam("json", w, r)
+
+	// Run the genetic programming if all the necessary data was given
+	var hist []gp.Generation
+	if data != "" && nStr != "" && epochsStr != "" {
+		samples, err := gp.LoadSamples(strings.NewReader(data))
+		if err != nil {
+			throwErr(w, r, err, http.StatusInternalServerError)
+			return
+		}
+		N, err := strconv.Atoi(nStr)
+		if err != nil {
+			throwErr(w, r, err, http.StatusInternalServerError)
+			return
+		}
+		epochs, err := strconv.Atoi(epochsStr)
+		if err != nil {
+			throwErr(w, r, err, http.StatusInternalServerError)
+			return
+		}
+		hist, err = solveGP(samples, N, epochs, w, r)
+		if err != nil {
+			throwErr(w, r, err, http.StatusInternalServerError)
+			return
+		}
+
+		// Create the fitness graph and the graph of the best expression against the data
+		epochData := make([]evolalg.EpochData, len(hist))
+		for i := range hist {
+			epochData[i] = hist[i].EpochData
+		}
+		err = renderChart("static/gp_fitness.svg", fitnessChart(epochData))
+		if err != nil {
+			throwErr(w, r, err, http.StatusInternalServerError)
+			return
+		}
+		err = renderChart("static/gp_fit.svg", regressionChart(samples, hist[len(hist)-1].BestTree))
+		if err != nil {
+			throwErr(w, r, err, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// generate template and process it
+	if jsonFormat == "" {
+		t, err := template.New("gp.html").Funcs(templateFuncs).ParseFiles("gp.html")
+		if err != nil {
+			throwErr(w, r, err, http.StatusInternalServerError)
+			return
+		}
+		err = t.Execute(w, hist)
+		if err != nil {
+			throwErr(w, r, err, http.StatusInternalServerError)
+			return
+		}
+	} else {
+		byteArr, err := json.Marshal(hist)
+		if err != nil {
+			throwErr(w, r, err, http.StatusInternalServerError)
+			return
+		}
+		w.Write(byteArr)
+	}
+}
+
+// solveGP konfiguruje programowanie genetyczne na podstawie parametrów GET i dopasowuje nim wyrażenie
+// do próbek.
+func solveGP(samples []gp.Sample, N, epochs int, w http.ResponseWriter, r *http.Request) (hist []gp.Generation, err error) {
+	s, err := gp.NewSymbolicRegressionSolver(samples)
+	if err != nil {
+		return nil, err
+	}
+	cfg := gp.Config{Parsimony: getGETParam("parsymonia", w, r) != ""}
+	cfg.Functions, err = gp.FunctionsByName(getGETParam("funkcje", w, r))
+	if err != nil {
+		return nil, err
+	}
+	cfg.ConstRange, err = getGETFloat("stale", 0, w, r)
+	if err != nil {
+		return nil, err
+	}
+	cfg.InitDepth, err = getGETInt("glebokosc", 0, w, r)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MaxDepth, err = getGETInt("max_glebokosc", 0, w, r)
+	if err != nil {
+		return nil, err
+	}
+	cfg.TournamentSize, err = getGETInt("turniej", 0, w, r)
+	if err != nil {
+		return nil, err
+	}
+	cfg.CrossoverProb, err = getGETFloat("Pk", 0, w, r)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MutationProb, err = getGETFloat("Pm", 0, w, r)
+	if err != nil {
+		return nil, err
+	}
+	err = s.SetConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if seedStr := getGETParam("ziarno", w, r); seedStr != "" {
+		seed, err := strconv.ParseInt(seedStr, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		s.SetSeed(seed)
+	}
+	term, err := termination(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	s.SetTermination(term)
+	return s.Solve(N, epochs)
+}
@@ -2,165 +2,256 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"html/template"
-	"math"
 	"net/http"
-	"os"
+	"net/url"
 	"strconv"
 
 	"github.com/TheSlipper/isa/evolalg"
-	chart "github.com/wcharczuk/go-chart/v2"
+	"github.com/TheSlipper/isa/evolalg/benchmarks"
+	"github.com/TheSlipper/isa/evolalg/gp"
 )
 
+// templateFuncs zawiera funkcje pomocnicze dostępne w szablonach stron.
+var templateFuncs = template.FuncMap{
+	"last": func(hist []evolalg.EpochData) evolalg.EpochData {
+		return hist[len(hist)-1]
+	},
+	"lastGeneration": func(hist []gp.Generation) gp.Generation {
+		return hist[len(hist)-1]
+	},
+	"paramValue": func(values url.Values, p evolalg.Param) string {
+		if vals, ok := values[p.Name]; ok {
+			return vals[0]
+		} else if len(values) > 0 && p.Kind == evolalg.BoolParam {
+			return ""
+		}
+		return p.Default
+	},
+	"deRates": deRates,
+	"adaptive": func(hist []evolalg.EpochData) bool {
+		for i := 2; i < len(hist); i++ {
+			if hist[i].CP != hist[1].CP || hist[i].MP != hist[1].MP || hist[i].CR != hist[1].CR ||
+				hist[i].F != hist[1].F {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// rootPage zawiera dane strony root.html.
+type rootPage struct {
+	Algorithms []evolalg.Algorithm   // algorytmy z rejestru, których formularze są wyświetlane
+	Algorithm  string                // wybrany algorytm
+	Functions  []benchmarks.Function // funkcje testowe do wyboru
+	Function   benchmarks.Function   // wybrana funkcja testowa
+	Dim        int                   // liczba wymiarów problemu
+	Values     url.Values            // parametry wysłane w formularzu
+	Hist       []evolalg.EpochData   // historia wykonania algorytmu (nil przed obliczeniami)
+
+	Genotypes []string // nazwy problemów binarnych do wyboru
+	Genotype  string   // wybrany problem binarny (pusty, jeśli wybrano funkcję testową)
+	Models    []string // nazwy zewnętrznych programów oceny do wyboru
+	Model     string   // wybrany program oceny (pusty, jeśli wybrano funkcję testową)
+
+	Optima     [][]float64 // położenia znanych optimów funkcji (nil, jeśli nieznane)
+	Optimum    float64     // ocena znanego optimum funkcji
+	HasOptimum bool        // optimum funkcji jest znane
+}
+
+// optimumGenotype to problem binarny o znanej najwyższej wartości dopasowania.
+type optimumGenotype interface {
+	Optimum() float64
+}
+
+// genotype tworzy problem binarny wybrany parametrem genotyp na podstawie parametrów dlugosc, blok i
+// ziarno_nk.
+func genotype(name string, w http.ResponseWriter, r *http.Request) (p evolalg.Problem, err error) {
+	l, err := getGETInt("dlugosc", 16, w, r)
+	if err != nil {
+		return
+	}
+	k, err := getGETInt("blok", 4, w, r)
+	if err != nil {
+		return
+	}
+	seed, err := getGETInt("ziarno_nk", 1, w, r)
+	if err != nil {
+		return
+	}
+	p.Name = fmt.Sprintf("%s/%d/%d/%d", name, l, k, seed)
+	p.Genotype, err = benchmarks.GenotypeByName(name, l, k, int64(seed))
+	return
+}
+
+// environment dodaje do problemu szum ocen i dryf krajobrazu funkcji wybrane parametrami szum, dryf
+// i okres_dryfu.
+func environment(p evolalg.Problem, w http.ResponseWriter, r *http.Request) (evolalg.Problem, error) {
+	noise, err := getGETFloat("szum", 0, w, r)
+	if err != nil {
+		return p, err
+	}
+	drift, err := getGETFloat("dryf", 0, w, r)
+	if err != nil {
+		return p, err
+	}
+	period, err := getGETInt("okres_dryfu", 10, w, r)
+	if err != nil {
+		return p, err
+	}
+	if noise == 0 && drift == 0 {
+		return p, nil
+	} else if p.Genotype != nil || p.Fitness != nil {
+		return p, errors.New("szum i dryf są dostępne tylko dla funkcji testowych")
+	}
+	if drift != 0 {
+		p = benchmarks.WithDrift(p, drift, period)
+	}
+	if noise != 0 {
+		p = benchmarks.WithNoise(p, noise)
+	}
+	return p, nil
+}
+
 // root pobiera plik strony root.html z dysku i prezentuje go przeglądarce.
 func root(w http.ResponseWriter, r *http.Request) {
 	// Get the GET params
-	generate := true
-	nStr, aStr, bStr, dStr := getGETParam("N", w, r), getGETParam("a", w, r), getGETParam("b", w, r), getGETParam("d", w, r)
-	cpStr, mpStr, epochsStr := getGETParam("Pk", w, r), getGETParam("Pm", w, r), getGETParam("epoki", w, r)
-	if nStr == "" || aStr == "" || bStr == "" || dStr == "" || cpStr == "" || mpStr == "" || epochsStr == "" {
-		generate = false
+	algorithm := getGETParam("algorytm", w, r)
+	generate := algorithm != "" || getGETParam("epoki", w, r) != ""
+	if algorithm == "" {
+		algorithm = "ga"
 	}
 	jsonFormat := getGETParam("json", w, r)
+	fnName := getGETParam("funkcja", w, r)
+	if fnName == "" {
+		fnName = "lab"
+	}
+	fn, err := benchmarks.ByName(fnName)
+	if err != nil {
+		throwErr(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	n, err := getGETInt("wymiar", fn.DefaultDim, w, r)
+	if err != nil {
+		throwErr(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	genotypeName := getGETParam("genotyp", w, r)
+	modelName := getGETParam("model", w, r)
 
 	// Generate the values if all the necessary data was given
 	var hist []evolalg.EpochData
 	if generate {
 		// Convert to values
-		N, err := strconv.Atoi(nStr)
+		a, err := getGETFloat("a", fn.Lower, w, r)
 		if err != nil {
 			throwErr(w, r, err, http.StatusInternalServerError)
 			return
 		}
-		dInt, err := strconv.Atoi(dStr)
+		b, err := getGETFloat("b", fn.Upper, w, r)
 		if err != nil {
 			throwErr(w, r, err, http.StatusInternalServerError)
 			return
 		}
-		d := byte(dInt)
-		a, err := strconv.ParseFloat(aStr, 64)
-		if err != nil {
-			throwErr(w, r, err, http.StatusInternalServerError)
-			return
+		var problem evolalg.Problem
+		if genotypeName != "" && modelName != "" {
+			err = errors.New("wybierz problem binarny albo model zewnętrzny")
+		} else if genotypeName != "" {
+			problem, err = genotype(genotypeName, w, r)
+		} else if modelName != "" {
+			problem, err = modelProblem(modelName, a, b)
+		} else {
+			problem, err = fn.ProblemIn(n, a, b)
 		}
-		b, err := strconv.ParseFloat(bStr, 64)
-		if err != nil {
-			throwErr(w, r, err, http.StatusInternalServerError)
-			return
+		if err == nil {
+			problem, err = environment(problem, w, r)
 		}
-		cp, err := strconv.ParseFloat(cpStr, 64)
 		if err != nil {
 			throwErr(w, r, err, http.StatusInternalServerError)
 			return
 		}
-		mp, err := strconv.ParseFloat(mpStr, 64)
+		f1 := func(x float64) float64 { return problem.F([]float64{x}) }
+
+		// Run the chosen algorithm
+		hist, err = solve(algorithm, problem, w, r)
 		if err != nil {
 			throwErr(w, r, err, http.StatusInternalServerError)
 			return
 		}
-		epochs, err := strconv.Atoi(epochsStr)
+
+		// Create the fmax, favg, fmin graph and the diversity graph
+		err = renderChart("static/fmax_favg_fmin.svg", fitnessChart(hist))
 		if err != nil {
 			throwErr(w, r, err, http.StatusInternalServerError)
 			return
 		}
-
-		// solver for this assignment with a grading function described by this formula:
-		// F(x)= x MOD1 *(COS(20*π *x)–SIN(x))
-		// d = 0,001 -> 3
-		gas, err := evolalg.NewGeneticAlgorithmSolver(a, b, d, func(x float64) float64 {
-			return math.Mod(x, 1*(math.Cos(20*math.Pi*x)-math.Sin(x)))
-		})
+		err = renderChart("static/diversity.svg", diversityChart(hist))
 		if err != nil {
 			throwErr(w, r, err, http.StatusInternalServerError)
 			return
 		}
-
-		hist, err = gas.Solve(N, epochs, cp, mp)
+		err = renderChart("static/rates.svg", ratesChart(hist))
 		if err != nil {
 			throwErr(w, r, err, http.StatusInternalServerError)
 			return
 		}
-
-		// Create the fmax, favg, fmin graph
-		fmax, favg, fmin := make([]float64, len(hist)), make([]float64, len(hist)), make([]float64, len(hist))
-		epochsArr := make([]float64, len(hist))
-		ticker := len(hist) / 20
-		epochTicks := []chart.Tick{}
-		i := 0
-		for ; i < len(hist); i++ {
-			fmax[i] = hist[i].FMax
-			favg[i] = hist[i].FAVG
-			fmin[i] = hist[i].FMin
-			epochsArr[i] = float64(i)
-
-			if len(hist) < 20 {
-				epochTicks = append(epochTicks, chart.Tick{Value: float64(i), Label: strconv.Itoa(i)})
-			} else if i%ticker == 0 {
-				epochTicks = append(epochTicks, chart.Tick{Value: float64(i), Label: strconv.Itoa(i)})
+		// The problems defined on genomes have no function of x to be drawn
+		if problem.F != nil && hist[0].Swarm != nil && n == 1 {
+			for i := range hist {
+				err = renderChart("static/swarm/"+strconv.Itoa(i)+".svg", swarmChart(f1, a, b, hist[i]))
+				if err != nil {
+					throwErr(w, r, err, http.StatusInternalServerError)
+					return
+				}
+			}
+		}
+		if problem.F != nil && hist[len(hist)-1].Optima != nil {
+			err = renderChart("static/niches.svg", nicheChart(f1, a, b, hist[len(hist)-1]))
+			if err != nil {
+				throwErr(w, r, err, http.StatusInternalServerError)
+				return
 			}
 		}
-		if epochTicks[len(epochTicks)-1].Value != float64(i) {
-			epochTicks = append(epochTicks, chart.Tick{Value: float64(epochs),
-				Label: strconv.Itoa(epochs)})
-		}
-
-		graph := chart.Chart{
-			XAxis: chart.XAxis{
-				Name: "Epoka",
-				Range: &chart.ContinuousRange{
-					Min: 0.0,
-					Max: float64(epochs),
-				},
-				Ticks: epochTicks,
-			},
-			YAxis: chart.YAxis{
-				Name: "f(x)",
-			},
-			Series: []chart.Series{
-				chart.ContinuousSeries{
-					Name: "fmax",
-					Style: chart.Style{
-						StrokeColor: chart.GetDefaultColor(0).WithAlpha(64),
-						StrokeWidth: 3.5,
-					},
-					XValues: epochsArr,
-					YValues: fmax,
-				},
-				chart.ContinuousSeries{
-					Name: "favg",
-					Style: chart.Style{
-						StrokeColor: chart.GetDefaultColor(1).WithAlpha(64),
-						StrokeWidth: 3.5,
-					},
-					XValues: epochsArr,
-					YValues: favg,
-				},
-				chart.ContinuousSeries{
-					Name: "fmin",
-					Style: chart.Style{
-						StrokeColor: chart.GetDefaultColor(2).WithAlpha(64),
-						StrokeWidth: 3.5,
-					},
-					XValues: epochsArr,
-					YValues: fmin,
-				},
-			},
-		}
-
-		os.Remove("static/fmax_favg_fmin.svg")
-		f, _ := os.Create("static/fmax_favg_fmin.svg")
-		defer f.Close()
-		graph.Render(chart.SVG, f)
 	}
 
 	// generate template and process it
 	if jsonFormat == "" {
-		t, err := template.ParseFiles("root.html")
+		t, err := template.New("root.html").Funcs(templateFuncs).ParseFiles("root.html")
 		if err != nil {
 			throwErr(w, r, err, http.StatusInternalServerError)
 			return
 		}
-		err = t.Execute(w, hist)
+		page := rootPage{
+			Algorithms: evolalg.Algorithms(),
+			Algorithm:  algorithm,
+			Functions:  benchmarks.All(),
+			Function:   fn,
+			Dim:        n,
+			Values:     r.URL.Query(),
+			Hist:       hist,
+			Genotypes:  benchmarks.GenotypeNames,
+			Genotype:   genotypeName,
+			Models:     modelNames(),
+			Model:      modelName,
+		}
+		if genotypeName != "" {
+			p, err := genotype(genotypeName, w, r)
+			if err != nil {
+				throwErr(w, r, err, http.StatusInternalServerError)
+				return
+			}
+			if o, ok := p.Genotype.(optimumGenotype); ok {
+				page.Optimum, page.HasOptimum = o.Optimum(), true
+			}
+		} else if modelName == "" {
+			// The optimum of an external model is unknown
+			page.Optima, page.Optimum, page.HasOptimum = fn.Optima(n)
+			page.Optimum = fn.Grade(page.Optimum)
+		}
+		err = t.Execute(w, page)
 		if err != nil {
 			throwErr(w, r, err, http.StatusInternalServerError)
 			return
@@ -175,3 +266,37 @@ func root(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(200)
 	}
 }
+
+// schemaEntry to opis algorytmu z rejestru zwracany przez algorithms.
+type schemaEntry struct {
+	Name   string          `json:"name"`
+	Label  string          `json:"label"`
+	Params []evolalg.Param `json:"params"`
+}
+
+// algorithms zwraca w formacie JSON listę algorytmów z rejestru wraz z pełnymi schematami ich
+// parametrów.
+func algorithms(w http.ResponseWriter, r *http.Request) {
+	var entries []schemaEntry
+	for _, a := range evolalg.Algorithms() {
+		entries = append(entries, schemaEntry{Name: a.Name, Label: a.Label, Params: a.Schema()})
+	}
+	byteArr, err := json.Marshal(entries)
+	if err != nil {
+		throwErr(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(byteArr)
+}
+
+// functions zwraca w formacie JSON listę funkcji testowych wraz z ich dziedzinami i liczbą wymiarów.
+func functions(w http.ResponseWriter, r *http.Request) {
+	byteArr, err := json.Marshal(benchmarks.All())
+	if err != nil {
+		throwErr(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(byteArr)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/TheSlipper/isa/evolalg"
+)
+
+// solve tworzy solver algorytmu o podanej nazwie z rejestru evolalg, konfiguruje go parametrami GET
+// opisanymi w schemacie algorytmu i rozwiązuje nim podany problem. Obliczenia są przerywane, gdy
+// klient zamknie połączenie.
+func solve(name string, problem evolalg.Problem, w http.ResponseWriter, r *http.Request) (hist []evolalg.EpochData, err error) {
+	alg, err := evolalg.AlgorithmByName(name)
+	if err != nil {
+		return nil, err
+	}
+	s, err := alg.New(problem)
+	if err != nil {
+		return nil, err
+	}
+	params := evolalg.Params{}
+	for _, p := range alg.Schema() {
+		if val := getGETParam(p.Name, w, r); val != "" {
+			params[p.Name] = val
+		}
+	}
+	err = s.Configure(params)
+	if err != nil {
+		return nil, err
+	}
+	return s.Run(r.Context())
+}
+
+// termination odczytuje z parametrów GET warunki wcześniejszego zatrzymania algorytmu.
+func termination(w http.ResponseWriter, r *http.Request) (term evolalg.Termination, err error) {
+	term.MaxEvaluations, err = getGETInt("max_obliczen", 0, w, r)
+	if err != nil {
+		return
+	}
+	term.StagnationEpochs, err = getGETInt("stop_stagnacja", 0, w, r)
+	if err != nil {
+		return
+	}
+	if targetStr := getGETParam("cel", w, r); targetStr != "" {
+		term.Target, err = strconv.ParseFloat(targetStr, 64)
+		term.HasTarget = true
+	}
+	return
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"runtime"
+	"strings"
+
+	"github.com/TheSlipper/isa/evolalg/tune"
+)
+
+// maxTuningBudget ogranicza liczbę uruchomień algorytmu w jednym żądaniu strojenia.
+const maxTuningBudget = 2000
+
+// tuning dobiera parametry algorytmu i zwraca ranking kandydatów w formacie JSON. Dziedziny strojonych
+// parametrów są podawane w powtarzanym parametrze p, a stałe wartości w powtarzanym parametrze s, oba w
+// postaci nazwa=wartość, np. /strojenie?metoda=race&p=N=10:60&p=Pk=0.75:1&s=epoki=30.
+func tuning(w http.ResponseWriter, r *http.Request) {
+	spec, err := tuningSpec(w, r)
+	if err != nil {
+		throwErr(w, r, err, http.StatusBadRequest)
+		return
+	}
+	res, err := tune.Run(r.Context(), spec, runtime.NumCPU())
+	if err != nil {
+		throwErr(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	byteArr, err := json.Marshal(res)
+	if err != nil {
+		throwErr(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(byteArr)
+}
+
+// tuningSpec odczytuje zadanie strojenia z parametrów GET.
+func tuningSpec(w http.ResponseWriter, r *http.Request) (spec tune.Spec, err error) {
+	spec.Algorithm = getGETParam("algorytm", w, r)
+	if fns := getGETParam("funkcje", w, r); fns != "" {
+		spec.Functions = strings.Split(fns, ",")
+	}
+	spec.Method = getGETParam("metoda", w, r)
+	ints := []struct {
+		name string
+		val  *int
+	}{{"wymiar", &spec.Dim}, {"budzet", &spec.Budget}, {"powtorzenia", &spec.Repetitions}, {"kandydaci", &spec.Candidates}}
+	for _, p := range ints {
+		*p.val, err = getGETInt(p.name, 0, w, r)
+		if err != nil {
+			return
+		}
+	}
+	if spec.Budget > maxTuningBudget {
+		err = errors.New("budżet strojenia jest zbyt duży")
+		return
+	}
+	seed, err := getGETInt("ziarno", 1, w, r)
+	if err != nil {
+		return
+	}
+	spec.Seed = int64(seed)
+	spec.Alpha, err = getGETFloat("alfa", 0, w, r)
+	if err != nil {
+		return
+	}
+
+	spec.Space, spec.Fixed = map[string]string{}, map[string]string{}
+	for key, m := range map[string]map[string]string{"p": spec.Space, "s": spec.Fixed} {
+		for _, pair := range r.URL.Query()[key] {
+			i := strings.Index(pair, "=")
+			if i <= 0 {
+				err = errors.New("parametr " + key + " musi mieć postać nazwa=wartość")
+				return
+			}
+			m[pair[:i]] = pair[i+1:]
+		}
+	}
+	return
+}